@@ -0,0 +1,18 @@
+package collection
+
+import (
+	. "codeberg.org/yaadata/opt"
+)
+
+// AVLTree is a self-balancing binary [SearchTree] that keeps the heights of
+// every node's subtrees within one of each other. Its height bound is tighter
+// than a [RedBlackTree], which favours lookup-heavy workloads.
+type AVLTree[K any, V any] interface {
+	SearchTree[K, V]
+
+	// Root returns the root node, or None if the tree is empty.
+	Root() Option[AVLTreeNode[K, V]]
+
+	// GetNode returns the node containing the given key, or None if not found.
+	GetNode(key K) Option[AVLTreeNode[K, V]]
+}
@@ -0,0 +1,33 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+)
+
+// AVLTreeNode represents a node in an [AVLTree].
+// Nodes are read-only views and are invalidated by any mutation of the tree.
+type AVLTreeNode[K any, V any] interface {
+	// Key returns the key stored in this node.
+	Key() K
+
+	// Value returns the value stored in this node.
+	Value() V
+
+	// Left returns the left child, or None if there is none.
+	Left() Option[AVLTreeNode[K, V]]
+
+	// Right returns the right child, or None if there is none.
+	Right() Option[AVLTreeNode[K, V]]
+
+	// Children returns an iterator over the existing children, left first.
+	Children() iter.Seq[AVLTreeNode[K, V]]
+
+	// BalanceFactor returns the height of the right subtree minus the height
+	// of the left subtree. It is always -1, 0 or 1.
+	BalanceFactor() int
+
+	// Height returns the height of the subtree rooted at this node. A leaf has height 1.
+	Height() int
+}
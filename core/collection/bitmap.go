@@ -0,0 +1,30 @@
+package collection
+
+import (
+	"encoding"
+
+	. "codeberg.org/yaadata/opt"
+)
+
+// Bitmap is a compressed [Set] of uint32 values kept in ascending order.
+// It supports rank/select queries and a portable binary encoding.
+type Bitmap interface {
+	Set[uint32]
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+
+	// Min returns the smallest value in the bitmap, or None if empty.
+	Min() Option[uint32]
+
+	// Max returns the largest value in the bitmap, or None if empty.
+	Max() Option[uint32]
+
+	// Rank returns the number of values less than or equal to value.
+	Rank(value uint32) int
+
+	// Select returns the value at the given zero-based rank, or None if out of bounds.
+	Select(rank int) Option[uint32]
+
+	// RunOptimize converts containers to run-length encoding where it is smaller.
+	RunOptimize()
+}
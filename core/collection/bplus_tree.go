@@ -0,0 +1,16 @@
+package collection
+
+// BPlusTree is a [SearchTree] that stores entries only in its leaves.
+// Leaves are linked in both directions, so ordered scans descend the tree once
+// and then follow the leaf chain. Only the in-order traversal strategy is
+// supported: internal nodes hold no entries, so [SearchTree.All] yields
+// entries in key order whatever strategy it is given.
+type BPlusTree[K any, V any] interface {
+	SearchTree[K, V]
+
+	// Cursor returns an unpositioned cursor over the leaf chain.
+	Cursor() BPlusTreeCursor[K, V]
+
+	// Order returns the branching factor of the tree.
+	Order() int
+}
@@ -0,0 +1,32 @@
+package collection
+
+import (
+	"codeberg.org/yaadata/bina/core/kv"
+	. "codeberg.org/yaadata/opt"
+)
+
+// BPlusTreeCursor walks the linked leaves of a [BPlusTree] in either direction.
+// A cursor starts unpositioned and becomes unpositioned again when it moves past
+// either end. Mutating the tree invalidates existing cursors.
+type BPlusTreeCursor[K any, V any] interface {
+	// Current returns the entry under the cursor, or None if unpositioned.
+	Current() Option[kv.Pair[K, V]]
+
+	// First moves to the entry with the smallest key, or None if the tree is empty.
+	First() Option[kv.Pair[K, V]]
+
+	// Last moves to the entry with the largest key, or None if the tree is empty.
+	Last() Option[kv.Pair[K, V]]
+
+	// Next moves to the following entry, or None once past the last entry.
+	// An unpositioned cursor moves to the first entry.
+	Next() Option[kv.Pair[K, V]]
+
+	// Prev moves to the preceding entry, or None once past the first entry.
+	// An unpositioned cursor moves to the last entry.
+	Prev() Option[kv.Pair[K, V]]
+
+	// Seek moves to the entry with the smallest key greater than or equal to key,
+	// or None if no such entry exists.
+	Seek(key K) Option[kv.Pair[K, V]]
+}
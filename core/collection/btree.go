@@ -13,4 +13,7 @@ type BTree[K any, V any] interface {
 
 	// Order returns the branching factor of the tree.
 	Order() int
+
+	// Root returns the root node, or None if the tree is empty.
+	Root() Option[BTreeNode[K, V]]
 }
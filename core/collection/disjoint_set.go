@@ -0,0 +1,46 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+)
+
+// DisjointSet, or union-find, partitions its elements into disjoint sets.
+// Each set is identified by one of its elements, its representative. Every
+// operation runs in near-constant amortized time.
+type DisjointSet[T any] interface {
+	Collection[T]
+	Aggregate[T]
+
+	// MakeSet adds element as a set of its own.
+	// Returns false if element is already present.
+	MakeSet(element T) bool
+
+	// Union merges the sets containing a and b, adding either as a set of its
+	// own first if absent. Returns true if two distinct sets were merged.
+	Union(a, b T) bool
+
+	// Find returns the representative of the set containing element, or None
+	// if element is absent. The representative only changes when its set is
+	// merged with another.
+	Find(element T) Option[T]
+
+	// Connected reports whether a and b are present and in the same set.
+	Connected(a, b T) bool
+
+	// SetSize returns the number of elements in the set containing element,
+	// or 0 if element is absent.
+	SetSize(element T) int
+
+	// SetCount returns the number of disjoint sets.
+	SetCount() int
+
+	// Groups returns an iterator over the disjoint sets, ordered by the
+	// earliest added element of each. It runs in O(n).
+	Groups() iter.Seq[Set[T]]
+
+	// Values returns an iterator over the elements in the order they were
+	// added.
+	Values() iter.Seq[T]
+}
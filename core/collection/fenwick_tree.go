@@ -0,0 +1,57 @@
+package collection
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/numeric"
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+// FenwickTree, or binary indexed tree, stores a fixed-length sequence of
+// numbers and maintains prefix sums under point updates in O(log n).
+type FenwickTree[T numeric.Number] interface {
+	// Len returns the number of elements in the tree.
+	Len() int
+
+	// Get returns the element at the given index, or None if out of bounds.
+	Get(index int) Option[T]
+
+	// Add adds delta to the element at the given index, returning false if out of bounds.
+	Add(index int, delta T) bool
+
+	// PrefixSum returns the sum of the elements at indices 0 through index inclusive.
+	// The index is clamped to the bounds of the tree.
+	PrefixSum(index int) T
+
+	// RangeSum returns the sum of the elements whose indices fall within the bounds.
+	RangeSum(opts ...where.WhereOption[int]) T
+
+	// LowerBound returns the smallest index whose prefix sum is at least sum,
+	// or None if the total is smaller. It requires every element to be
+	// non-negative, which makes it suitable for weighted sampling.
+	LowerBound(sum T) Option[int]
+}
+
+// FenwickTree2D is a two-dimensional [FenwickTree] over a fixed grid that
+// maintains rectangle sums under point updates in O(log rows * log cols).
+type FenwickTree2D[T numeric.Number] interface {
+	// Rows returns the number of rows in the grid.
+	Rows() int
+
+	// Cols returns the number of columns in the grid.
+	Cols() int
+
+	// Get returns the element at the given cell, or None if out of bounds.
+	Get(row, col int) Option[T]
+
+	// Add adds delta to the element at the given cell, returning false if out of bounds.
+	Add(row, col int, delta T) bool
+
+	// PrefixSum returns the sum of the rectangle from (0, 0) to (row, col) inclusive.
+	// Indices are clamped to the bounds of the grid.
+	PrefixSum(row, col int) T
+
+	// RangeSum returns the sum of the cells whose row and column indices fall
+	// within the respective bounds. A nil slice leaves that dimension unbounded.
+	RangeSum(rows []where.WhereOption[int], cols []where.WhereOption[int]) T
+}
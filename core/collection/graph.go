@@ -0,0 +1,77 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/edge"
+)
+
+// Graph is a set of vertices joined by weighted edges. Directedness and the
+// handling of parallel edges are fixed when the graph is built. The
+// [Collection] and [Aggregate] methods act on the vertices, which are
+// iterated in the order they were added. Edges and neighbours are iterated
+// in an order that depends on the implementation but not on chance.
+type Graph[V comparable, E any] interface {
+	Collection[V]
+	Aggregate[V]
+
+	// IsDirected reports whether edges have a direction.
+	IsDirected() bool
+
+	// EdgePolicy returns how edges parallel to an existing one are handled.
+	EdgePolicy() GraphEdgePolicy
+
+	// AddVertex adds an isolated vertex.
+	// Returns false if vertex is already present.
+	AddVertex(vertex V) bool
+
+	// AddEdge adds an edge from from to to, adding either vertex first if
+	// absent. Returns false if an edge already joins them and the edge policy
+	// rejects parallel edges.
+	AddEdge(from, to V, weight E) bool
+
+	// RemoveVertex removes vertex and every edge incident to it.
+	// Returns false if vertex is absent.
+	RemoveVertex(vertex V) bool
+
+	// RemoveEdge removes every edge from from to to.
+	// Returns false if there was none.
+	RemoveEdge(from, to V) bool
+
+	// HasEdge reports whether an edge leads from from to to.
+	HasEdge(from, to V) bool
+
+	// Weight returns the weight of the first edge from from to to, or None if
+	// there is none.
+	Weight(from, to V) Option[E]
+
+	// Neighbors returns an iterator over the vertices reachable from vertex
+	// along one edge, paired with the weight of that edge. A vertex joined
+	// by parallel edges is yielded once per edge.
+	Neighbors(vertex V) iter.Seq2[V, E]
+
+	// InDegree returns the number of edges entering vertex, or 0 if vertex is
+	// absent. In an undirected graph it equals OutDegree.
+	InDegree(vertex V) int
+
+	// OutDegree returns the number of edges leaving vertex, or 0 if vertex is
+	// absent. In an undirected graph it is the number of incident edges.
+	OutDegree(vertex V) int
+
+	// EdgeCount returns the number of edges. An undirected edge counts once.
+	EdgeCount() int
+
+	// Vertices returns an iterator over the vertices.
+	Vertices() iter.Seq[V]
+
+	// Edges returns an iterator over the edges. An undirected edge is yielded
+	// once, oriented the way it was added.
+	Edges() iter.Seq[edge.Edge[V, E]]
+
+	// MemoryFootprint returns the approximate number of bytes held by the
+	// graph's own structures. Memory referenced from vertices and weights,
+	// such as the contents of strings, is not counted.
+	MemoryFootprint() int
+}
@@ -0,0 +1,17 @@
+package collection
+
+// GraphEdgePolicy specifies what a [Graph] does when an edge is added
+// between two vertices that are already connected in that direction.
+type GraphEdgePolicy int
+
+const (
+	// GraphEdgePolicyReject keeps the existing edge and rejects the new one.
+	GraphEdgePolicyReject GraphEdgePolicy = iota
+
+	// GraphEdgePolicyReplace overwrites the weight of the existing edge.
+	GraphEdgePolicyReplace
+
+	// GraphEdgePolicyAllow adds the new edge alongside the existing one,
+	// making the graph a multigraph.
+	GraphEdgePolicyAllow
+)
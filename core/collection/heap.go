@@ -0,0 +1,46 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+)
+
+// Heap is a priority queue. The element that orders first under the heap's
+// comparator sits at the top, so a heap using the natural ordering is a
+// min-heap.
+type Heap[T any] interface {
+	Collection[T]
+	Aggregate[T]
+
+	// Push adds an element to the heap.
+	Push(element T)
+
+	// Pop removes and returns the top element, or None if empty.
+	Pop() Option[T]
+
+	// Peek returns the top element without removing it, or None if empty.
+	Peek() Option[T]
+
+	// Values returns an iterator over the elements in no particular order.
+	Values() iter.Seq[T]
+}
+
+// MinMaxHeap is a double-ended priority queue. Both the first and the last
+// element under its comparator can be read in O(1) and removed in O(log n).
+// Pop and Peek act on the first element.
+type MinMaxHeap[T any] interface {
+	Heap[T]
+
+	// PopMin removes and returns the first element, or None if empty.
+	PopMin() Option[T]
+
+	// PopMax removes and returns the last element, or None if empty.
+	PopMax() Option[T]
+
+	// PeekMin returns the first element without removing it, or None if empty.
+	PeekMin() Option[T]
+
+	// PeekMax returns the last element without removing it, or None if empty.
+	PeekMax() Option[T]
+}
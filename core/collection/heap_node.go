@@ -0,0 +1,7 @@
+package collection
+
+// HeapNode is a handle to an element of a [MergeableHeap].
+type HeapNode[T any] interface {
+	// Value returns the element.
+	Value() T
+}
@@ -0,0 +1,23 @@
+package collection
+
+import (
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+// ImplicitTreap is a [DynamicSequence] backed by a treap keyed on position.
+// Insert, RemoveAt, Get, Split, Concat and Reverse all run in expected O(log n),
+// which makes it suitable as a rope-like sequence.
+type ImplicitTreap[T any] interface {
+	DynamicSequence[T]
+
+	// Split keeps the elements before index in this treap and returns the
+	// remaining elements as a new treap. The index is clamped to [0, Len()].
+	Split(index int) ImplicitTreap[T]
+
+	// Concat appends the elements of other to this treap, leaving other empty.
+	Concat(other ImplicitTreap[T])
+
+	// Reverse reverses the order of the elements whose indices fall within the
+	// bounds. Without bounds the whole sequence is reversed.
+	Reverse(opts ...where.WhereOption[int])
+}
@@ -0,0 +1,38 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/interval"
+	"codeberg.org/yaadata/bina/core/kv"
+)
+
+// IntervalTree maps intervals to payloads and finds every stored interval
+// that overlaps a query interval or contains a query point in
+// O(min(n, k log n)), where k is the number of matches.
+type IntervalTree[K any, V any] interface {
+	Collection[interval.Interval[K]]
+	Aggregate[kv.Pair[interval.Interval[K], V]]
+
+	// Insert stores value under the interval, replacing the payload of an identical interval.
+	Insert(iv interval.Interval[K], value V)
+
+	// Delete removes the given interval, returning its payload or None if not found.
+	Delete(iv interval.Interval[K]) Option[V]
+
+	// Get returns the payload of the given interval, or None if not found.
+	Get(iv interval.Interval[K]) Option[V]
+
+	// Overlapping returns an iterator over the stored intervals that share at
+	// least one point with iv, ordered by start point.
+	Overlapping(iv interval.Interval[K]) iter.Seq2[interval.Interval[K], V]
+
+	// Containing returns an iterator over the stored intervals that contain
+	// point, ordered by start point.
+	Containing(point K) iter.Seq2[interval.Interval[K], V]
+
+	// All returns an iterator over every stored interval, ordered by start point.
+	All() iter.Seq2[interval.Interval[K], V]
+}
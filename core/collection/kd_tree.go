@@ -0,0 +1,35 @@
+package collection
+
+import (
+	"iter"
+)
+
+// KDTree partitions points of type P in k dimensions by splitting on one
+// axis per level, answering nearest-neighbour and range queries without
+// scanning every point. Points are identified by their coordinates.
+type KDTree[P any] interface {
+	Collection[P]
+	Aggregate[P]
+
+	// Insert adds a point. Insertion does not rebalance the tree.
+	Insert(point P)
+
+	// Delete removes one point with the same coordinates, returning false if none exists.
+	Delete(point P) bool
+
+	// Height returns the height of the tree.
+	Height() int
+
+	// Nearest returns up to k points closest to point, nearest first.
+	Nearest(point P, k int) []P
+
+	// WithinRadius returns an iterator over the points no farther than radius from point.
+	WithinRadius(point P, radius float64) iter.Seq[P]
+
+	// RangeBox returns an iterator over the points whose every coordinate
+	// lies between the matching coordinates of min and max, inclusive.
+	RangeBox(min, max P) iter.Seq[P]
+
+	// All returns an iterator over every point in the tree.
+	All() iter.Seq[P]
+}
@@ -0,0 +1,25 @@
+package collection
+
+// MergeableHeap is a pointer-based [Heap] that can absorb another heap
+// without copying and can lower an element in place through the handle
+// returned when the element was inserted.
+type MergeableHeap[T any] interface {
+	Heap[T]
+
+	// Insert adds an element and returns a handle to it. The handle stays
+	// valid until the element is popped or the heap is cleared, including
+	// after the heap is melded into another one.
+	Insert(element T) HeapNode[T]
+
+	// DecreaseKey replaces the element behind node with element, which must
+	// not order after it. Returns false, leaving the heap unchanged, if node
+	// is not a live handle of this heap or element orders after the current
+	// value.
+	DecreaseKey(node HeapNode[T], element T) bool
+
+	// Meld moves every element of other into this heap and leaves other
+	// empty. A heap of the same kind is linked in without copying and its
+	// handles now belong to this heap. Any other heap is drained element by
+	// element and its handles become invalid.
+	Meld(other MergeableHeap[T])
+}
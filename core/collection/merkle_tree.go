@@ -0,0 +1,41 @@
+package collection
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/interval"
+)
+
+// MerkleTree is an append-only list of byte slices summarised by a single
+// root hash. Proofs show that a leaf belongs to the tree, or that an older
+// tree is a prefix of the current one, using O(log n) hashes. Every hash
+// returned is a copy the caller may modify.
+type MerkleTree interface {
+	// Len returns the number of leaves.
+	Len() int
+
+	// Append adds a leaf holding data.
+	Append(data []byte)
+
+	// Root returns the root hash of the tree.
+	Root() []byte
+
+	// RootAt returns the root hash the tree had when it held size leaves, or None if size exceeds Len.
+	RootAt(size int) Option[[]byte]
+
+	// LeafHash returns the hash of the leaf at the given index, or None if out of bounds.
+	LeafHash(index int) Option[[]byte]
+
+	// Proof returns the hashes proving that the leaf at the given index
+	// belongs to the tree with the current root, or None if out of bounds.
+	Proof(index int) Option[[][]byte]
+
+	// ConsistencyProof returns the hashes proving that the tree with size
+	// leaves is a prefix of the current tree, or None if size is not in [1, Len].
+	ConsistencyProof(size int) Option[[][]byte]
+
+	// Diff returns the half-open ranges of leaf indices whose hashes differ
+	// from other, including leaves only one of the trees holds. Both trees
+	// must use the same hash function.
+	Diff(other MerkleTree) []interval.Interval[int]
+}
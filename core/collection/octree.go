@@ -0,0 +1,10 @@
+package collection
+
+import (
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// Octree is an [Orthtree] over three-dimensional space that splits full cells into eight octants.
+type Octree[V any] interface {
+	Orthtree[spatial.Point3, spatial.Box, V]
+}
@@ -0,0 +1,44 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+)
+
+// Orthtree partitions a fixed region into equal cells, splitting a cell into
+// one child per orthant once it holds more points than its capacity. P is
+// the point type and R the region type. See [QuadTree] and [Octree].
+type Orthtree[P any, R any, V any] interface {
+	Collection[P]
+	Aggregate[kv.Pair[P, V]]
+
+	// Bounds returns the region covered by the tree.
+	Bounds() R
+
+	// Insert adds a point with its payload, returning false if the point lies outside Bounds.
+	Insert(point P, value V) bool
+
+	// Remove removes one entry at exactly the given point, returning its payload or None if not found.
+	Remove(point P) Option[V]
+
+	// RemoveFunc removes one entry at exactly the given point whose payload
+	// satisfies pred, returning the payload or None if not found.
+	RemoveFunc(point P, pred predicate.Predicate[V]) Option[V]
+
+	// Query returns an iterator over the entries whose points lie within region.
+	Query(region R) iter.Seq2[P, V]
+
+	// Nearest returns up to k entries closest to point, nearest first.
+	Nearest(point P, k int) []kv.Pair[P, V]
+
+	// All returns an iterator over every entry in the tree.
+	All() iter.Seq2[P, V]
+
+	// Leaves returns an iterator over the region of every leaf cell and the
+	// number of entries it holds.
+	Leaves() iter.Seq2[R, int]
+}
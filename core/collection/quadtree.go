@@ -0,0 +1,10 @@
+package collection
+
+import (
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// QuadTree is an [Orthtree] over the plane that splits full cells into four quadrants.
+type QuadTree[V any] interface {
+	Orthtree[spatial.Point, spatial.Rect, V]
+}
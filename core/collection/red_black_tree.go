@@ -0,0 +1,12 @@
+package collection
+
+// RedBlackTree is a self-balancing binary [SearchTree]. Every node is coloured
+// red or black, which keeps the height within twice the optimum and bounds
+// lookups and updates to O(log n) in the worst case.
+type RedBlackTree[K any, V any] interface {
+	SearchTree[K, V]
+
+	// Validate checks the red-black properties and returns an error describing
+	// the first violation found, or nil if the tree is well formed.
+	Validate() error
+}
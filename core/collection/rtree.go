@@ -0,0 +1,44 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// RTree indexes axis-aligned rectangles with payloads by grouping nearby
+// rectangles under shared bounding boxes. The same rectangle may be stored
+// more than once.
+type RTree[V any] interface {
+	Collection[spatial.Rect]
+	Aggregate[kv.Pair[spatial.Rect, V]]
+
+	// Insert adds a rectangle with its payload.
+	Insert(rect spatial.Rect, value V)
+
+	// Delete removes one entry with exactly the given rectangle, returning its payload or None if not found.
+	Delete(rect spatial.Rect) Option[V]
+
+	// DeleteFunc removes one entry with exactly the given rectangle whose
+	// payload satisfies pred, returning the payload or None if not found.
+	DeleteFunc(rect spatial.Rect, pred predicate.Predicate[V]) Option[V]
+
+	// Search returns an iterator over the entries whose rectangles intersect rect.
+	Search(rect spatial.Rect) iter.Seq2[spatial.Rect, V]
+
+	// Nearest returns up to k entries whose rectangles are closest to point, nearest first.
+	Nearest(point spatial.Point, k int) []kv.Pair[spatial.Rect, V]
+
+	// All returns an iterator over every entry in the tree.
+	All() iter.Seq2[spatial.Rect, V]
+
+	// Height returns the number of levels in the tree.
+	Height() int
+
+	// Root returns the root node, or None if the tree is empty.
+	Root() Option[RTreeNode[V]]
+}
@@ -0,0 +1,28 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// RTreeNode represents a node in an [RTree] and the bounding box covering everything below it.
+type RTreeNode[V any] interface {
+	// Bounds returns the smallest rectangle covering every entry below this node.
+	Bounds() spatial.Rect
+
+	// Children returns an iterator over this node's child nodes. Leaves have none.
+	Children() iter.Seq[RTreeNode[V]]
+
+	// Parent returns this node's parent, or None if this is the root.
+	Parent() Option[RTreeNode[V]]
+
+	// IsLeaf reports whether this node stores entries rather than child nodes.
+	IsLeaf() bool
+
+	// Values returns the entries stored in this node. Only leaves store entries.
+	Values() []kv.Pair[spatial.Rect, V]
+}
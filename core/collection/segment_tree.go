@@ -0,0 +1,33 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+// SegmentTree stores a fixed-length sequence of T and answers aggregate
+// queries over index ranges in O(log n). Updates of type U can be applied
+// lazily to whole ranges in O(log n).
+type SegmentTree[T any, U any] interface {
+	// Len returns the number of elements in the tree.
+	Len() int
+
+	// Get returns the element at the given index, or None if out of bounds.
+	Get(index int) Option[T]
+
+	// Set replaces the element at the given index, returning false if out of bounds.
+	Set(index int, value T) bool
+
+	// Query combines the elements whose indices fall within the bounds.
+	// Without bounds the whole sequence is combined; an empty range yields the identity.
+	Query(opts ...where.WhereOption[int]) T
+
+	// Apply applies update to every element whose index falls within the bounds.
+	Apply(update U, opts ...where.WhereOption[int])
+
+	// Values returns an iterator over the elements in index order.
+	Values() iter.Seq[T]
+}
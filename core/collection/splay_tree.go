@@ -0,0 +1,21 @@
+package collection
+
+import (
+	. "codeberg.org/yaadata/opt"
+)
+
+// SplayTree is a self-adjusting binary [SearchTree]. Get, Put and Delete splay
+// the accessed key to the root, so keys that were used recently are cheap to
+// reach again. Operations run in amortized O(log n).
+//
+// Because Get reshapes the tree, reads are mutations: a SplayTree shared
+// between goroutines needs external synchronisation for reads as well as
+// writes. Peek, Contains, Floor, Ceiling, Min, Max, Range and All leave the
+// structure untouched.
+type SplayTree[K any, V any] interface {
+	SearchTree[K, V]
+
+	// Peek returns the value for the given key, or None if not found,
+	// without splaying the tree.
+	Peek(key K) Option[V]
+}
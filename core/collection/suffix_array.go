@@ -0,0 +1,39 @@
+package collection
+
+import (
+	"iter"
+)
+
+// SuffixArray indexes every suffix of a text of symbols T in sorted order,
+// answering substring queries in O(m log n) for a pattern of length m.
+type SuffixArray[T any] interface {
+	// Len returns the length of the indexed text.
+	Len() int
+
+	// Suffixes returns the start positions of the suffixes of the text in sorted order.
+	Suffixes() []int
+
+	// LCP returns the length of the longest common prefix of each suffix in
+	// [SuffixArray.Suffixes] order with the one before it. The first entry is 0.
+	LCP() []int
+
+	// Contains reports whether pattern occurs in the text.
+	Contains(pattern []T) bool
+
+	// Count returns the number of occurrences of pattern in the text. The
+	// empty pattern occurs Len+1 times, once at every position including the
+	// end of the text.
+	Count(pattern []T) int
+
+	// Lookup returns an iterator over the start positions of every
+	// occurrence of pattern, in ascending order. The empty pattern yields
+	// every position from 0 to Len inclusive.
+	Lookup(pattern []T) iter.Seq[int]
+
+	// LongestRepeatedSubstring returns the longest substring occurring at
+	// least twice, possibly overlapping. It is empty if no symbol repeats.
+	LongestRepeatedSubstring() []T
+
+	// LongestCommonSubstring returns the longest substring of both the text and other.
+	LongestCommonSubstring(other []T) []T
+}
@@ -0,0 +1,34 @@
+package collection
+
+import (
+	"iter"
+)
+
+// SuffixTree indexes every suffix of one or more strings of symbols T and
+// grows online: symbols appended to the current string are searchable
+// straight away. Pattern queries run in O(m) for a pattern of length m, plus
+// the number of occurrences reported.
+type SuffixTree[T any] interface {
+	// Len returns the total number of symbols appended across all strings.
+	Len() int
+
+	// Strings returns the number of terminated strings. The string being
+	// appended to has this index.
+	Strings() int
+
+	// Append adds a symbol to the end of the current string.
+	Append(symbol T)
+
+	// Terminate ends the current string and returns its index. Later symbols start a new string.
+	Terminate() int
+
+	// Contains reports whether pattern occurs in any string.
+	Contains(pattern []T) bool
+
+	// Occurrences returns an iterator over the string index and offset of
+	// every occurrence of pattern, ordered by string and then offset.
+	Occurrences(pattern []T) iter.Seq2[int, int]
+
+	// Root returns the root node.
+	Root() SuffixTreeNode[T]
+}
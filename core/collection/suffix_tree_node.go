@@ -0,0 +1,28 @@
+package collection
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/kv"
+)
+
+// SuffixTreeNode represents a node in a [SuffixTree] and the edge leading to it.
+type SuffixTreeNode[T any] interface {
+	// Children returns an iterator over this node's child nodes.
+	Children() iter.Seq[SuffixTreeNode[T]]
+
+	// Parent returns this node's parent, or None if this is the root.
+	Parent() Option[SuffixTreeNode[T]]
+
+	// Label returns the symbols on the edge leading to this node. String
+	// terminators are not included. The root has an empty label.
+	Label() []T
+
+	// IsLeaf reports whether this node ends a suffix.
+	IsLeaf() bool
+
+	// Suffix returns the string index and offset of the suffix ending at this leaf, or None for inner nodes.
+	Suffix() Option[kv.Pair[int, int]]
+}
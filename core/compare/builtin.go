@@ -0,0 +1,9 @@
+package compare
+
+import "cmp"
+
+// Builtin orders values of a [cmp.Ordered] type using their natural ordering.
+// It can be passed anywhere a func(a, b T) Order comparator is expected.
+func Builtin[T cmp.Ordered](a, b T) Order {
+	return Order(cmp.Compare(a, b))
+}
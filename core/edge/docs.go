@@ -0,0 +1,3 @@
+// Package edge
+// Provides the weighted edge type shared by graph collections
+package edge
@@ -0,0 +1,41 @@
+package edge
+
+// Edge represents an immutable weighted connection between two vertices.
+// In an undirected graph From and To are interchangeable.
+type Edge[V any, E any] interface {
+	// From returns the vertex the edge leaves.
+	From() V
+
+	// To returns the vertex the edge enters.
+	To() V
+
+	// Weight returns the weight, or other payload, of the edge.
+	Weight() E
+}
+
+type edge[V any, E any] struct {
+	from   V
+	to     V
+	weight E
+}
+
+// New creates a new edge.
+func New[V any, E any](from, to V, weight E) Edge[V, E] {
+	return &edge[V, E]{
+		from:   from,
+		to:     to,
+		weight: weight,
+	}
+}
+
+func (e *edge[V, E]) From() V {
+	return e.from
+}
+
+func (e *edge[V, E]) To() V {
+	return e.to
+}
+
+func (e *edge[V, E]) Weight() E {
+	return e.weight
+}
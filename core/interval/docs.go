@@ -0,0 +1,5 @@
+// Package interval defines spans between two endpoints of an ordered type.
+// Each endpoint follows the inclusive/exclusive conventions of [where.Bound].
+package interval
+
+import _ "codeberg.org/yaadata/bina/core/where"
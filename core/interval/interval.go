@@ -0,0 +1,113 @@
+package interval
+
+import (
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+// Interval is a span between a start and an end point. Each point carries a
+// [where.Bound] stating whether the point itself belongs to the interval.
+type Interval[K any] struct {
+	start      K
+	startBound where.Bound
+	end        K
+	endBound   where.Bound
+}
+
+// New returns the half-open interval [start, end).
+func New[K any](start, end K) Interval[K] {
+	return WithBounds(start, where.BoundInclusive, end, where.BoundExclusive)
+}
+
+// Closed returns the closed interval [start, end].
+func Closed[K any](start, end K) Interval[K] {
+	return WithBounds(start, where.BoundInclusive, end, where.BoundInclusive)
+}
+
+// Open returns the open interval (start, end).
+func Open[K any](start, end K) Interval[K] {
+	return WithBounds(start, where.BoundExclusive, end, where.BoundExclusive)
+}
+
+// Point returns the closed interval [point, point].
+func Point[K any](point K) Interval[K] {
+	return Closed(point, point)
+}
+
+// WithBounds returns the interval between start and end with the given bounds.
+func WithBounds[K any](start K, startBound where.Bound, end K, endBound where.Bound) Interval[K] {
+	return Interval[K]{
+		start:      start,
+		startBound: startBound,
+		end:        end,
+		endBound:   endBound,
+	}
+}
+
+// Start returns the start point and its bound type.
+func (i Interval[K]) Start() kv.Pair[K, where.Bound] {
+	return kv.New(i.start, i.startBound)
+}
+
+// End returns the end point and its bound type.
+func (i Interval[K]) End() kv.Pair[K, where.Bound] {
+	return kv.New(i.end, i.endBound)
+}
+
+// IsEmpty reports whether no point lies within the interval, ordering points with fn.
+func (i Interval[K]) IsEmpty(fn func(a, b K) compare.Order) bool {
+	return !reaches(i.start, i.startBound, i.end, i.endBound, fn)
+}
+
+// Contains reports whether point lies within the interval, ordering points with fn.
+func (i Interval[K]) Contains(point K, fn func(a, b K) compare.Order) bool {
+	return i.Overlaps(Point(point), fn)
+}
+
+// Overlaps reports whether the interval shares at least one point with
+// other, ordering points with fn. Empty intervals overlap nothing.
+func (i Interval[K]) Overlaps(other Interval[K], fn func(a, b K) compare.Order) bool {
+	return !i.IsEmpty(fn) && !other.IsEmpty(fn) &&
+		reaches(i.start, i.startBound, other.end, other.endBound, fn) &&
+		reaches(other.start, other.startBound, i.end, i.endBound, fn)
+}
+
+// Compare orders intervals by their start points and then by their end
+// points, ordering points with fn. An inclusive start sorts before an
+// exclusive one at the same point, and an exclusive end sorts before an
+// inclusive one.
+func Compare[K any](a, b Interval[K], fn func(a, b K) compare.Order) compare.Order {
+	if order := CompareStart(a, b, fn); !order.IsEqual() {
+		return order
+	}
+	return CompareEnd(a, b, fn)
+}
+
+// CompareStart orders intervals by their start points, ordering points with fn.
+func CompareStart[K any](a, b Interval[K], fn func(a, b K) compare.Order) compare.Order {
+	if order := fn(a.start, b.start); !order.IsEqual() {
+		return order
+	}
+	// An inclusive start admits the point itself and so begins earlier
+	return compare.Order(a.startBound - b.startBound)
+}
+
+// CompareEnd orders intervals by their end points, ordering points with fn.
+func CompareEnd[K any](a, b Interval[K], fn func(a, b K) compare.Order) compare.Order {
+	if order := fn(a.end, b.end); !order.IsEqual() {
+		return order
+	}
+	// An inclusive end admits the point itself and so finishes later
+	return compare.Order(b.endBound - a.endBound)
+}
+
+// reaches reports whether some point satisfies both the start and the end
+// bound.
+func reaches[K any](start K, startBound where.Bound, end K, endBound where.Bound, fn func(a, b K) compare.Order) bool {
+	order := fn(start, end)
+	if order.IsEqual() {
+		return startBound == where.BoundInclusive && endBound == where.BoundInclusive
+	}
+	return order.IsLess()
+}
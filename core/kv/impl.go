@@ -6,7 +6,7 @@ type pair[K any, V any] struct {
 }
 
 // New creates a new key-value pair.
-func New[K comparable, V any](key K, value V) Pair[K, V] {
+func New[K any, V any](key K, value V) Pair[K, V] {
 	return &pair[K, V]{
 		key:   key,
 		value: value,
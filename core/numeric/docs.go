@@ -0,0 +1,3 @@
+// Package numeric defines type constraints for collections that perform
+// arithmetic on their elements.
+package numeric
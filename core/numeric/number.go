@@ -0,0 +1,17 @@
+package numeric
+
+// Integer is satisfied by every built-in signed and unsigned integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Float is satisfied by every built-in floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is satisfied by every built-in integer and floating-point type.
+type Number interface {
+	Integer | Float
+}
@@ -0,0 +1,59 @@
+package spatial
+
+import (
+	"math"
+)
+
+// Point3 is a location in three-dimensional space.
+type Point3 struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// Box is an axis-aligned box spanning Min to Max, inclusive of its faces.
+type Box struct {
+	Min Point3
+	Max Point3
+}
+
+// NewBox returns the box with corners a and b in any order.
+func NewBox(a, b Point3) Box {
+	return Box{
+		Min: Point3{X: min(a.X, b.X), Y: min(a.Y, b.Y), Z: min(a.Z, b.Z)},
+		Max: Point3{X: max(a.X, b.X), Y: max(a.Y, b.Y), Z: max(a.Z, b.Z)},
+	}
+}
+
+// Volume returns the volume of the box.
+func (b Box) Volume() float64 {
+	return (b.Max.X - b.Min.X) * (b.Max.Y - b.Min.Y) * (b.Max.Z - b.Min.Z)
+}
+
+// Center returns the midpoint of the box.
+func (b Box) Center() Point3 {
+	return Point3{X: (b.Min.X + b.Max.X) / 2, Y: (b.Min.Y + b.Max.Y) / 2, Z: (b.Min.Z + b.Max.Z) / 2}
+}
+
+// Contains reports whether p lies inside the box or on its faces.
+func (b Box) Contains(p Point3) bool {
+	return b.Min.X <= p.X && p.X <= b.Max.X &&
+		b.Min.Y <= p.Y && p.Y <= b.Max.Y &&
+		b.Min.Z <= p.Z && p.Z <= b.Max.Z
+}
+
+// Intersects reports whether the boxes share at least one point.
+func (b Box) Intersects(other Box) bool {
+	return b.Min.X <= other.Max.X && other.Min.X <= b.Max.X &&
+		b.Min.Y <= other.Max.Y && other.Min.Y <= b.Max.Y &&
+		b.Min.Z <= other.Max.Z && other.Min.Z <= b.Max.Z
+}
+
+// Distance returns the Euclidean distance from p to the nearest point of the
+// box, or 0 if the box contains p.
+func (b Box) Distance(p Point3) float64 {
+	dx := max(b.Min.X-p.X, 0, p.X-b.Max.X)
+	dy := max(b.Min.Y-p.Y, 0, p.Y-b.Max.Y)
+	dz := max(b.Min.Z-p.Z, 0, p.Z-b.Max.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
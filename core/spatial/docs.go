@@ -0,0 +1,3 @@
+// Package spatial defines points, axis-aligned rectangles and boxes for
+// spatial collections.
+package spatial
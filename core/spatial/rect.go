@@ -0,0 +1,75 @@
+package spatial
+
+import (
+	"math"
+)
+
+// Point is a location in the plane.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Rect is an axis-aligned rectangle spanning Min to Max, inclusive of its
+// edges. A rectangle with Min == Max is a single point.
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+// NewRect returns the rectangle with corners (x1, y1) and (x2, y2) in any order.
+func NewRect(x1, y1, x2, y2 float64) Rect {
+	return Rect{
+		Min: Point{X: min(x1, x2), Y: min(y1, y2)},
+		Max: Point{X: max(x1, x2), Y: max(y1, y2)},
+	}
+}
+
+// PointRect returns the degenerate rectangle covering only p.
+func PointRect(p Point) Rect {
+	return Rect{Min: p, Max: p}
+}
+
+// Area returns the area of the rectangle.
+func (r Rect) Area() float64 {
+	return (r.Max.X - r.Min.X) * (r.Max.Y - r.Min.Y)
+}
+
+// Center returns the midpoint of the rectangle.
+func (r Rect) Center() Point {
+	return Point{X: (r.Min.X + r.Max.X) / 2, Y: (r.Min.Y + r.Max.Y) / 2}
+}
+
+// Contains reports whether p lies inside the rectangle or on its edges.
+func (r Rect) Contains(p Point) bool {
+	return r.Min.X <= p.X && p.X <= r.Max.X &&
+		r.Min.Y <= p.Y && p.Y <= r.Max.Y
+}
+
+// ContainsRect reports whether other lies entirely inside the rectangle.
+func (r Rect) ContainsRect(other Rect) bool {
+	return r.Contains(other.Min) && r.Contains(other.Max)
+}
+
+// Intersects reports whether the rectangles share at least one point.
+// Rectangles that only touch along an edge intersect.
+func (r Rect) Intersects(other Rect) bool {
+	return r.Min.X <= other.Max.X && other.Min.X <= r.Max.X &&
+		r.Min.Y <= other.Max.Y && other.Min.Y <= r.Max.Y
+}
+
+// Union returns the smallest rectangle covering both rectangles.
+func (r Rect) Union(other Rect) Rect {
+	return Rect{
+		Min: Point{X: min(r.Min.X, other.Min.X), Y: min(r.Min.Y, other.Min.Y)},
+		Max: Point{X: max(r.Max.X, other.Max.X), Y: max(r.Max.Y, other.Max.Y)},
+	}
+}
+
+// Distance returns the Euclidean distance from p to the nearest point of the
+// rectangle, or 0 if the rectangle contains p.
+func (r Rect) Distance(p Point) float64 {
+	dx := max(r.Min.X-p.X, 0, p.X-r.Max.X)
+	dy := max(r.Min.Y-p.Y, 0, p.Y-r.Max.Y)
+	return math.Hypot(dx, dy)
+}
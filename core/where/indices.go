@@ -0,0 +1,30 @@
+package where
+
+// Indices resolves a range over int positions into the half-open interval
+// [start, end) of a sequence with the given length. Unset bounds default to
+// the ends of the sequence and the result is clamped to [0, length]; an empty
+// range has start == end.
+func Indices(length int, opts ...WhereOption[int]) (int, int) {
+	wh := Default[int]()
+	for _, opt := range opts {
+		opt(wh)
+	}
+	start, end := 0, length
+	// Bounds are clamped to length before stepping past them, so that
+	// math.MaxInt cannot wrap around
+	if wh.from.IsSome() {
+		start = min(wh.from.Unwrap(), length)
+		if wh.fromBound == BoundExclusive && start < length {
+			start++
+		}
+	}
+	if wh.to.IsSome() {
+		end = min(wh.to.Unwrap(), length)
+		if wh.toBound == BoundInclusive && end < length {
+			end++
+		}
+	}
+	start = min(max(start, 0), length)
+	end = min(max(end, start), length)
+	return start, end
+}
@@ -1,6 +1,7 @@
 package where
 
 import (
+	"codeberg.org/yaadata/bina/core/compare"
 	"codeberg.org/yaadata/bina/core/kv"
 	. "codeberg.org/yaadata/opt"
 )
@@ -31,3 +32,34 @@ func (c *Where[K]) From() kv.Pair[Option[K], Bound] {
 func (c *Where[K]) To() kv.Pair[Option[K], Bound] {
 	return kv.New(c.to, c.toBound)
 }
+
+// AdmitsFrom reports whether key satisfies the start bound, ordering keys with fn.
+// An unbounded start admits every key.
+func (c *Where[K]) AdmitsFrom(key K, fn func(a, b K) compare.Order) bool {
+	if c.from.IsNone() {
+		return true
+	}
+	order := fn(key, c.from.Unwrap())
+	if c.fromBound == BoundExclusive {
+		return order.IsGreater()
+	}
+	return order.IsGreaterThanOrEqualTo()
+}
+
+// AdmitsTo reports whether key satisfies the end bound, ordering keys with fn.
+// An unbounded end admits every key.
+func (c *Where[K]) AdmitsTo(key K, fn func(a, b K) compare.Order) bool {
+	if c.to.IsNone() {
+		return true
+	}
+	order := fn(key, c.to.Unwrap())
+	if c.toBound == BoundExclusive {
+		return order.IsLess()
+	}
+	return order.IsLessThanOrEqualTo()
+}
+
+// Admits reports whether key lies within the range, ordering keys with fn.
+func (c *Where[K]) Admits(key K, fn func(a, b K) compare.Order) bool {
+	return c.AdmitsFrom(key, fn) && c.AdmitsTo(key, fn)
+}
@@ -20,3 +20,19 @@ func To[K any](end K) WhereOption[K] {
 		w.to = Some(end)
 	}
 }
+
+// FromExclusive sets the start point of the range, excluding the point itself.
+func FromExclusive[K any](from K) WhereOption[K] {
+	return func(ranger *Where[K]) {
+		ranger.from = Some(from)
+		ranger.fromBound = BoundExclusive
+	}
+}
+
+// ToExclusive sets the end point of the range, excluding the point itself.
+func ToExclusive[K any](end K) WhereOption[K] {
+	return func(w *Where[K]) {
+		w.to = Some(end)
+		w.toBound = BoundExclusive
+	}
+}
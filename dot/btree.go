@@ -0,0 +1,58 @@
+package dot
+
+import (
+	"strings"
+
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// BTree renders the subtree under root, usually [collection.BTree.Root], as
+// a directed graph of record nodes with one field per key, children in
+// order. Labels apply to keys. Each highlighted key marks the nodes and
+// edges from root down to the node holding it.
+func BTree[K comparable, V any](root collection.BTreeNode[K, V], opts ...RenderOption[K]) string {
+	cfg := configure(opts)
+	w := newWriter(true, "node [shape=record]")
+	if root != nil {
+		var ids int
+		layout(cfg, root, &ids).write(w)
+	}
+	return w.String()
+}
+
+// treeNode is a B-tree node laid out for rendering. highlighted is set if
+// the node's subtree holds a highlighted key.
+type treeNode struct {
+	id          int
+	label       string
+	highlighted bool
+	children    []*treeNode
+}
+
+// layout numbers the subtree under node in preorder, starting from *ids.
+func layout[K comparable, V any](cfg *RenderConfiguration[K], node collection.BTreeNode[K, V], ids *int) *treeNode {
+	t := &treeNode{id: *ids}
+	*ids++
+	fields := make([]string, 0, len(node.Values()))
+	for _, pair := range node.Values() {
+		fields = append(fields, escapeRecord(cfg.labelOf(pair.Key())))
+		t.highlighted = t.highlighted || cfg.highlights(pair.Key())
+	}
+	t.label = strings.Join(fields, " | ")
+	for child := range node.Children() {
+		c := layout(cfg, child, ids)
+		t.highlighted = t.highlighted || c.highlighted
+		t.children = append(t.children, c)
+	}
+	return t
+}
+
+func (t *treeNode) write(w *writer) {
+	w.node(t.id, t.label, t.highlighted)
+	for _, c := range t.children {
+		w.edge(t.id, c.id, c.highlighted)
+	}
+	for _, c := range t.children {
+		c.write(w)
+	}
+}
@@ -0,0 +1,12 @@
+// Package dot renders node-based structures in the Graphviz DOT language:
+// B-tree nodes, linked lists of every kind and graphs. The output is
+// deterministic, so it can be pasted into documents or compared in tests,
+// and renders with any Graphviz tool, for example
+//
+//	dot -Tsvg tree.dot > tree.svg
+//
+// Every renderer accepts [RenderOption] values to change how elements are
+// labelled and to highlight a path through the structure.
+package dot
+
+import _ "codeberg.org/yaadata/bina/core/collection"
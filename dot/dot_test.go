@@ -0,0 +1,237 @@
+package dot_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/dot"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	"codeberg.org/yaadata/bina/graph/algo"
+	circularlinkedlist "codeberg.org/yaadata/bina/sequence/circular_linked_list"
+	doublylinkedlist "codeberg.org/yaadata/bina/sequence/doubly_linked_list"
+	linkedlist "codeberg.org/yaadata/bina/sequence/linked_list"
+	"codeberg.org/yaadata/bina/tree/btree"
+)
+
+// lines joins lines into a DOT document.
+func lines(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestBTree(t *testing.T) {
+	// ========= [A]rrange =========
+	var pairs []kv.Pair[int, string]
+	for key := 10; key <= 100; key += 10 {
+		pairs = append(pairs, kv.New(key, fmt.Sprint(key)))
+	}
+	tree := btree.NewBuiltinBuilder[int, string]().
+		Order(3).
+		From(pairs...).
+		Build()
+
+	t.Run("Renders the tree", func(t *testing.T) {
+		// ========= [A]ct     =========
+		actual := dot.BTree(tree.Root().Unwrap())
+		// ========= [A]ssert  =========
+		must.Eq(t, lines(
+			"digraph {",
+			"\tnode [shape=record];",
+			`	n0 [label="30 | 60"];`,
+			"\tn0 -> n1;",
+			"\tn0 -> n2;",
+			"\tn0 -> n3;",
+			`	n1 [label="10 | 20"];`,
+			`	n2 [label="40 | 50"];`,
+			`	n3 [label="70 | 80 | 90 | 100"];`,
+			"}",
+		), actual)
+	})
+
+	t.Run("Highlights the path to a key", func(t *testing.T) {
+		// ========= [A]ct     =========
+		actual := dot.BTree(tree.Root().Unwrap(),
+			dot.WithHighlight(50),
+			dot.WithLabel(func(key int) string { return fmt.Sprintf("{k%d}", key) }),
+		)
+		// ========= [A]ssert  =========
+		must.Eq(t, lines(
+			"digraph {",
+			"\tnode [shape=record];",
+			`	n0 [label="\{k30\} | \{k60\}", color="red", penwidth=2];`,
+			"\tn0 -> n1;",
+			`	n0 -> n2 [color="red", penwidth=2];`,
+			"\tn0 -> n3;",
+			`	n1 [label="\{k10\} | \{k20\}"];`,
+			`	n2 [label="\{k40\} | \{k50\}", color="red", penwidth=2];`,
+			`	n3 [label="\{k70\} | \{k80\} | \{k90\} | \{k100\}"];`,
+			"}",
+		), actual)
+	})
+
+	t.Run("Renders a subtree", func(t *testing.T) {
+		// ========= [A]ct     =========
+		actual := dot.BTree(tree.GetNode(40).Unwrap())
+		// ========= [A]ssert  =========
+		must.Eq(t, lines(
+			"digraph {",
+			"\tnode [shape=record];",
+			`	n0 [label="40 | 50"];`,
+			"}",
+		), actual)
+	})
+
+	t.Run("Empty tree has no root", func(t *testing.T) {
+		// ========= [A]rrange =========
+		empty := btree.NewBuiltinBuilder[int, string]().Build()
+		// ========= [A]ssert  =========
+		must.True(t, empty.Root().IsNone())
+	})
+}
+
+func TestLinkedList(t *testing.T) {
+	// SCENARIO: singly linked
+	t.Run("Singly linked", func(t *testing.T) {
+		// ========= [A]rrange =========
+		list := linkedlist.NewBuiltinBuilder[string]().From("a", "b", "c").Build()
+		// ========= [A]ct     =========
+		actual := dot.LinkedList(list, dot.WithHighlight("b"))
+		// ========= [A]ssert  =========
+		must.Eq(t, lines(
+			"digraph {",
+			"\trankdir=LR;",
+			"\tnode [shape=box];",
+			`	n0 [label="a", color="red", penwidth=2];`,
+			`	n1 [label="b", color="red", penwidth=2];`,
+			`	n2 [label="c"];`,
+			`	n0 -> n1 [color="red", penwidth=2];`,
+			"\tn1 -> n2;",
+			"}",
+		), actual)
+	})
+
+	// SCENARIO: doubly linked
+	t.Run("Doubly linked", func(t *testing.T) {
+		// ========= [A]rrange =========
+		list := doublylinkedlist.NewBuiltinBuilder[int]().From(1, 2, 3).Build()
+		// ========= [A]ct     =========
+		actual := dot.LinkedList(list, dot.WithLabel(func(v int) string {
+			return fmt.Sprintf("#%d", v)
+		}))
+		// ========= [A]ssert  =========
+		must.Eq(t, lines(
+			"digraph {",
+			"\trankdir=LR;",
+			"\tnode [shape=box];",
+			`	n0 [label="#1"];`,
+			`	n1 [label="#2"];`,
+			`	n2 [label="#3"];`,
+			"\tn0 -> n1 [dir=both];",
+			"\tn1 -> n2 [dir=both];",
+			"}",
+		), actual)
+	})
+
+	// SCENARIO: circular
+	t.Run("Circular", func(t *testing.T) {
+		// ========= [A]rrange =========
+		list := circularlinkedlist.NewBuiltinBuilder[string]().From("a", "b", "c").Build()
+		// ========= [A]ct     =========
+		actual := dot.LinkedList(list, dot.WithHighlight("c", "a"))
+		// ========= [A]ssert  =========
+		must.Eq(t, lines(
+			"digraph {",
+			"\trankdir=LR;",
+			"\tnode [shape=box];",
+			`	n0 [label="a", color="red", penwidth=2];`,
+			`	n1 [label="b", color="red", penwidth=2];`,
+			`	n2 [label="c", color="red", penwidth=2];`,
+			`	n0 -> n1 [dir=both, color="red", penwidth=2];`,
+			`	n1 -> n2 [dir=both, color="red", penwidth=2];`,
+			"\tn2 -> n0 [dir=both];",
+			"}",
+		), actual)
+	})
+
+	// SCENARIO: empty
+	t.Run("Empty", func(t *testing.T) {
+		// ========= [A]rrange =========
+		list := linkedlist.NewBuiltinBuilder[string]().Build()
+		// ========= [A]ct     =========
+		actual := dot.LinkedList(list, dot.WithHighlight("missing"))
+		// ========= [A]ssert  =========
+		must.Eq(t, lines("digraph {", "\trankdir=LR;", "\tnode [shape=box];", "}"), actual)
+	})
+
+	t.Run("Escapes labels", func(t *testing.T) {
+		// ========= [A]rrange =========
+		list := linkedlist.NewBuiltinBuilder[string]().From(`say "hi"`, "two\nlines", `C:\`).Build()
+		// ========= [A]ct     =========
+		actual := dot.LinkedList(list)
+		// ========= [A]ssert  =========
+		must.StrContains(t, actual, `n0 [label="say \"hi\""];`)
+		must.StrContains(t, actual, `n1 [label="two\nlines"];`)
+		must.StrContains(t, actual, `n2 [label="C:\\"];`)
+	})
+}
+
+func TestGraph(t *testing.T) {
+	t.Run("Renders a directed graph with a highlighted path", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := adjacencylist.NewBuilder[string, int]().
+			Directed().
+			From(
+				edge.New("a", "b", 1),
+				edge.New("b", "c", 2),
+				edge.New("a", "c", 5),
+			).
+			Build()
+		path := algo.Dijkstra(g, "a").Path("c").Unwrap()
+		// ========= [A]ct     =========
+		actual := dot.Graph(g, dot.WithHighlight(slices.Collect(path.Vertices.Values())...))
+		// ========= [A]ssert  =========
+		must.Eq(t, lines(
+			"digraph {",
+			`	n0 [label="a", color="red", penwidth=2];`,
+			`	n1 [label="b", color="red", penwidth=2];`,
+			`	n2 [label="c", color="red", penwidth=2];`,
+			`	n0 -> n1 [label="1", color="red", penwidth=2];`,
+			`	n0 -> n2 [label="5"];`,
+			`	n1 -> n2 [label="2", color="red", penwidth=2];`,
+			"}",
+		), actual)
+	})
+
+	t.Run("Renders an undirected graph", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := adjacencylist.NewBuilder[int, float64]().
+			EdgePolicy(collection.GraphEdgePolicyAllow).
+			Vertices(3).
+			From(
+				edge.New(1, 2, 0.5),
+				edge.New(2, 1, 1.5),
+			).
+			Build()
+		// ========= [A]ct     =========
+		actual := dot.Graph(g,
+			dot.WithHighlight(1, 2),
+			dot.WithLabel(func(v int) string { return fmt.Sprintf("v%d", v) }),
+		)
+		// ========= [A]ssert  =========
+		must.Eq(t, lines(
+			"graph {",
+			`	n0 [label="v3"];`,
+			`	n1 [label="v1", color="red", penwidth=2];`,
+			`	n2 [label="v2", color="red", penwidth=2];`,
+			`	n1 -- n2 [label="0.5", color="red", penwidth=2];`,
+			`	n2 -- n1 [label="1.5", color="red", penwidth=2];`,
+			"}",
+		), actual)
+	})
+}
@@ -0,0 +1,38 @@
+package dot
+
+import (
+	"fmt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// Graph renders g, as a digraph if it is directed, with vertices in
+// iteration order and each edge labelled with its weight. Labels apply to
+// vertices. Every highlighted vertex is marked, as is every edge leading
+// from one highlighted vertex to the next, so a path found by the graph/algo
+// package can be shown in place.
+func Graph[V comparable, E any](g collection.Graph[V, E], opts ...RenderOption[V]) string {
+	cfg := configure(opts)
+	w := newWriter(g.IsDirected())
+	ids := make(map[V]int, g.Len())
+	for vertex := range g.Vertices() {
+		ids[vertex] = len(ids)
+		w.node(ids[vertex], escape(cfg.labelOf(vertex)), cfg.highlights(vertex))
+	}
+	type step struct {
+		from, to V
+	}
+	steps := make(map[step]bool)
+	path := cfg.path()
+	for i := 1; i < len(path); i++ {
+		steps[step{path[i-1], path[i]}] = true
+		if !g.IsDirected() {
+			steps[step{path[i], path[i-1]}] = true
+		}
+	}
+	for e := range g.Edges() {
+		label := fmt.Sprintf(`label="%s"`, escape(fmt.Sprint(e.Weight())))
+		w.edge(ids[e.From()], ids[e.To()], steps[step{e.From(), e.To()}], label)
+	}
+	return w.String()
+}
@@ -0,0 +1,82 @@
+package dot
+
+import (
+	. "codeberg.org/yaadata/opt"
+	"codeberg.org/yaadata/opt/extension"
+
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// LinkedList renders list as a chain of nodes from left to right, following
+// Next from the head. A Next link whose target links back through Previous,
+// as in doubly linked lists, is drawn as a single two-headed edge. In a
+// circular list the link from the tail back to the head is drawn and the
+// walk stops there. Each highlighted value marks the nodes and edges from
+// the head to the first node holding it.
+func LinkedList[T comparable, Node collection.LinkedListNode[T]](list collection.LinkedList[T, Node], opts ...RenderOption[T]) string {
+	cfg := configure(opts)
+	w := newWriter(true, "rankdir=LR", "node [shape=box]")
+	ids := make(map[any]int)
+	var nodes []collection.LinkedListNode[T]
+	head := extension.OptionMap(list.Head(), func(n Node) collection.LinkedListNode[T] {
+		return n
+	})
+	for node := head; node.IsSome(); node = next(node.Unwrap()) {
+		if _, seen := ids[node.Unwrap()]; seen {
+			break
+		}
+		ids[node.Unwrap()] = len(nodes)
+		nodes = append(nodes, node.Unwrap())
+	}
+	last := -1
+	for _, value := range cfg.path() {
+		for i, node := range nodes {
+			if node.Value() == value {
+				last = max(last, i)
+				break
+			}
+		}
+	}
+	for i, node := range nodes {
+		w.node(i, escape(cfg.labelOf(node.Value())), i <= last)
+	}
+	for i, node := range nodes {
+		following := next(node)
+		if following.IsNone() {
+			continue
+		}
+		j := ids[following.Unwrap()]
+		var attributes []string
+		if linksBack(following.Unwrap(), node) {
+			attributes = append(attributes, "dir=both")
+		}
+		w.edge(i, j, j == i+1 && j <= last, attributes...)
+	}
+	return w.String()
+}
+
+// next returns the node after node, whichever kind of node it is.
+func next[T any](node collection.LinkedListNode[T]) Option[collection.LinkedListNode[T]] {
+	switch n := node.(type) {
+	case collection.DoublyLinkedListNode[T]:
+		return extension.OptionMap(n.Next(), func(n collection.DoublyLinkedListNode[T]) collection.LinkedListNode[T] {
+			return n
+		})
+	case collection.SinglyLinkedListNode[T]:
+		return extension.OptionMap(n.Next(), func(n collection.SinglyLinkedListNode[T]) collection.LinkedListNode[T] {
+			return n
+		})
+	default:
+		return None[collection.LinkedListNode[T]]()
+	}
+}
+
+// linksBack reports whether node has a Previous link to previous.
+func linksBack[T any](node, previous collection.LinkedListNode[T]) bool {
+	n, ok := node.(collection.DoublyLinkedListNode[T])
+	if !ok {
+		return false
+	}
+	back := n.Previous()
+	return back.IsSome() && collection.LinkedListNode[T](back.Unwrap()) == previous
+}
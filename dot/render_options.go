@@ -0,0 +1,62 @@
+package dot
+
+import (
+	"fmt"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+)
+
+// RenderConfiguration holds options for rendering a structure whose
+// elements are of type T.
+type RenderConfiguration[T comparable] struct {
+	label     Option[func(T) string]
+	highlight Option[[]T]
+}
+
+// RenderOption configures rendering.
+type RenderOption[T comparable] func(cfg *RenderConfiguration[T])
+
+// WithLabel returns an option that labels each element with fn. By default
+// elements are labelled with their default format, as fmt.Sprint does.
+func WithLabel[T comparable](fn func(element T) string) RenderOption[T] {
+	return func(cfg *RenderConfiguration[T]) {
+		cfg.label = Some(fn)
+	}
+}
+
+// WithHighlight returns an option that highlights a path through the
+// structure. What the path covers depends on the renderer: in a B-tree or a
+// linked list each element marks the way from the root or head to the node
+// holding it, in a graph consecutive vertices mark the edges between them.
+func WithHighlight[T comparable](path ...T) RenderOption[T] {
+	return func(cfg *RenderConfiguration[T]) {
+		cfg.highlight = Some(path)
+	}
+}
+
+func configure[T comparable](opts []RenderOption[T]) *RenderConfiguration[T] {
+	cfg := &RenderConfiguration[T]{
+		label:     None[func(T) string](),
+		highlight: None[[]T](),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+func (cfg *RenderConfiguration[T]) labelOf(element T) string {
+	if cfg.label.IsSome() {
+		return cfg.label.Unwrap()(element)
+	}
+	return fmt.Sprint(element)
+}
+
+func (cfg *RenderConfiguration[T]) path() []T {
+	return cfg.highlight.UnwrapOrDefault()
+}
+
+func (cfg *RenderConfiguration[T]) highlights(element T) bool {
+	return slices.Contains(cfg.path(), element)
+}
@@ -0,0 +1,70 @@
+package dot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// highlight holds the attributes added to highlighted nodes and edges.
+const highlight = `color="red", penwidth=2`
+
+// writer accumulates a DOT graph. Nodes are named n0, n1, ... by the caller.
+type writer struct {
+	b        strings.Builder
+	directed bool
+}
+
+// newWriter starts a graph with the given graph-level statements, such as
+// "rankdir=LR".
+func newWriter(directed bool, statements ...string) *writer {
+	w := &writer{directed: directed}
+	if directed {
+		w.b.WriteString("digraph {\n")
+	} else {
+		w.b.WriteString("graph {\n")
+	}
+	for _, statement := range statements {
+		fmt.Fprintf(&w.b, "\t%s;\n", statement)
+	}
+	return w
+}
+
+// node declares node id with a label already escaped for a DOT string.
+func (w *writer) node(id int, label string, highlighted bool) {
+	fmt.Fprintf(&w.b, "\tn%d [label=\"%s\"", id, label)
+	if highlighted {
+		w.b.WriteString(", " + highlight)
+	}
+	w.b.WriteString("];\n")
+}
+
+// edge declares an edge between two nodes with optional extra attributes.
+func (w *writer) edge(from, to int, highlighted bool, attributes ...string) {
+	op := "--"
+	if w.directed {
+		op = "->"
+	}
+	fmt.Fprintf(&w.b, "\tn%d %s n%d", from, op, to)
+	if highlighted {
+		attributes = append(attributes, highlight)
+	}
+	if len(attributes) > 0 {
+		fmt.Fprintf(&w.b, " [%s]", strings.Join(attributes, ", "))
+	}
+	w.b.WriteString(";\n")
+}
+
+func (w *writer) String() string {
+	return w.b.String() + "}\n"
+}
+
+// escape returns s escaped for use inside a DOT string, so that it shows
+// as written.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// escapeRecord returns s escaped for use as a field of a record label.
+func escapeRecord(s string) string {
+	return strings.NewReplacer(`{`, `\{`, `}`, `\}`, `|`, `\|`, `<`, `\<`, `>`, `\>`).Replace(escape(s))
+}
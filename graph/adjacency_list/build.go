@@ -0,0 +1,78 @@
+package adjacencylist
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	adjacencylist "codeberg.org/yaadata/bina/internal/adjacency_list"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.Graph] backed by adjacency lists.
+func NewBuilder[V comparable, E any]() Builder[V, E, collection.Graph[V, E], *build[V, E]] {
+	return &build[V, E]{
+		directed: false,
+		policy:   None[collection.GraphEdgePolicy](),
+		from:     None[[]edge.Edge[V, E]](),
+		vertices: None[[]V](),
+	}
+}
+
+type build[V comparable, E any] struct {
+	directed bool
+	policy   Option[collection.GraphEdgePolicy]
+	from     Option[[]edge.Edge[V, E]]
+	vertices Option[[]V]
+}
+
+func (b *build[V, E]) Directed() *build[V, E] {
+	b.directed = true
+	return b
+}
+
+func (b *build[V, E]) EdgePolicy(policy collection.GraphEdgePolicy) *build[V, E] {
+	b.policy = Some(policy)
+	return b
+}
+
+func (b *build[V, E]) From(edges ...edge.Edge[V, E]) *build[V, E] {
+	b.from = Some(edges)
+	return b
+}
+
+func (b *build[V, E]) Vertices(vertices ...V) *build[V, E] {
+	b.vertices = Some(vertices)
+	return b
+}
+
+func (b *build[V, E]) Build() collection.Graph[V, E] {
+	g := adjacencylist.New[V, E](b.directed, b.policy.UnwrapOrDefault())
+	for _, vertex := range b.vertices.UnwrapOrDefault() {
+		g.AddVertex(vertex)
+	}
+	for _, e := range b.from.UnwrapOrDefault() {
+		g.AddEdge(e.From(), e.To(), e.Weight())
+	}
+	return g
+}
+
+// FromGraph returns a copy of g backed by adjacency lists. The copy keeps the
+// directedness, edge policy, vertex order and edges of g.
+func FromGraph[V comparable, E any](g collection.Graph[V, E]) collection.Graph[V, E] {
+	c := adjacencylist.New[V, E](g.IsDirected(), g.EdgePolicy())
+	for vertex := range g.Vertices() {
+		c.AddVertex(vertex)
+	}
+	for e := range g.Edges() {
+		c.AddEdge(e.From(), e.To(), e.Weight())
+	}
+	return c
+}
+
+// Footprint estimates the bytes a graph backed by adjacency lists would hold
+// for the given numbers of vertices and edges, as
+// [collection.Graph.MemoryFootprint] would report it. Comparing the estimates
+// of the representations helps to choose one by density.
+func Footprint[V comparable, E any](vertices, edges int, directed bool) int {
+	return adjacencylist.Footprint[V, E](vertices, edges, directed)
+}
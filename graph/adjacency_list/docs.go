@@ -0,0 +1,7 @@
+// Package adjacencylist implements builders for [collection.Graph] backed by
+// adjacency lists. Each vertex keeps the edges leaving it, so iterating
+// neighbours costs O(degree) and memory grows with V + E, which suits sparse
+// graphs.
+package adjacencylist
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,139 @@
+package adjacencymatrix_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	adjacencymatrix "codeberg.org/yaadata/bina/graph/adjacency_matrix"
+	incidencematrix "codeberg.org/yaadata/bina/graph/incidence_matrix"
+	"codeberg.org/yaadata/bina/internal/graphtest"
+)
+
+func TestAdjacencyMatrix(t *testing.T) {
+	graphtest.Run(t, func(directed bool, policy collection.GraphEdgePolicy) collection.Graph[string, int] {
+		builder := adjacencymatrix.NewBuilder[string, int]().EdgePolicy(policy)
+		if directed {
+			builder.Directed()
+		}
+		return builder.Build()
+	})
+}
+
+func TestAdjacencyMatrixBuilder(t *testing.T) {
+	t.Run("Can build from edges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := adjacencymatrix.NewBuilder[string, float64]().
+			Directed().
+			Vertices("z").
+			From(
+				edge.New("a", "b", 1.5),
+				edge.New("b", "c", 2.5),
+				edge.New("a", "b", 9.0),
+			).
+			Build()
+		// ========= [A]ssert  =========
+		must.True(t, g.IsDirected())
+		must.Eq(t, []string{"z", "a", "b", "c"}, graphtest.Vertices(g))
+		must.Eq(t, []string{"a->b:1.5", "b->c:2.5"}, graphtest.Edges(g))
+	})
+
+	t.Run("Can build with edge policy", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := adjacencymatrix.NewBuilder[int, string]().
+			EdgePolicy(collection.GraphEdgePolicyReplace).
+			From(
+				edge.New(1, 2, "old"),
+				edge.New(2, 1, "new"),
+			).
+			Build()
+		// ========= [A]ssert  =========
+		must.False(t, g.IsDirected())
+		must.Eq(t, collection.GraphEdgePolicyReplace, g.EdgePolicy())
+		must.Eq(t, []string{"1->2:new"}, graphtest.Edges(g))
+	})
+}
+
+func TestAdjacencyMatrixFromGraph(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Directed %t", directed), func(t *testing.T) {
+			// ========= [A]rrange =========
+			builder := adjacencylist.NewBuilder[string, int]().
+				EdgePolicy(collection.GraphEdgePolicyAllow).
+				Vertices("z").
+				From(
+					edge.New("a", "b", 1),
+					edge.New("b", "c", 2),
+					edge.New("c", "a", 3),
+					edge.New("a", "b", 4),
+					edge.New("c", "c", 5),
+				)
+			if directed {
+				builder.Directed()
+			}
+			original := builder.Build()
+			// ========= [A]ct     =========
+			converted := adjacencymatrix.FromGraph(original)
+			back := adjacencylist.FromGraph(converted)
+			// ========= [A]ssert  =========
+			for _, g := range []collection.Graph[string, int]{converted, back} {
+				must.Eq(t, directed, g.IsDirected())
+				must.Eq(t, collection.GraphEdgePolicyAllow, g.EdgePolicy())
+				must.Eq(t, graphtest.Vertices(original), graphtest.Vertices(g))
+				must.Eq(t, graphtest.Edges(original), graphtest.Edges(g))
+				for vertex := range original.Vertices() {
+					must.Eq(t, graphtest.Neighbors(original, vertex), graphtest.Neighbors(g, vertex))
+					must.Eq(t, original.InDegree(vertex), g.InDegree(vertex))
+				}
+			}
+		})
+	}
+}
+
+func TestAdjacencyMatrixMemoryFootprint(t *testing.T) {
+	t.Run("Empty graph", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := adjacencymatrix.NewBuilder[int, int]().Build()
+		// ========= [A]ssert  =========
+		must.Positive(t, g.MemoryFootprint())
+		must.Eq(t, adjacencymatrix.Footprint[int, int](0, 0, false), g.MemoryFootprint())
+	})
+
+	t.Run("Estimate bounds the reported footprint", func(t *testing.T) {
+		// ========= [A]rrange =========
+		const vertices = 40
+		g := adjacencymatrix.NewBuilder[int, int]().Directed().Build()
+		// ========= [A]ct     =========
+		for from := range vertices {
+			for to := range vertices {
+				if (from+to)%3 == 0 {
+					g.AddEdge(from, to, from*to)
+				}
+			}
+		}
+		// ========= [A]ssert  =========
+		estimate := adjacencymatrix.Footprint[int, int](g.Len(), g.EdgeCount(), true)
+		must.GreaterEq(t, estimate, g.MemoryFootprint())
+		must.LessEq(t, 3*estimate, g.MemoryFootprint())
+	})
+}
+
+func TestFootprintByDensity(t *testing.T) {
+	// ========= [A]rrange =========
+	const vertices = 500
+	dense, sparse := vertices*(vertices-1), 2*vertices
+	// ========= [A]ssert  =========
+	must.Less(t,
+		adjacencylist.Footprint[int, float64](vertices, dense, true),
+		adjacencymatrix.Footprint[int, float64](vertices, dense, true))
+	must.Greater(t,
+		adjacencylist.Footprint[int, float64](vertices, sparse, true),
+		adjacencymatrix.Footprint[int, float64](vertices, sparse, true))
+	must.Greater(t,
+		adjacencylist.Footprint[int, float64](vertices, sparse, true),
+		incidencematrix.Footprint[int, float64](vertices, sparse, true))
+}
@@ -0,0 +1,11 @@
+package adjacencymatrix
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/graph/builder"
+)
+
+// Builder is a [builder.BaseBuilder] for adjacency matrix graphs.
+type Builder[V comparable, E any, Target collection.Graph[V, E], Self Builder[V, E, Target, Self]] interface {
+	builder.BaseBuilder[V, E, Target, Self]
+}
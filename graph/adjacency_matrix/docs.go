@@ -0,0 +1,6 @@
+// Package adjacencymatrix implements builders for [collection.Graph] backed
+// by an adjacency matrix. Edge lookup costs O(1) and memory grows with V²
+// regardless of the number of edges, which suits small or dense graphs.
+package adjacencymatrix
+
+import _ "codeberg.org/yaadata/bina/core/collection"
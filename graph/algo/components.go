@@ -0,0 +1,75 @@
+package algo
+
+import (
+	"slices"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/sequence/slice"
+	"codeberg.org/yaadata/bina/sequence/stack"
+	"codeberg.org/yaadata/bina/set/hashset"
+)
+
+// StronglyConnectedComponents partitions the vertices of g into strongly
+// connected components using Tarjan's algorithm. Components come in reverse
+// topological order of the condensation: no edge leads from a component to
+// an earlier one. Each component lists its vertices in the order the search
+// discovered them. In an undirected graph the components are the connected
+// components.
+func StronglyConnectedComponents[V comparable, E any](g collection.Graph[V, E]) collection.Slice[collection.Slice[V]] {
+	t := &tarjan[V, E]{
+		graph:      g,
+		index:      make(map[V]int, g.Len()),
+		low:        make(map[V]int, g.Len()),
+		open:       stack.NewBuiltinBuilder[V]().Build(),
+		onStack:    hashset.NewBuiltinBuilder[V]().Build(),
+		components: slice.NewBuiltinBuilder[collection.Slice[V]]().Build(),
+	}
+	for vertex := range g.Vertices() {
+		if _, ok := t.index[vertex]; !ok {
+			t.visit(vertex)
+		}
+	}
+	return t.components
+}
+
+// tarjan holds the state of one run of Tarjan's algorithm. index numbers
+// vertices in discovery order, low is the smallest index reachable from a
+// vertex's subtree through at most one edge to a vertex still open, and open
+// holds the vertices not yet assigned to a component.
+type tarjan[V comparable, E any] struct {
+	graph      collection.Graph[V, E]
+	index      map[V]int
+	low        map[V]int
+	open       collection.Stack[V]
+	onStack    collection.Set[V]
+	components collection.Slice[collection.Slice[V]]
+}
+
+func (t *tarjan[V, E]) visit(vertex V) {
+	t.index[vertex] = len(t.index)
+	t.low[vertex] = t.index[vertex]
+	t.open.Push(vertex)
+	t.onStack.Add(vertex)
+	for next := range t.graph.Neighbors(vertex) {
+		if _, ok := t.index[next]; !ok {
+			t.visit(next)
+			t.low[vertex] = min(t.low[vertex], t.low[next])
+		} else if t.onStack.Contains(next) {
+			t.low[vertex] = min(t.low[vertex], t.index[next])
+		}
+	}
+	if t.low[vertex] != t.index[vertex] {
+		return
+	}
+	var component []V
+	for {
+		member := t.open.Pop().Unwrap()
+		t.onStack.Remove(member)
+		component = append(component, member)
+		if member == vertex {
+			break
+		}
+	}
+	slices.Reverse(component)
+	t.components.Append(slice.NewBuiltinBuilder[V]().From(component...).Build())
+}
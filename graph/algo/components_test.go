@@ -0,0 +1,93 @@
+package algo_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	"codeberg.org/yaadata/bina/graph/algo"
+)
+
+// components collects the vertices of each component.
+func components[V comparable](result collection.Slice[collection.Slice[V]]) [][]V {
+	var res [][]V
+	for component := range result.Values() {
+		res = append(res, slices.Collect(component.Values()))
+	}
+	return res
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	t.Run("Finds components in reverse topological order", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(true, nil,
+			[2]string{"a", "b"},
+			[2]string{"b", "c"},
+			[2]string{"c", "a"},
+			[2]string{"b", "d"},
+			[2]string{"d", "e"},
+			[2]string{"e", "d"},
+			[2]string{"e", "f"},
+		)
+		// ========= [A]ct     =========
+		result := algo.StronglyConnectedComponents(g)
+		// ========= [A]ssert  =========
+		must.Eq(t, [][]string{{"f"}, {"d", "e"}, {"a", "b", "c"}}, components(result))
+	})
+
+	t.Run("Finds connected components of undirected graphs", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(false, []string{"x"},
+			[2]string{"a", "b"},
+			[2]string{"c", "b"},
+			[2]string{"d", "e"},
+		)
+		// ========= [A]ct     =========
+		result := algo.StronglyConnectedComponents(g)
+		// ========= [A]ssert  =========
+		must.Eq(t, [][]string{{"x"}, {"a", "b", "c"}, {"d", "e"}}, components(result))
+	})
+
+	t.Run("Random graphs stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(6, 1))
+		for range 100 {
+			g := adjacencylist.NewBuilder[int, int]().Directed().Build()
+			for v := range 15 {
+				g.AddVertex(v)
+			}
+			for range rng.IntN(30) {
+				g.AddEdge(rng.IntN(15), rng.IntN(15), 0)
+			}
+			// reach[u][v] reports whether v is reachable from u
+			var reach [15][15]bool
+			for u := range 15 {
+				for v := range algo.DFS(g, algo.WithSources(u)) {
+					reach[u][v] = true
+				}
+			}
+			// ========= [A]ct     =========
+			result := components(algo.StronglyConnectedComponents(g))
+			// ========= [A]ssert  =========
+			position := make(map[int]int)
+			for i, component := range result {
+				for _, v := range component {
+					position[v] = i
+				}
+			}
+			must.MapLen(t, 15, position)
+			for u := range 15 {
+				for v := range 15 {
+					must.Eq(t, reach[u][v] && reach[v][u], position[u] == position[v])
+					if reach[u][v] {
+						must.GreaterEq(t, position[v], position[u])
+					}
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package algo
+
+import (
+	"iter"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/sequence/slice"
+	"codeberg.org/yaadata/bina/set/hashset"
+)
+
+// ArticulationPoints returns the vertices whose removal would disconnect
+// the component they belong to, in vertex iteration order. Edge directions
+// are ignored, so a directed graph is treated as its underlying undirected
+// graph.
+func ArticulationPoints[V comparable, E any](g collection.Graph[V, E]) collection.Slice[V] {
+	c := connect(g)
+	points := slice.NewBuiltinBuilder[V]().Build()
+	for vertex := range g.Vertices() {
+		if c.points.Contains(vertex) {
+			points.Append(vertex)
+		}
+	}
+	return points
+}
+
+// Bridges returns the edges whose removal would disconnect the component
+// they belong to, in edge iteration order. Edge directions are ignored, as in
+// [ArticulationPoints], so an edge with a parallel edge or an edge in the
+// opposite direction is never a bridge.
+func Bridges[V comparable, E any](g collection.Graph[V, E]) collection.Slice[edge.Edge[V, E]] {
+	c := connect(g)
+	bridges := slice.NewBuiltinBuilder[edge.Edge[V, E]]().Build()
+	for e := range g.Edges() {
+		if c.bridges.Contains(pair[V]{e.From(), e.To()}) || c.bridges.Contains(pair[V]{e.To(), e.From()}) {
+			bridges.Append(e)
+		}
+	}
+	return bridges
+}
+
+// pair is an edge without its weight.
+type pair[V comparable] struct {
+	from, to V
+}
+
+// connectivity holds the result of a lowlink search over the underlying
+// undirected graph. order numbers vertices in discovery order and low is the
+// smallest order reachable from a vertex's subtree through at most one edge
+// outside the tree.
+type connectivity[V comparable, E any] struct {
+	neighbors func(V) iter.Seq2[V, E]
+	order     map[V]int
+	low       map[V]int
+	points    collection.Set[V]
+	bridges   collection.Set[pair[V]]
+}
+
+func connect[V comparable, E any](g collection.Graph[V, E]) *connectivity[V, E] {
+	c := &connectivity[V, E]{
+		neighbors: undirected(g),
+		order:     make(map[V]int, g.Len()),
+		low:       make(map[V]int, g.Len()),
+		points:    hashset.NewBuiltinBuilder[V]().Build(),
+		bridges:   hashset.NewBuiltinBuilder[pair[V]]().Build(),
+	}
+	for vertex := range g.Vertices() {
+		if _, ok := c.order[vertex]; ok {
+			continue
+		}
+		if c.visit(vertex, vertex, true) > 1 {
+			c.points.Add(vertex)
+		}
+	}
+	return c
+}
+
+// visit searches from vertex, reached through a tree edge from parent, and
+// returns the number of its children in the search tree. Only the first edge
+// back to the parent is the tree edge; any parallel one closes a cycle.
+func (c *connectivity[V, E]) visit(vertex, parent V, root bool) int {
+	c.order[vertex] = len(c.order)
+	c.low[vertex] = c.order[vertex]
+	children := 0
+	skipped := root
+	for next := range c.neighbors(vertex) {
+		switch _, seen := c.order[next]; {
+		case next == vertex:
+		case next == parent && !skipped:
+			skipped = true
+		case seen:
+			c.low[vertex] = min(c.low[vertex], c.order[next])
+		default:
+			children++
+			c.visit(next, vertex, false)
+			c.low[vertex] = min(c.low[vertex], c.low[next])
+			if !root && c.low[next] >= c.order[vertex] {
+				c.points.Add(vertex)
+			}
+			if c.low[next] > c.order[vertex] {
+				c.bridges.Add(pair[V]{vertex, next})
+			}
+		}
+	}
+	return children
+}
+
+// undirected returns the neighbours of each vertex of g with edge directions
+// ignored. Each directed edge becomes one undirected edge.
+func undirected[V comparable, E any](g collection.Graph[V, E]) func(V) iter.Seq2[V, E] {
+	if !g.IsDirected() {
+		return g.Neighbors
+	}
+	adjacent := make(map[V][]edge.Edge[V, E], g.Len())
+	for e := range g.Edges() {
+		adjacent[e.From()] = append(adjacent[e.From()], e)
+		if e.From() != e.To() {
+			adjacent[e.To()] = append(adjacent[e.To()], edge.New(e.To(), e.From(), e.Weight()))
+		}
+	}
+	return func(vertex V) iter.Seq2[V, E] {
+		return func(yield func(V, E) bool) {
+			for _, e := range adjacent[vertex] {
+				if !yield(e.To(), e.Weight()) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package algo_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	"codeberg.org/yaadata/bina/graph/algo"
+)
+
+// countComponents counts the connected components of the undirected graph
+// with the given vertices and edges.
+func countComponents(vertices []int, edges []edge.Edge[int, int]) int {
+	g := adjacencylist.NewBuilder[int, int]().
+		EdgePolicy(collection.GraphEdgePolicyAllow).
+		Vertices(vertices...).
+		From(edges...).
+		Build()
+	return algo.StronglyConnectedComponents(g).Len()
+}
+
+func TestArticulationPoints(t *testing.T) {
+	t.Run("Finds cut vertices", func(t *testing.T) {
+		// ========= [A]rrange =========
+		// a - b - c - d
+		//      \ /    |
+		//       e     f - g
+		//             |  /
+		//             h
+		g := build(false, nil,
+			[2]string{"a", "b"},
+			[2]string{"b", "c"},
+			[2]string{"b", "e"},
+			[2]string{"c", "e"},
+			[2]string{"c", "d"},
+			[2]string{"d", "f"},
+			[2]string{"f", "g"},
+			[2]string{"f", "h"},
+			[2]string{"g", "h"},
+		)
+		// ========= [A]ct     =========
+		points := algo.ArticulationPoints(g)
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"b", "c", "d", "f"}, slices.Collect(points.Values()))
+	})
+
+	t.Run("Ignores edge direction", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(true, nil,
+			[2]string{"a", "b"},
+			[2]string{"c", "b"},
+		)
+		// ========= [A]ct     =========
+		points := algo.ArticulationPoints(g)
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"b"}, slices.Collect(points.Values()))
+	})
+}
+
+func TestBridges(t *testing.T) {
+	t.Run("Finds bridges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(false, nil,
+			[2]string{"a", "b"},
+			[2]string{"b", "c"},
+			[2]string{"c", "a"},
+			[2]string{"d", "c"},
+			[2]string{"d", "e"},
+			[2]string{"e", "e"},
+		)
+		// ========= [A]ct     =========
+		bridges := algo.Bridges(g)
+		// ========= [A]ssert  =========
+		var actual []string
+		for e := range bridges.Values() {
+			actual = append(actual, fmt.Sprintf("%s->%s", e.From(), e.To()))
+		}
+		must.Eq(t, []string{"d->c", "d->e"}, actual)
+	})
+
+	t.Run("Parallel edges are not bridges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(false, nil,
+			[2]string{"a", "b"},
+			[2]string{"b", "a"},
+			[2]string{"b", "c"},
+		)
+		// ========= [A]ct     =========
+		bridges := algo.Bridges(g)
+		// ========= [A]ssert  =========
+		must.Eq(t, 1, bridges.Len())
+		must.Eq(t, "c", bridges.Get(0).Unwrap().To())
+	})
+
+	t.Run("Opposite directed edges are not bridges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(true, nil,
+			[2]string{"a", "b"},
+			[2]string{"b", "a"},
+			[2]string{"c", "b"},
+		)
+		// ========= [A]ct     =========
+		bridges := algo.Bridges(g)
+		// ========= [A]ssert  =========
+		must.Eq(t, 1, bridges.Len())
+		must.Eq(t, "c", bridges.Get(0).Unwrap().From())
+	})
+}
+
+func TestConnectivityRandom(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(9, 3))
+	for round := range 200 {
+		directed := round%2 == 1
+		var vertices []int
+		for v := range 10 {
+			vertices = append(vertices, v)
+		}
+		var edges []edge.Edge[int, int]
+		for i := range rng.IntN(16) {
+			edges = append(edges, edge.New(rng.IntN(10), rng.IntN(10), i))
+		}
+		builder := adjacencylist.NewBuilder[int, int]().
+			EdgePolicy(collection.GraphEdgePolicyAllow).
+			Vertices(vertices...).
+			From(edges...)
+		if directed {
+			builder.Directed()
+		}
+		g := builder.Build()
+		before := countComponents(vertices, edges)
+		// ========= [A]ct     =========
+		points := slices.Collect(algo.ArticulationPoints(g).Values())
+		bridges := slices.Collect(algo.Bridges(g).Values())
+		// ========= [A]ssert  =========
+		for _, v := range vertices {
+			remaining := slices.DeleteFunc(slices.Clone(vertices), func(u int) bool {
+				return u == v
+			})
+			kept := slices.DeleteFunc(slices.Clone(edges), func(e edge.Edge[int, int]) bool {
+				return e.From() == v || e.To() == v
+			})
+			cut := countComponents(remaining, kept) > before
+			must.Eq(t, cut, slices.Contains(points, v))
+		}
+		for i, e := range edges {
+			kept := slices.Delete(slices.Clone(edges), i, i+1)
+			isBridge := countComponents(vertices, kept) > before
+			must.Eq(t, isBridge, slices.ContainsFunc(bridges, func(b edge.Edge[int, int]) bool {
+				return b.Weight() == e.Weight()
+			}))
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package algo implements algorithms over [collection.Graph]: traversals,
+// orderings and connectivity queries. Every function works with any graph
+// representation and visits vertices and edges in the order the graph
+// iterates them, so results are deterministic for a given graph.
+package algo
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,189 @@
+package algo
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/core/numeric"
+	"codeberg.org/yaadata/bina/sequence/queue"
+	"codeberg.org/yaadata/bina/sequence/slice"
+	"codeberg.org/yaadata/bina/set/hashset"
+)
+
+// Flow is a maximum flow from a source to a sink together with a minimum
+// cut separating them.
+type Flow[V comparable, W numeric.Number] struct {
+	value      W
+	edges      collection.Slice[edge.Edge[V, W]]
+	cut        collection.Slice[edge.Edge[V, W]]
+	sourceSide collection.Set[V]
+}
+
+// Value returns the total flow leaving the source, which equals the
+// capacity of the minimum cut.
+func (f *Flow[V, W]) Value() W {
+	return f.value
+}
+
+// Edges returns the edges carrying flow, in edge iteration order, each
+// weighted with the flow along it and oriented the way the flow goes.
+func (f *Flow[V, W]) Edges() collection.Slice[edge.Edge[V, W]] {
+	return f.edges
+}
+
+// MinCut returns the edges of a minimum cut, in edge iteration order and
+// with their capacities as weights: those leading from [Flow.SourceSide] to
+// the other vertices, or joining the two in an undirected graph. Their
+// capacities add up to [Flow.Value].
+func (f *Flow[V, W]) MinCut() collection.Slice[edge.Edge[V, W]] {
+	return f.cut
+}
+
+// SourceSide returns the vertices still reachable from the source through
+// edges with spare capacity. It is the smallest source side of a minimum
+// cut.
+func (f *Flow[V, W]) SourceSide() collection.Set[V] {
+	return f.sourceSide
+}
+
+// MaxFlow returns a maximum flow from source to sink using Dinic's
+// algorithm, in O(V²E). Edge weights are capacities and must be
+// non-negative. An undirected edge can carry flow either way, up to its
+// capacity. Vertices and edges are explored in iteration order, so the
+// result is deterministic for a given graph. If source or sink is missing,
+// or they are the same vertex, the flow is empty.
+func MaxFlow[V comparable, W numeric.Number](g collection.Graph[V, W], source, sink V) *Flow[V, W] {
+	n := newNetwork(g)
+	s, sOK := n.index[source]
+	t, tOK := n.index[sink]
+	f := &Flow[V, W]{
+		edges:      slice.NewBuiltinBuilder[edge.Edge[V, W]]().Build(),
+		cut:        slice.NewBuiltinBuilder[edge.Edge[V, W]]().Build(),
+		sourceSide: hashset.NewBuiltinBuilder[V]().Build(),
+	}
+	if !sOK || !tOK || s == t {
+		return f
+	}
+	for n.layer(s, t) {
+		clear(n.next)
+		for pushed := n.augment(s, t, None[W]()); pushed > 0; pushed = n.augment(s, t, None[W]()) {
+			f.value += pushed
+		}
+	}
+	n.layer(s, t)
+	for i, vertex := range n.vertices {
+		if n.level[i] >= 0 {
+			f.sourceSide.Add(vertex)
+		}
+	}
+	for i, e := range n.edges {
+		a := 2 * i
+		from, to := n.index[e.From()], n.index[e.To()]
+		if capacity, residual := e.Weight(), n.residual[a]; residual < capacity {
+			f.edges.Append(edge.New(e.From(), e.To(), capacity-residual))
+		} else if residual > capacity {
+			f.edges.Append(edge.New(e.To(), e.From(), residual-capacity))
+		}
+		if n.level[from] >= 0 && n.level[to] < 0 || !g.IsDirected() && n.level[to] >= 0 && n.level[from] < 0 {
+			f.cut.Append(e)
+		}
+	}
+	return f
+}
+
+// network is the residual network of a graph. Arcs 2i and 2i+1 belong to
+// the i-th edge: the first runs the way the edge does, the second back, with
+// no capacity unless the graph is undirected.
+type network[V comparable, W numeric.Number] struct {
+	vertices []V
+	index    map[V]int
+	edges    []edge.Edge[V, W]
+	// arcs lists the arcs leaving each vertex.
+	arcs     [][]int
+	head     []int
+	residual []W
+	// level is the BFS distance from the source in the residual network, or
+	// -1 if unreachable.
+	level []int
+	// next is the first arc of each vertex not yet known to be useless in
+	// the current phase.
+	next []int
+}
+
+func newNetwork[V comparable, W numeric.Number](g collection.Graph[V, W]) *network[V, W] {
+	n := &network[V, W]{
+		index: make(map[V]int, g.Len()),
+	}
+	for vertex := range g.Vertices() {
+		n.index[vertex] = len(n.vertices)
+		n.vertices = append(n.vertices, vertex)
+	}
+	n.arcs = make([][]int, len(n.vertices))
+	n.level = make([]int, len(n.vertices))
+	n.next = make([]int, len(n.vertices))
+	for e := range g.Edges() {
+		from, to := n.index[e.From()], n.index[e.To()]
+		var back W
+		if !g.IsDirected() {
+			back = e.Weight()
+		}
+		n.edges = append(n.edges, e)
+		n.arcs[from] = append(n.arcs[from], len(n.head))
+		n.head = append(n.head, to)
+		n.residual = append(n.residual, e.Weight())
+		n.arcs[to] = append(n.arcs[to], len(n.head))
+		n.head = append(n.head, from)
+		n.residual = append(n.residual, back)
+	}
+	return n
+}
+
+// layer assigns BFS levels from s over arcs with spare capacity and reports
+// whether t was reached.
+func (n *network[V, W]) layer(s, t int) bool {
+	for i := range n.level {
+		n.level[i] = -1
+	}
+	builder := queue.NewBuiltinBuilder[int]()
+	builder.BackedBy(queue.QueueBackedBySinglyLinkedList)
+	pending := builder.Build()
+	n.level[s] = 0
+	pending.Enqueue(s)
+	for !pending.IsEmpty() {
+		u := pending.Dequeue().Unwrap()
+		for _, a := range n.arcs[u] {
+			if v := n.head[a]; n.residual[a] > 0 && n.level[v] < 0 {
+				n.level[v] = n.level[u] + 1
+				pending.Enqueue(v)
+			}
+		}
+	}
+	return n.level[t] >= 0
+}
+
+// augment pushes up to limit units from u to t along arcs that go one level
+// deeper and returns the amount pushed. No limit leaves the push bounded only
+// by the residual capacities, which cannot overflow W the way their sum can.
+func (n *network[V, W]) augment(u, t int, limit Option[W]) W {
+	if u == t {
+		return limit.Unwrap()
+	}
+	for ; n.next[u] < len(n.arcs[u]); n.next[u]++ {
+		a := n.arcs[u][n.next[u]]
+		v := n.head[a]
+		if n.residual[a] <= 0 || n.level[v] != n.level[u]+1 {
+			continue
+		}
+		budget := n.residual[a]
+		if limit.IsSome() {
+			budget = min(limit.Unwrap(), budget)
+		}
+		if pushed := n.augment(v, t, Some(budget)); pushed > 0 {
+			n.residual[a] -= pushed
+			n.residual[a^1] += pushed
+			return pushed
+		}
+	}
+	return 0
+}
@@ -0,0 +1,203 @@
+package algo_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	"codeberg.org/yaadata/bina/graph/algo"
+)
+
+func TestMaxFlow(t *testing.T) {
+	t.Run("Finds the maximum flow and a minimum cut", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("s", "a", 16),
+			edge.New("s", "c", 13),
+			edge.New("a", "b", 12),
+			edge.New("c", "a", 4),
+			edge.New("b", "c", 9),
+			edge.New("c", "d", 14),
+			edge.New("d", "b", 7),
+			edge.New("b", "t", 20),
+			edge.New("d", "t", 4),
+		)
+		// ========= [A]ct     =========
+		flow := algo.MaxFlow(g, "s", "t")
+		// ========= [A]ssert  =========
+		must.Eq(t, 23, flow.Value())
+		must.Eq(t, []string{"a-b:12", "d-b:7", "d-t:4"}, format(flow.MinCut()))
+		must.SliceContainsAll(t, []string{"s", "a", "c", "d"}, slices.Collect(flow.SourceSide().Values()))
+		assertFlow(t, g, "s", "t", flow)
+	})
+
+	t.Run("Sends flow both ways along undirected edges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(false,
+			edge.New("s", "a", 2),
+			edge.New("s", "b", 1),
+			edge.New("b", "a", 3),
+			edge.New("a", "t", 1),
+			edge.New("t", "b", 2),
+		)
+		// ========= [A]ct     =========
+		flow := algo.MaxFlow(g, "s", "t")
+		// ========= [A]ssert  =========
+		must.Eq(t, 3, flow.Value())
+		must.Eq(t, []string{"s-a:2", "s-b:1", "a-t:1", "a-b:1", "b-t:2"}, format(flow.Edges()))
+		must.Eq(t, []string{"s-a:2", "s-b:1"}, format(flow.MinCut()))
+		assertFlow(t, g, "s", "t", flow)
+	})
+
+	t.Run("Handles unreachable and missing vertices", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true, edge.New("s", "a", 1), edge.New("t", "a", 1))
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, algo.MaxFlow(g, "s", "t").Value())
+		must.Eq(t, 0, algo.MaxFlow(g, "s", "t").MinCut().Len())
+		must.Eq(t, 0, algo.MaxFlow(g, "s", "missing").Value())
+		must.Eq(t, 0, algo.MaxFlow(g, "s", "s").Edges().Len())
+	})
+
+	t.Run("Works with float capacities", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("s", "a", 1.5),
+			edge.New("s", "b", 2.0),
+			edge.New("a", "t", 2.5),
+			edge.New("b", "t", 0.5),
+		)
+		// ========= [A]ct     =========
+		flow := algo.MaxFlow(g, "s", "t")
+		// ========= [A]ssert  =========
+		must.Eq(t, 2.0, flow.Value())
+		must.Eq(t, []string{"s-a:1.5", "b-t:0.5"}, format(flow.MinCut()))
+	})
+
+	t.Run("Does not overflow small capacity types", func(t *testing.T) {
+		// ========= [A]rrange =========
+		// The capacities leaving s add up to 256, which wraps to 0 in a uint8
+		g := adjacencylist.NewBuilder[string, uint8]().
+			Directed().
+			From(
+				edge.New[string, uint8]("s", "a", 128),
+				edge.New[string, uint8]("s", "b", 128),
+				edge.New[string, uint8]("a", "t", 1),
+			).
+			Build()
+		// ========= [A]ct     =========
+		flow := algo.MaxFlow(g, "s", "t")
+		// ========= [A]ssert  =========
+		must.Eq(t, uint8(1), flow.Value())
+		must.Eq(t, []string{"a-t:1"}, format(flow.MinCut()))
+	})
+
+	t.Run("Does not underflow unsigned capacities against undirected edges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := adjacencylist.NewBuilder[string, uint8]().
+			From(
+				edge.New[string, uint8]("a", "s", 200),
+				edge.New[string, uint8]("t", "a", 3),
+			).
+			Build()
+		// ========= [A]ct     =========
+		flow := algo.MaxFlow(g, "s", "t")
+		// ========= [A]ssert  =========
+		must.Eq(t, uint8(3), flow.Value())
+		must.Eq(t, []string{"s-a:3", "a-t:3"}, format(flow.Edges()))
+	})
+
+	t.Run("Random graphs match the brute-force minimum cut", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(1, 4))
+		for round := range 100 {
+			builder := adjacencylist.NewBuilder[string, int]().EdgePolicy(collection.GraphEdgePolicyAllow)
+			if round%2 == 0 {
+				builder.Directed()
+			}
+			g := builder.Build()
+			names := []string{"s", "t", "a", "b", "c", "d", "e"}
+			for _, v := range names {
+				g.AddVertex(v)
+			}
+			for range rng.IntN(20) {
+				g.AddEdge(names[rng.IntN(len(names))], names[rng.IntN(len(names))], rng.IntN(10))
+			}
+			// Every source side holds s but not t, plus any subset of the rest
+			best := -1
+			for mask := range 1 << (len(names) - 2) {
+				side := map[string]bool{"s": true}
+				for i, v := range names[2:] {
+					side[v] = mask&(1<<i) != 0
+				}
+				var capacity int
+				for e := range g.Edges() {
+					if side[e.From()] && !side[e.To()] || !g.IsDirected() && side[e.To()] && !side[e.From()] {
+						capacity += e.Weight()
+					}
+				}
+				if best < 0 || capacity < best {
+					best = capacity
+				}
+			}
+			// ========= [A]ct     =========
+			flow := algo.MaxFlow(g, "s", "t")
+			// ========= [A]ssert  =========
+			must.Eq(t, best, flow.Value())
+			assertFlow(t, g, "s", "t", flow)
+		}
+	})
+}
+
+// assertFlow checks that flow respects capacities and conservation, and that
+// its minimum cut is saturated and separates the source side.
+func assertFlow(t *testing.T, g collection.Graph[string, int], source, sink string, flow *algo.Flow[string, int]) {
+	t.Helper()
+	// The flow between each pair of vertices is within the capacity of the
+	// edges joining them
+	key := func(from, to string) [2]string {
+		if !g.IsDirected() && to < from {
+			return [2]string{to, from}
+		}
+		return [2]string{from, to}
+	}
+	capacity := make(map[[2]string]int)
+	for e := range g.Edges() {
+		capacity[key(e.From(), e.To())] += e.Weight()
+	}
+	moved := make(map[[2]string]int)
+	balance := make(map[string]int)
+	for e := range flow.Edges().Values() {
+		must.Positive(t, e.Weight())
+		if k := key(e.From(), e.To()); k[0] == e.From() {
+			moved[k] += e.Weight()
+		} else {
+			moved[k] -= e.Weight()
+		}
+		balance[e.From()] -= e.Weight()
+		balance[e.To()] += e.Weight()
+	}
+	for k, net := range moved {
+		must.LessEq(t, capacity[k], max(net, -net))
+	}
+	for vertex, net := range balance {
+		switch vertex {
+		case source:
+			must.Eq(t, -flow.Value(), net)
+		case sink:
+			must.Eq(t, flow.Value(), net)
+		default:
+			must.Eq(t, 0, net, must.Sprint(fmt.Sprintf("conservation at %s", vertex)))
+		}
+	}
+	side := flow.SourceSide()
+	must.True(t, side.Contains(source))
+	must.False(t, side.Contains(sink))
+	must.Eq(t, flow.Value(), total(flow.MinCut()))
+}
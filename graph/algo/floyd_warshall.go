@@ -0,0 +1,114 @@
+package algo
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/numeric"
+	"codeberg.org/yaadata/bina/sequence/slice"
+)
+
+// AllShortestPaths holds the shortest paths between every pair of vertices
+// of a graph.
+type AllShortestPaths[V comparable, W numeric.Number] struct {
+	vertices []V
+	index    map[V]int
+	distance [][]W
+	// next[i][j] is the index of the vertex after the i-th on a shortest path
+	// to the j-th, or -1 if there is no path.
+	next [][]int
+}
+
+// Distance returns the weight of the shortest path from one vertex to
+// another, or None if there is no path. Every vertex reaches itself at
+// distance zero.
+func (p *AllShortestPaths[V, W]) Distance(from, to V) Option[W] {
+	i, j, ok := p.indices(from, to)
+	if !ok || p.next[i][j] < 0 {
+		return None[W]()
+	}
+	return Some(p.distance[i][j])
+}
+
+// Path returns the shortest path from one vertex to another, or None if
+// there is no path.
+func (p *AllShortestPaths[V, W]) Path(from, to V) Option[Path[V, W]] {
+	i, j, ok := p.indices(from, to)
+	if !ok || p.next[i][j] < 0 {
+		return None[Path[V, W]]()
+	}
+	vertices := slice.NewBuiltinBuilder[V]().Build()
+	vertices.Append(from)
+	for at := i; at != j; {
+		at = p.next[at][j]
+		vertices.Append(p.vertices[at])
+	}
+	return Some(Path[V, W]{Vertices: vertices, Weight: p.distance[i][j]})
+}
+
+func (p *AllShortestPaths[V, W]) indices(from, to V) (int, int, bool) {
+	i, ok := p.index[from]
+	if !ok {
+		return 0, 0, false
+	}
+	j, ok := p.index[to]
+	return i, j, ok
+}
+
+// FloydWarshall returns the shortest paths between every pair of vertices
+// of g in O(V³) time and O(V²) space, allowing negative edge weights. Of
+// parallel edges only the lightest counts. If g has a cycle of negative
+// weight the result is an error of type *[NegativeCycleError] naming one.
+func FloydWarshall[V comparable, W numeric.Number](g collection.Graph[V, W]) Result[*AllShortestPaths[V, W]] {
+	n := g.Len()
+	p := &AllShortestPaths[V, W]{
+		vertices: make([]V, 0, n),
+		index:    make(map[V]int, n),
+		distance: make([][]W, n),
+		next:     make([][]int, n),
+	}
+	for vertex := range g.Vertices() {
+		p.index[vertex] = len(p.vertices)
+		p.vertices = append(p.vertices, vertex)
+	}
+	for i, vertex := range p.vertices {
+		p.distance[i] = make([]W, n)
+		p.next[i] = make([]int, n)
+		for j := range n {
+			p.next[i][j] = -1
+		}
+		p.next[i][i] = i
+		for neighbor, weight := range g.Neighbors(vertex) {
+			j := p.index[neighbor]
+			if p.next[i][j] < 0 || weight < p.distance[i][j] {
+				p.distance[i][j] = weight
+				p.next[i][j] = j
+			}
+		}
+	}
+	for k := range n {
+		for i := range n {
+			if p.next[i][k] < 0 {
+				continue
+			}
+			for j := range n {
+				if p.next[k][j] < 0 {
+					continue
+				}
+				through := p.distance[i][k] + p.distance[k][j]
+				if p.next[i][j] < 0 || through < p.distance[i][j] {
+					p.distance[i][j] = through
+					p.next[i][j] = p.next[i][k]
+				}
+			}
+		}
+	}
+	for i, vertex := range p.vertices {
+		if p.distance[i][i] < 0 {
+			// Bellman-Ford from a vertex on a negative closed walk is bound to
+			// find a negative cycle, and names it more reliably than next.
+			return Err[*AllShortestPaths[V, W]](BellmanFord(g, vertex).UnwrapErr())
+		}
+	}
+	return Ok(p)
+}
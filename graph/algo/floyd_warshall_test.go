@@ -0,0 +1,75 @@
+package algo_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/graph/algo"
+)
+
+func TestFloydWarshall(t *testing.T) {
+	t.Run("Finds all shortest paths", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("a", "c", -2),
+			edge.New("b", "a", 4),
+			edge.New("b", "c", 3),
+			edge.New("c", "d", 2),
+			edge.New("d", "b", -1),
+			edge.New("d", "b", 5),
+		)
+		g.AddVertex("lonely")
+		// ========= [A]ct     =========
+		result := algo.FloydWarshall(g)
+		// ========= [A]ssert  =========
+		must.True(t, result.IsOk())
+		paths := result.Unwrap()
+		expected := map[[2]string]int{
+			{"a", "b"}: -1, {"a", "c"}: -2, {"a", "d"}: 0,
+			{"b", "a"}: 4, {"b", "c"}: 2, {"b", "d"}: 4,
+			{"c", "a"}: 5, {"c", "b"}: 1, {"c", "d"}: 2,
+			{"d", "a"}: 3, {"d", "b"}: -1, {"d", "c"}: 1,
+		}
+		for pair, distance := range expected {
+			must.Eq(t, distance, paths.Distance(pair[0], pair[1]).Unwrap())
+			must.Eq(t, distance, paths.Path(pair[0], pair[1]).Unwrap().Weight)
+		}
+		must.Eq(t, []string{"b", "a", "c", "d"}, collect(paths.Path("b", "d").Unwrap().Vertices))
+		must.Eq(t, 0, paths.Distance("lonely", "lonely").Unwrap())
+		must.Eq(t, []string{"lonely"}, collect(paths.Path("lonely", "lonely").Unwrap().Vertices))
+		must.True(t, paths.Distance("a", "lonely").IsNone())
+		must.True(t, paths.Path("lonely", "a").IsNone())
+		must.True(t, paths.Distance("a", "missing").IsNone())
+	})
+
+	t.Run("Reports negative cycles", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("a", "b", 1),
+			edge.New("c", "d", 2),
+			edge.New("d", "e", -4),
+			edge.New("e", "c", 1),
+		)
+		// ========= [A]ct     =========
+		result := algo.FloydWarshall(g)
+		// ========= [A]ssert  =========
+		var cycle *algo.NegativeCycleError[string]
+		must.True(t, errors.As(result.UnwrapErr(), &cycle))
+		must.SliceContainsAll(t, []string{"c", "d", "e"}, collect(cycle.Cycle))
+		assertCycle(t, g, collect(cycle.Cycle))
+	})
+
+	t.Run("Reports negative self-loops", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true, edge.New("a", "b", 1), edge.New("b", "b", -1))
+		// ========= [A]ct     =========
+		result := algo.FloydWarshall(g)
+		// ========= [A]ssert  =========
+		var cycle *algo.NegativeCycleError[string]
+		must.True(t, errors.As(result.UnwrapErr(), &cycle))
+		must.Eq(t, []string{"b"}, collect(cycle.Cycle))
+	})
+}
@@ -0,0 +1,27 @@
+package algo_test
+
+import (
+	"fmt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/core/numeric"
+)
+
+// format returns the edges of forest as "from-to:weight".
+func format[W numeric.Number](forest collection.Slice[edge.Edge[string, W]]) []string {
+	var res []string
+	for e := range forest.Values() {
+		res = append(res, fmt.Sprintf("%s-%s:%v", e.From(), e.To(), e.Weight()))
+	}
+	return res
+}
+
+// total returns the sum of the weights of forest.
+func total[V comparable, W numeric.Number](forest collection.Slice[edge.Edge[V, W]]) W {
+	var sum W
+	for e := range forest.Values() {
+		sum += e.Weight()
+	}
+	return sum
+}
@@ -0,0 +1,156 @@
+package algo
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/sequence/queue"
+	"codeberg.org/yaadata/bina/sequence/slice"
+)
+
+// Matching is a set of edges of a bipartite graph no two of which share a
+// vertex.
+type Matching[V comparable, E any] struct {
+	partner map[V]V
+	edges   collection.Slice[edge.Edge[V, E]]
+}
+
+// Len returns the number of matched pairs.
+func (m *Matching[V, E]) Len() int {
+	return m.edges.Len()
+}
+
+// Partner returns the vertex matched with vertex, or None if vertex is
+// unmatched.
+func (m *Matching[V, E]) Partner(vertex V) Option[V] {
+	if partner, ok := m.partner[vertex]; ok {
+		return Some(partner)
+	}
+	return None[V]()
+}
+
+// Edges returns the matched edges, each leading from a left vertex to its
+// partner, in the iteration order of the left vertices.
+func (m *Matching[V, E]) Edges() collection.Slice[edge.Edge[V, E]] {
+	return m.edges
+}
+
+// HopcroftKarp returns a maximum matching between the vertices of g in left
+// and the rest, in O(E√V). Edge directions are ignored and edges joining two
+// vertices on the same side are skipped. Vertices and edges are explored in
+// iteration order, so the result is deterministic for a given graph.
+func HopcroftKarp[V comparable, E any](g collection.Graph[V, E], left collection.Set[V]) *Matching[V, E] {
+	h := &hopcroftKarp[V, E]{
+		neighbors: undirected(g),
+		left:      left,
+		mate:      make(map[V]edge.Edge[V, E]),
+		depth:     make(map[V]int),
+	}
+	for vertex := range g.Vertices() {
+		if left.Contains(vertex) {
+			h.order = append(h.order, vertex)
+		}
+	}
+	for h.layer() {
+		for _, u := range h.order {
+			if _, matched := h.mate[u]; !matched {
+				h.augment(u)
+			}
+		}
+	}
+	m := &Matching[V, E]{
+		partner: make(map[V]V, 2*len(h.mate)),
+		edges:   slice.NewBuiltinBuilder[edge.Edge[V, E]]().Build(),
+	}
+	for _, u := range h.order {
+		if e, ok := h.mate[u]; ok {
+			m.partner[u] = e.To()
+			m.partner[e.To()] = u
+			m.edges.Append(e)
+		}
+	}
+	return m
+}
+
+// hopcroftKarp holds the state of one run of the Hopcroft-Karp algorithm.
+// mate maps each matched vertex on either side to its matched edge, oriented
+// from the left vertex. depth is the phase's BFS layer of each left vertex
+// along alternating paths from the free ones; a left vertex missing from it
+// is unreachable or has been found to be a dead end. shortest is the depth
+// of the left vertices next to a free right vertex on the shortest
+// augmenting paths; only those paths are flipped in a phase.
+type hopcroftKarp[V comparable, E any] struct {
+	neighbors func(V) iter.Seq2[V, E]
+	left      collection.Set[V]
+	order     []V
+	mate      map[V]edge.Edge[V, E]
+	depth     map[V]int
+	shortest  int
+}
+
+// layer runs the BFS of a phase and reports whether a free right vertex can
+// be reached along an alternating path.
+func (h *hopcroftKarp[V, E]) layer() bool {
+	clear(h.depth)
+	builder := queue.NewBuiltinBuilder[V]()
+	builder.BackedBy(queue.QueueBackedBySinglyLinkedList)
+	pending := builder.Build()
+	for _, u := range h.order {
+		if _, matched := h.mate[u]; !matched {
+			h.depth[u] = 0
+			pending.Enqueue(u)
+		}
+	}
+	found := false
+	for !pending.IsEmpty() {
+		u := pending.Dequeue().Unwrap()
+		if found && h.depth[u] > h.shortest {
+			break
+		}
+		for v := range h.neighbors(u) {
+			if h.left.Contains(v) {
+				continue
+			}
+			e, matched := h.mate[v]
+			if !matched {
+				found = true
+				h.shortest = h.depth[u]
+				continue
+			}
+			if _, seen := h.depth[e.From()]; !seen {
+				h.depth[e.From()] = h.depth[u] + 1
+				pending.Enqueue(e.From())
+			}
+		}
+	}
+	return found
+}
+
+// augment looks for an alternating path from the left vertex u to a free
+// right vertex through the layers and flips it. Reports whether it did.
+func (h *hopcroftKarp[V, E]) augment(u V) bool {
+	for v, weight := range h.neighbors(u) {
+		if h.left.Contains(v) {
+			continue
+		}
+		e, matched := h.mate[v]
+		if !matched && h.depth[u] != h.shortest {
+			continue
+		}
+		if matched {
+			next := e.From()
+			if depth, ok := h.depth[next]; !ok || depth != h.depth[u]+1 || !h.augment(next) {
+				continue
+			}
+		}
+		e = edge.New(u, v, weight)
+		h.mate[u] = e
+		h.mate[v] = e
+		return true
+	}
+	delete(h.depth, u)
+	return false
+}
@@ -0,0 +1,119 @@
+package algo_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	"codeberg.org/yaadata/bina/graph/algo"
+	"codeberg.org/yaadata/bina/set/hashset"
+)
+
+func TestHopcroftKarp(t *testing.T) {
+	t.Run("Assigns workers to shards", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("w1", "s1", 1),
+			edge.New("w1", "s2", 1),
+			edge.New("w2", "s1", 1),
+			edge.New("w3", "s2", 1),
+			edge.New("w3", "s3", 1),
+			edge.New("w4", "s3", 1),
+		)
+		workers := hashset.NewBuiltinBuilder[string]().From("w1", "w2", "w3", "w4").Build()
+		// ========= [A]ct     =========
+		matching := algo.HopcroftKarp(g, workers)
+		// ========= [A]ssert  =========
+		must.Eq(t, 3, matching.Len())
+		must.Eq(t, []string{"w1-s1:1", "w3-s2:1", "w4-s3:1"}, format(matching.Edges()))
+		must.Eq(t, "s1", matching.Partner("w1").Unwrap())
+		must.Eq(t, "w4", matching.Partner("s3").Unwrap())
+		must.True(t, matching.Partner("w2").IsNone())
+		must.True(t, matching.Partner("missing").IsNone())
+	})
+
+	t.Run("Reroutes along augmenting paths", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(false,
+			edge.New("w1", "s1", 1),
+			edge.New("w1", "s2", 2),
+			edge.New("w2", "s1", 3),
+		)
+		workers := hashset.NewBuiltinBuilder[string]().From("w1", "w2").Build()
+		// ========= [A]ct     =========
+		matching := algo.HopcroftKarp(g, workers)
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"w1-s2:2", "w2-s1:3"}, format(matching.Edges()))
+	})
+
+	t.Run("Ignores direction and same-side edges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("r1", "l1", 1),
+			edge.New("l1", "l2", 1),
+			edge.New("r1", "r2", 1),
+			edge.New("l2", "r1", 1),
+		)
+		left := hashset.NewBuiltinBuilder[string]().From("l1", "l2").Build()
+		// ========= [A]ct     =========
+		matching := algo.HopcroftKarp(g, left)
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"l1-r1:1"}, format(matching.Edges()))
+	})
+
+	t.Run("Matches an empty graph", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted[int](false)
+		// ========= [A]ct     =========
+		matching := algo.HopcroftKarp(g, hashset.NewBuiltinBuilder[string]().Build())
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, matching.Len())
+	})
+
+	t.Run("Random graphs match the maximum flow", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(5, 2))
+		for range 200 {
+			g := adjacencylist.NewBuilder[string, int]().
+				EdgePolicy(collection.GraphEdgePolicyAllow).
+				Build()
+			left := hashset.NewBuiltinBuilder[string]().Build()
+			leftCount, rightCount := 1+rng.IntN(8), 1+rng.IntN(8)
+			for i := range leftCount {
+				left.Add(fmt.Sprintf("l%d", i))
+				g.AddVertex(fmt.Sprintf("l%d", i))
+			}
+			for range rng.IntN(25) {
+				g.AddEdge(fmt.Sprintf("l%d", rng.IntN(leftCount)), fmt.Sprintf("r%d", rng.IntN(rightCount)), 1)
+			}
+			// A unit network from a super source through the left vertices to
+			// the right vertices and a super sink
+			network := adjacencylist.NewBuilder[string, int]().
+				Directed().
+				Build()
+			for e := range g.Edges() {
+				network.AddEdge("source", e.From(), 1)
+				network.AddEdge(e.From(), e.To(), 1)
+				network.AddEdge(e.To(), "sink", 1)
+			}
+			// ========= [A]ct     =========
+			matching := algo.HopcroftKarp(g, left)
+			// ========= [A]ssert  =========
+			must.Eq(t, algo.MaxFlow(network, "source", "sink").Value(), matching.Len())
+			used := make(map[string]bool)
+			for e := range matching.Edges().Values() {
+				must.True(t, left.Contains(e.From()))
+				must.True(t, g.HasEdge(e.From(), e.To()))
+				must.False(t, used[e.From()] || used[e.To()])
+				used[e.From()], used[e.To()] = true, true
+				must.Eq(t, e.To(), matching.Partner(e.From()).Unwrap())
+				must.Eq(t, e.From(), matching.Partner(e.To()).Unwrap())
+			}
+		}
+	})
+}
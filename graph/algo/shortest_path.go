@@ -0,0 +1,208 @@
+package algo
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/numeric"
+	"codeberg.org/yaadata/bina/heap"
+	"codeberg.org/yaadata/bina/sequence/slice"
+)
+
+// Path is a walk through a graph together with the sum of its edge weights.
+type Path[V comparable, W numeric.Number] struct {
+	// Vertices lists the vertices of the walk from its start to its end.
+	Vertices collection.Slice[V]
+	// Weight is the sum of the weights of the edges along the walk.
+	Weight W
+}
+
+// ShortestPaths holds the shortest paths from one source to every vertex
+// reachable from it.
+type ShortestPaths[V comparable, W numeric.Number] struct {
+	source   V
+	distance map[V]W
+	previous map[V]V
+}
+
+func newShortestPaths[V comparable, W numeric.Number](source V) *ShortestPaths[V, W] {
+	var zero W
+	return &ShortestPaths[V, W]{
+		source:   source,
+		distance: map[V]W{source: zero},
+		previous: make(map[V]V),
+	}
+}
+
+// Source returns the vertex the paths start from.
+func (p *ShortestPaths[V, W]) Source() V {
+	return p.source
+}
+
+// Distance returns the weight of the shortest path to vertex, or None if
+// vertex is not reachable from the source.
+func (p *ShortestPaths[V, W]) Distance(vertex V) Option[W] {
+	if distance, ok := p.distance[vertex]; ok {
+		return Some(distance)
+	}
+	return None[W]()
+}
+
+// Path returns the shortest path from the source to vertex, or None if
+// vertex is not reachable from the source.
+func (p *ShortestPaths[V, W]) Path(vertex V) Option[Path[V, W]] {
+	distance, ok := p.distance[vertex]
+	if !ok {
+		return None[Path[V, W]]()
+	}
+	vertices := []V{vertex}
+	for vertex != p.source {
+		vertex = p.previous[vertex]
+		vertices = append(vertices, vertex)
+	}
+	slices.Reverse(vertices)
+	return Some(Path[V, W]{
+		Vertices: slice.NewBuiltinBuilder[V]().From(vertices...).Build(),
+		Weight:   distance,
+	})
+}
+
+// relax records that vertex can be reached through previous at distance, if
+// that is shorter than what is known. Reports whether it was.
+func (p *ShortestPaths[V, W]) relax(previous, vertex V, distance W) bool {
+	if known, ok := p.distance[vertex]; ok && known <= distance {
+		return false
+	}
+	p.distance[vertex] = distance
+	p.previous[vertex] = previous
+	return true
+}
+
+// NegativeCycleError reports that shortest paths are undefined because a
+// cycle of negative total weight can be traversed any number of times.
+type NegativeCycleError[V comparable] struct {
+	// Cycle lists the vertices of one negative cycle. Each vertex has an edge
+	// to the next one and the last has an edge back to the first.
+	Cycle collection.Slice[V]
+}
+
+func (e *NegativeCycleError[V]) Error() string {
+	return fmt.Sprintf("graph has a negative cycle: %v", slices.Collect(e.Cycle.Values()))
+}
+
+// Dijkstra returns the shortest paths from source to every vertex of g
+// reachable from it. Every edge weight must be non-negative; use
+// [BellmanFord] otherwise. Pending vertices are kept in a pairing heap and
+// moved up with [collection.MergeableHeap.DecreaseKey], so it runs in
+// O(E + V log V) amortised. If source is not in g only source itself is
+// reachable, at distance zero.
+func Dijkstra[V comparable, W numeric.Number](g collection.Graph[V, W], source V) *ShortestPaths[V, W] {
+	return search(g, source, None[V](), func(V) W {
+		var zero W
+		return zero
+	})
+}
+
+// AStar returns the shortest path from source to target, or None if target
+// is not reachable. Every edge weight must be non-negative. heuristic
+// estimates the weight of the shortest path from a vertex to target; the
+// search explores vertices in order of known distance plus estimate. An
+// admissible heuristic, one that never overestimates, yields a shortest
+// path; a consistent one, which also never drops by more than the weight of
+// an edge, additionally lets every vertex be settled once.
+func AStar[V comparable, W numeric.Number](g collection.Graph[V, W], source, target V, heuristic func(vertex V) W) Option[Path[V, W]] {
+	return search(g, source, Some(target), heuristic).Path(target)
+}
+
+// candidate is a vertex waiting in the heap of [search], ordered by priority.
+type candidate[V comparable, W numeric.Number] struct {
+	vertex   V
+	priority W
+}
+
+// search runs A* from source until target is settled, or until every
+// reachable vertex is if target is None. A vertex found again with a
+// shorter distance after it was settled is queued once more, so inconsistent
+// heuristics still give shortest paths.
+func search[V comparable, W numeric.Number](g collection.Graph[V, W], source V, target Option[V], heuristic func(V) W) *ShortestPaths[V, W] {
+	paths := newShortestPaths[V, W](source)
+	pending := heap.NewMergeableBuilder(func(a, b candidate[V, W]) compare.Order {
+		return compare.Order(cmp.Compare(a.priority, b.priority))
+	}).Build()
+	handles := make(map[V]collection.HeapNode[candidate[V, W]])
+	handles[source] = pending.Insert(candidate[V, W]{source, heuristic(source)})
+	for next := pending.Pop(); next.IsSome(); next = pending.Pop() {
+		vertex := next.Unwrap().vertex
+		delete(handles, vertex)
+		if target.IsSome() && target.Unwrap() == vertex {
+			break
+		}
+		for neighbor, weight := range g.Neighbors(vertex) {
+			distance := paths.distance[vertex] + weight
+			if !paths.relax(vertex, neighbor, distance) {
+				continue
+			}
+			queued := candidate[V, W]{neighbor, distance + heuristic(neighbor)}
+			if handle, ok := handles[neighbor]; ok {
+				pending.DecreaseKey(handle, queued)
+			} else {
+				handles[neighbor] = pending.Insert(queued)
+			}
+		}
+	}
+	return paths
+}
+
+// BellmanFord returns the shortest paths from source to every vertex of g
+// reachable from it, allowing negative edge weights, in O(V·E). If a cycle
+// of negative weight is reachable from source the result is an error of
+// type *[NegativeCycleError] naming it. In an undirected graph every
+// negative edge forms such a cycle with itself.
+func BellmanFord[V comparable, W numeric.Number](g collection.Graph[V, W], source V) Result[*ShortestPaths[V, W]] {
+	paths := newShortestPaths[V, W](source)
+	// relaxAll relaxes every edge leaving a reached vertex and returns the
+	// last vertex whose distance dropped.
+	relaxAll := func() Option[V] {
+		last := None[V]()
+		for vertex := range g.Vertices() {
+			distance, ok := paths.distance[vertex]
+			if !ok {
+				continue
+			}
+			for neighbor, weight := range g.Neighbors(vertex) {
+				if paths.relax(vertex, neighbor, distance+weight) {
+					last = Some(neighbor)
+				}
+			}
+		}
+		return last
+	}
+	for range g.Len() - 1 {
+		if relaxAll().IsNone() {
+			return Ok(paths)
+		}
+	}
+	last := relaxAll()
+	if last.IsNone() {
+		return Ok(paths)
+	}
+	// The vertex relaxed in the extra round descends from a negative cycle in
+	// the tree of previous vertices. Stepping back V times lands on it.
+	vertex := last.Unwrap()
+	for range g.Len() {
+		vertex = paths.previous[vertex]
+	}
+	cycle := []V{vertex}
+	for at := paths.previous[vertex]; at != vertex; at = paths.previous[at] {
+		cycle = append(cycle, at)
+	}
+	slices.Reverse(cycle)
+	return Err[*ShortestPaths[V, W]](&NegativeCycleError[V]{
+		Cycle: slice.NewBuiltinBuilder[V]().From(cycle...).Build(),
+	})
+}
@@ -0,0 +1,314 @@
+package algo_test
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	adjacencymatrix "codeberg.org/yaadata/bina/graph/adjacency_matrix"
+	"codeberg.org/yaadata/bina/graph/algo"
+)
+
+// weighted returns an adjacency list graph that allows parallel edges.
+func weighted[W int | float64](directed bool, edges ...edge.Edge[string, W]) collection.Graph[string, W] {
+	builder := adjacencylist.NewBuilder[string, W]().
+		EdgePolicy(collection.GraphEdgePolicyAllow).
+		From(edges...)
+	if directed {
+		builder.Directed()
+	}
+	return builder.Build()
+}
+
+// collect returns the elements of s.
+func collect[T any](s collection.Slice[T]) []T {
+	return slices.Collect(s.Values())
+}
+
+func TestDijkstra(t *testing.T) {
+	// ========= [A]rrange =========
+	g := weighted(true,
+		edge.New("a", "b", 7),
+		edge.New("a", "c", 9),
+		edge.New("a", "f", 14),
+		edge.New("b", "c", 10),
+		edge.New("b", "d", 15),
+		edge.New("c", "d", 11),
+		edge.New("c", "f", 2),
+		edge.New("d", "e", 6),
+		edge.New("f", "e", 9),
+		edge.New("f", "e", 1),
+	)
+	g.AddVertex("lonely")
+	// ========= [A]ct     =========
+	paths := algo.Dijkstra(g, "a")
+
+	// SCENARIO: distances
+	t.Run("Distance", func(t *testing.T) {
+		// ========= [A]ssert  =========
+		must.Eq(t, "a", paths.Source())
+		must.Eq(t, 0, paths.Distance("a").Unwrap())
+		must.Eq(t, 9, paths.Distance("c").Unwrap())
+		must.Eq(t, 11, paths.Distance("f").Unwrap())
+		must.Eq(t, 12, paths.Distance("e").Unwrap())
+		must.True(t, paths.Distance("lonely").IsNone())
+		must.True(t, paths.Distance("missing").IsNone())
+	})
+
+	// SCENARIO: path reconstruction
+	t.Run("Path", func(t *testing.T) {
+		// ========= [A]ct     =========
+		e := paths.Path("e").Unwrap()
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"a", "c", "f", "e"}, collect(e.Vertices))
+		must.Eq(t, 12, e.Weight)
+		must.Eq(t, []string{"a"}, collect(paths.Path("a").Unwrap().Vertices))
+		must.True(t, paths.Path("lonely").IsNone())
+	})
+
+	t.Run("Follows undirected edges both ways", func(t *testing.T) {
+		// ========= [A]rrange =========
+		undirected := weighted(false,
+			edge.New("b", "a", 0.5),
+			edge.New("c", "b", 0.25),
+			edge.New("a", "c", 1.0),
+		)
+		// ========= [A]ct     =========
+		e := algo.Dijkstra(undirected, "a").Path("c").Unwrap()
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"a", "b", "c"}, collect(e.Vertices))
+		must.Eq(t, 0.75, e.Weight)
+	})
+}
+
+func TestAStar(t *testing.T) {
+	// ========= [A]rrange =========
+	// A 10x10 grid with a wall in column 5 that has a gap in row 9
+	type cell struct{ row, col int }
+	g := adjacencylist.NewBuilder[cell, int]().Build()
+	for row := range 10 {
+		for col := range 10 {
+			if col == 5 && row != 9 {
+				continue
+			}
+			if col < 9 && (col != 4 || row == 9) {
+				g.AddEdge(cell{row, col}, cell{row, col + 1}, 1)
+			}
+			if row < 9 && col != 5 {
+				g.AddEdge(cell{row, col}, cell{row + 1, col}, 1)
+			}
+		}
+	}
+	target := cell{0, 9}
+	var expanded int
+	manhattan := func(c cell) int {
+		expanded++
+		return max(target.row-c.row, c.row-target.row) + max(target.col-c.col, c.col-target.col)
+	}
+
+	t.Run("Finds the shortest path", func(t *testing.T) {
+		// ========= [A]ct     =========
+		found := algo.AStar(g, cell{0, 0}, target, manhattan)
+		// ========= [A]ssert  =========
+		must.True(t, found.IsSome())
+		must.Eq(t, 9+9+9, found.Unwrap().Weight)
+		vertices := collect(found.Unwrap().Vertices)
+		must.True(t, vertices[0] == cell{0, 0})
+		must.True(t, vertices[len(vertices)-1] == target)
+		must.True(t, slices.Contains(vertices, cell{9, 5}))
+		must.Eq(t, 27, algo.Dijkstra(g, cell{0, 0}).Distance(target).Unwrap())
+	})
+
+	t.Run("Explores less than Dijkstra", func(t *testing.T) {
+		// ========= [A]rrange =========
+		open := adjacencylist.NewBuilder[cell, int]().Build()
+		for row := range 10 {
+			for col := range 10 {
+				if col < 9 {
+					open.AddEdge(cell{row, col}, cell{row, col + 1}, 1)
+				}
+				if row < 9 {
+					open.AddEdge(cell{row, col}, cell{row + 1, col}, 1)
+				}
+			}
+		}
+		expanded = 0
+		// ========= [A]ct     =========
+		found := algo.AStar(open, cell{0, 4}, target, manhattan)
+		// ========= [A]ssert  =========
+		must.Eq(t, 5, found.Unwrap().Weight)
+		must.Less(t, 50, expanded)
+	})
+
+	t.Run("Reports unreachable targets", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g.AddVertex(cell{-1, -1})
+		// ========= [A]ct     =========
+		found := algo.AStar(g, cell{0, 0}, cell{-1, -1}, func(cell) int { return 0 })
+		// ========= [A]ssert  =========
+		must.True(t, found.IsNone())
+	})
+}
+
+func TestBellmanFord(t *testing.T) {
+	t.Run("Handles negative weights", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("s", "a", 4),
+			edge.New("s", "b", 5),
+			edge.New("b", "a", -3),
+			edge.New("a", "c", 2),
+			edge.New("c", "d", -1),
+		)
+		// ========= [A]ct     =========
+		result := algo.BellmanFord(g, "s")
+		// ========= [A]ssert  =========
+		must.True(t, result.IsOk())
+		paths := result.Unwrap()
+		must.Eq(t, 2, paths.Distance("a").Unwrap())
+		must.Eq(t, 3, paths.Distance("d").Unwrap())
+		must.Eq(t, []string{"s", "b", "a", "c", "d"}, collect(paths.Path("d").Unwrap().Vertices))
+	})
+
+	t.Run("Reports negative cycles", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("s", "a", 1),
+			edge.New("a", "b", 1),
+			edge.New("b", "c", -3),
+			edge.New("c", "a", 1),
+			edge.New("c", "t", 1),
+		)
+		// ========= [A]ct     =========
+		result := algo.BellmanFord(g, "s")
+		// ========= [A]ssert  =========
+		var cycle *algo.NegativeCycleError[string]
+		must.True(t, errors.As(result.UnwrapErr(), &cycle))
+		vertices := collect(cycle.Cycle)
+		must.SliceContainsAll(t, []string{"a", "b", "c"}, vertices)
+		assertCycle(t, g, vertices)
+	})
+
+	t.Run("Ignores unreachable negative cycles", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(true,
+			edge.New("s", "a", 1),
+			edge.New("b", "c", -3),
+			edge.New("c", "b", 1),
+		)
+		// ========= [A]ct     =========
+		result := algo.BellmanFord(g, "s")
+		// ========= [A]ssert  =========
+		must.True(t, result.IsOk())
+		must.True(t, result.Unwrap().Distance("b").IsNone())
+	})
+
+	t.Run("Reports negative undirected edges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := weighted(false, edge.New("s", "a", 2), edge.New("a", "b", -1))
+		// ========= [A]ct     =========
+		result := algo.BellmanFord(g, "s")
+		// ========= [A]ssert  =========
+		var cycle *algo.NegativeCycleError[string]
+		must.True(t, errors.As(result.UnwrapErr(), &cycle))
+		must.SliceContainsAll(t, []string{"a", "b"}, collect(cycle.Cycle))
+	})
+}
+
+// assertCycle checks that vertices form a cycle of negative weight in g.
+func assertCycle[V comparable](t *testing.T, g collection.Graph[V, int], vertices []V) {
+	t.Helper()
+	var total int
+	for i, from := range vertices {
+		weight := g.Weight(from, vertices[(i+1)%len(vertices)])
+		must.True(t, weight.IsSome())
+		total += weight.Unwrap()
+	}
+	must.Negative(t, total)
+}
+
+func TestShortestPathsRandom(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(3, 5))
+	for round := range 40 {
+		directed := round%2 == 0
+		builder := adjacencymatrix.NewBuilder[int, int]().EdgePolicy(collection.GraphEdgePolicyAllow)
+		if directed {
+			builder.Directed()
+		}
+		g := builder.Build()
+		for v := range 12 {
+			g.AddVertex(v)
+		}
+		// Negative weights only on edges from lower to higher vertices of a
+		// directed graph, which cannot form cycles
+		negative := directed && round%4 == 0
+		for range rng.IntN(40) {
+			from, to := rng.IntN(12), rng.IntN(12)
+			weight := rng.IntN(20)
+			if negative && from < to {
+				weight -= 10
+			}
+			if negative && from >= to {
+				continue
+			}
+			g.AddEdge(from, to, weight)
+		}
+		// ========= [A]ct     =========
+		all := algo.FloydWarshall(g).Unwrap()
+		for source := range 12 {
+			bellman := algo.BellmanFord(g, source).Unwrap()
+			var dijkstra *algo.ShortestPaths[int, int]
+			if !negative {
+				dijkstra = algo.Dijkstra(g, source)
+			}
+			// ========= [A]ssert  =========
+			for target := range 12 {
+				expected := all.Distance(source, target)
+				must.Eq(t, expected, bellman.Distance(target))
+				if expected.IsNone() {
+					must.True(t, all.Path(source, target).IsNone())
+					continue
+				}
+				for _, found := range []algo.Path[int, int]{all.Path(source, target).Unwrap(), bellman.Path(target).Unwrap()} {
+					assertPath(t, g, source, target, found)
+					must.Eq(t, expected.Unwrap(), found.Weight)
+				}
+				if dijkstra != nil {
+					must.Eq(t, expected, dijkstra.Distance(target))
+					assertPath(t, g, source, target, dijkstra.Path(target).Unwrap())
+					found := algo.AStar(g, source, target, func(int) int { return 0 })
+					must.Eq(t, expected.Unwrap(), found.Unwrap().Weight)
+				}
+			}
+		}
+	}
+}
+
+// assertPath checks that p walks from source to target along edges of g
+// whose lightest weights add up to p.Weight.
+func assertPath(t *testing.T, g collection.Graph[int, int], source, target int, p algo.Path[int, int]) {
+	t.Helper()
+	vertices := collect(p.Vertices)
+	must.Eq(t, source, vertices[0])
+	must.True(t, vertices[len(vertices)-1] == target)
+	var total int
+	for i := range len(vertices) - 1 {
+		lightest := math.MaxInt
+		for neighbor, weight := range g.Neighbors(vertices[i]) {
+			if neighbor == vertices[i+1] {
+				lightest = min(lightest, weight)
+			}
+		}
+		must.NotEq(t, math.MaxInt, lightest)
+		total += lightest
+	}
+	must.Eq(t, p.Weight, total)
+}
@@ -0,0 +1,75 @@
+package algo
+
+import (
+	"cmp"
+	"slices"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/core/numeric"
+	"codeberg.org/yaadata/bina/heap"
+	"codeberg.org/yaadata/bina/sequence/slice"
+	disjointset "codeberg.org/yaadata/bina/set/disjoint_set"
+	"codeberg.org/yaadata/bina/set/hashset"
+)
+
+// Kruskal returns the edges of a minimum spanning forest of g: a minimum
+// spanning tree of each connected component. It sorts the edges by weight
+// and keeps each one that joins two trees, tracked in a
+// [collection.DisjointSet], in O(E log E). Edges come in the order they were
+// kept, oriented as g yields them. Edge directions are ignored, so a
+// directed graph is treated as its underlying undirected graph. Among edges
+// of equal weight the one g yields first wins, so ties are broken
+// deterministically.
+func Kruskal[V comparable, W numeric.Number](g collection.Graph[V, W]) collection.Slice[edge.Edge[V, W]] {
+	edges := slices.Collect(g.Edges())
+	slices.SortStableFunc(edges, func(a, b edge.Edge[V, W]) int {
+		return cmp.Compare(a.Weight(), b.Weight())
+	})
+	trees := disjointset.NewBuiltinBuilder[V]().Capacity(g.Len()).Build()
+	forest := slice.NewBuiltinBuilder[edge.Edge[V, W]]().Capacity(max(g.Len()-1, 0)).Build()
+	for _, e := range edges {
+		if trees.Union(e.From(), e.To()) {
+			forest.Append(e)
+		}
+	}
+	return forest
+}
+
+// Prim returns the edges of a minimum spanning forest of g, growing one tree
+// at a time from the first vertex not yet covered. Edges leaving the tree
+// wait in a binary heap, so it runs in O(E log E). Edges come in the order
+// they joined a tree, oriented away from it. As with [Kruskal], edge
+// directions are ignored.
+func Prim[V comparable, W numeric.Number](g collection.Graph[V, W]) collection.Slice[edge.Edge[V, W]] {
+	neighbors := undirected(g)
+	covered := hashset.NewBuiltinBuilder[V]().Capacity(g.Len()).Build()
+	forest := slice.NewBuiltinBuilder[edge.Edge[V, W]]().Capacity(max(g.Len()-1, 0)).Build()
+	crossing := heap.NewBuilder(func(a, b edge.Edge[V, W]) compare.Order {
+		return compare.Order(cmp.Compare(a.Weight(), b.Weight()))
+	}).Build()
+	grow := func(vertex V) {
+		covered.Add(vertex)
+		for neighbor, weight := range neighbors(vertex) {
+			if !covered.Contains(neighbor) {
+				crossing.Push(edge.New(vertex, neighbor, weight))
+			}
+		}
+	}
+	for root := range g.Vertices() {
+		if covered.Contains(root) {
+			continue
+		}
+		grow(root)
+		for next := crossing.Pop(); next.IsSome(); next = crossing.Pop() {
+			e := next.Unwrap()
+			if covered.Contains(e.To()) {
+				continue
+			}
+			forest.Append(e)
+			grow(e.To())
+		}
+	}
+	return forest
+}
@@ -0,0 +1,93 @@
+package algo_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	"codeberg.org/yaadata/bina/graph/algo"
+)
+
+func TestMinimumSpanningTree(t *testing.T) {
+	// ========= [A]rrange =========
+	g := weighted(false,
+		edge.New("a", "b", 4),
+		edge.New("a", "h", 8),
+		edge.New("b", "c", 8),
+		edge.New("b", "h", 11),
+		edge.New("c", "d", 7),
+		edge.New("c", "f", 4),
+		edge.New("c", "i", 2),
+		edge.New("d", "e", 9),
+		edge.New("d", "f", 14),
+		edge.New("e", "f", 10),
+		edge.New("f", "g", 2),
+		edge.New("g", "h", 1),
+		edge.New("g", "i", 6),
+		edge.New("h", "i", 7),
+		edge.New("x", "y", 3),
+		edge.New("y", "x", 1),
+	)
+	g.AddVertex("lonely")
+
+	// SCENARIO: Kruskal
+	t.Run("Kruskal", func(t *testing.T) {
+		// ========= [A]ct     =========
+		forest := algo.Kruskal(g)
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{
+			"g-h:1", "y-x:1", "c-i:2", "f-g:2", "a-b:4", "c-f:4", "c-d:7", "a-h:8", "d-e:9",
+		}, format(forest))
+		must.Eq(t, 38, total(forest))
+	})
+
+	// SCENARIO: Prim
+	t.Run("Prim", func(t *testing.T) {
+		// ========= [A]ct     =========
+		forest := algo.Prim(g)
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{
+			"a-b:4", "a-h:8", "h-g:1", "g-f:2", "f-c:4", "c-i:2", "c-d:7", "d-e:9", "x-y:1",
+		}, format(forest))
+		must.Eq(t, 38, total(forest))
+	})
+
+	t.Run("Ignores edge direction", func(t *testing.T) {
+		// ========= [A]rrange =========
+		directed := weighted(true,
+			edge.New("a", "b", 2.5),
+			edge.New("c", "b", 1.5),
+			edge.New("c", "a", 3.0),
+		)
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"c-b:1.5", "a-b:2.5"}, format(algo.Kruskal(directed)))
+		must.Eq(t, []string{"a-b:2.5", "b-c:1.5"}, format(algo.Prim(directed)))
+	})
+
+	t.Run("Random graphs stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(8, 2))
+		for range 100 {
+			g := adjacencylist.NewBuilder[int, float64]().
+				EdgePolicy(collection.GraphEdgePolicyAllow).
+				Build()
+			for v := range 10 {
+				g.AddVertex(v)
+			}
+			for range rng.IntN(30) {
+				g.AddEdge(rng.IntN(10), rng.IntN(10), float64(rng.IntN(8)))
+			}
+			// ========= [A]ct     =========
+			kruskal, prim := algo.Kruskal(g), algo.Prim(g)
+			// ========= [A]ssert  =========
+			trees := algo.StronglyConnectedComponents(g).Len()
+			must.Eq(t, g.Len()-trees, kruskal.Len())
+			must.Eq(t, g.Len()-trees, prim.Len())
+			must.Eq(t, total(kruskal), total(prim))
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package algo
+
+import (
+	"fmt"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/sequence/queue"
+	"codeberg.org/yaadata/bina/sequence/slice"
+	"codeberg.org/yaadata/bina/set/hashset"
+)
+
+// CycleError reports that a graph has no topological order.
+type CycleError[V comparable] struct {
+	// Cycle lists the vertices of one cycle in the graph. Each vertex has an
+	// edge to the next one and the last has an edge back to the first.
+	Cycle collection.Slice[V]
+}
+
+func (e *CycleError[V]) Error() string {
+	return fmt.Sprintf("graph has a cycle: %v", slices.Collect(e.Cycle.Values()))
+}
+
+// TopologicalSort orders the vertices of g so that every edge leads from an
+// earlier vertex to a later one, using Kahn's algorithm. Among vertices that
+// are ready at the same time, the one that became ready first comes first,
+// starting from insertion order.
+//
+// If g has a cycle the result is an error of type *[CycleError] naming one
+// of them. Every edge of an undirected graph, and every self-loop, counts as
+// a cycle.
+func TopologicalSort[V comparable, E any](g collection.Graph[V, E]) Result[collection.Slice[V]] {
+	indegree := make(map[V]int, g.Len())
+	builder := queue.NewBuiltinBuilder[V]()
+	builder.BackedBy(queue.QueueBackedBySinglyLinkedList)
+	ready := builder.Build()
+	for vertex := range g.Vertices() {
+		indegree[vertex] = g.InDegree(vertex)
+		if indegree[vertex] == 0 {
+			ready.Enqueue(vertex)
+		}
+	}
+	order := slice.NewBuiltinBuilder[V]().Capacity(g.Len()).Build()
+	for !ready.IsEmpty() {
+		vertex := ready.Dequeue().Unwrap()
+		order.Append(vertex)
+		for next := range g.Neighbors(vertex) {
+			indegree[next]--
+			if indegree[next] == 0 {
+				ready.Enqueue(next)
+			}
+		}
+	}
+	if order.Len() < g.Len() {
+		return Err[collection.Slice[V]](&CycleError[V]{Cycle: findCycle(g, indegree)})
+	}
+	return Ok(order)
+}
+
+// findCycle returns a cycle among the vertices Kahn's algorithm left behind,
+// those with a positive in-degree. Each of them has a predecessor among the
+// others, so a cycle always exists.
+func findCycle[V comparable, E any](g collection.Graph[V, E], indegree map[V]int) collection.Slice[V] {
+	done := hashset.NewBuiltinBuilder[V]().Build()
+	position := make(map[V]int)
+	var path []V
+	var visit func(vertex V) Option[[]V]
+	visit = func(vertex V) Option[[]V] {
+		position[vertex] = len(path)
+		path = append(path, vertex)
+		for next := range g.Neighbors(vertex) {
+			if indegree[next] == 0 || done.Contains(next) {
+				continue
+			}
+			if start, ok := position[next]; ok {
+				return Some(path[start:])
+			}
+			if cycle := visit(next); cycle.IsSome() {
+				return cycle
+			}
+		}
+		delete(position, vertex)
+		path = path[:len(path)-1]
+		done.Add(vertex)
+		return None[[]V]()
+	}
+	for vertex := range g.Vertices() {
+		if indegree[vertex] == 0 || done.Contains(vertex) {
+			continue
+		}
+		if cycle := visit(vertex); cycle.IsSome() {
+			return slice.NewBuiltinBuilder[V]().From(cycle.Unwrap()...).Build()
+		}
+	}
+	return slice.NewBuiltinBuilder[V]().Build()
+}
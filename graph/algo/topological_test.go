@@ -0,0 +1,118 @@
+package algo_test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	"codeberg.org/yaadata/bina/graph/algo"
+)
+
+func TestTopologicalSort(t *testing.T) {
+	t.Run("Orders a DAG", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(true, []string{"shirt", "tie", "jacket", "belt", "trousers", "shoes", "socks"},
+			[2]string{"shirt", "tie"},
+			[2]string{"tie", "jacket"},
+			[2]string{"shirt", "belt"},
+			[2]string{"belt", "jacket"},
+			[2]string{"trousers", "belt"},
+			[2]string{"trousers", "shoes"},
+			[2]string{"socks", "shoes"},
+		)
+		// ========= [A]ct     =========
+		result := algo.TopologicalSort(g)
+		// ========= [A]ssert  =========
+		must.True(t, result.IsOk())
+		must.Eq(t,
+			[]string{"shirt", "trousers", "socks", "tie", "belt", "shoes", "jacket"},
+			slices.Collect(result.Unwrap().Values()))
+	})
+
+	t.Run("Orders an empty graph", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(true, nil)
+		// ========= [A]ct     =========
+		result := algo.TopologicalSort(g)
+		// ========= [A]ssert  =========
+		must.True(t, result.IsOk())
+		must.Eq(t, 0, result.Unwrap().Len())
+	})
+
+	t.Run("Reports a cycle", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(true, []string{"start"},
+			[2]string{"start", "a"},
+			[2]string{"a", "b"},
+			[2]string{"b", "c"},
+			[2]string{"c", "a"},
+			[2]string{"c", "end"},
+		)
+		// ========= [A]ct     =========
+		result := algo.TopologicalSort(g)
+		// ========= [A]ssert  =========
+		must.True(t, result.IsError())
+		var cycle *algo.CycleError[string]
+		must.True(t, errors.As(result.UnwrapErr(), &cycle))
+		must.Eq(t, []string{"a", "b", "c"}, slices.Collect(cycle.Cycle.Values()))
+		must.EqError(t, result.UnwrapErr(), "graph has a cycle: [a b c]")
+	})
+
+	t.Run("Reports a self-loop", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(true, nil,
+			[2]string{"a", "b"},
+			[2]string{"b", "b"},
+		)
+		// ========= [A]ct     =========
+		result := algo.TopologicalSort(g)
+		// ========= [A]ssert  =========
+		var cycle *algo.CycleError[string]
+		must.True(t, errors.As(result.UnwrapErr(), &cycle))
+		must.Eq(t, []string{"b"}, slices.Collect(cycle.Cycle.Values()))
+	})
+
+	t.Run("Reports undirected edges", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := build(false, []string{"lonely"}, [2]string{"a", "b"})
+		// ========= [A]ct     =========
+		result := algo.TopologicalSort(g)
+		// ========= [A]ssert  =========
+		var cycle *algo.CycleError[string]
+		must.True(t, errors.As(result.UnwrapErr(), &cycle))
+		must.Eq(t, []string{"a", "b"}, slices.Collect(cycle.Cycle.Values()))
+	})
+
+	t.Run("Random graphs stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(4, 7))
+		for range 200 {
+			g := adjacencylist.NewBuilder[int, int]().Directed().Build()
+			for range rng.IntN(16) {
+				g.AddEdge(rng.IntN(12), rng.IntN(12), 0)
+			}
+			// ========= [A]ct     =========
+			result := algo.TopologicalSort(g)
+			// ========= [A]ssert  =========
+			if result.IsOk() {
+				order := slices.Collect(result.Unwrap().Values())
+				must.SliceContainsAll(t, slices.Collect(g.Vertices()), order)
+				for e := range g.Edges() {
+					must.Less(t, slices.Index(order, e.To()), slices.Index(order, e.From()))
+				}
+				continue
+			}
+			var cycle *algo.CycleError[int]
+			must.True(t, errors.As(result.UnwrapErr(), &cycle))
+			vertices := slices.Collect(cycle.Cycle.Values())
+			must.SliceNotEmpty(t, vertices)
+			for i, from := range vertices {
+				must.True(t, g.HasEdge(from, vertices[(i+1)%len(vertices)]))
+			}
+		}
+	})
+}
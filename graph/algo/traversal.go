@@ -0,0 +1,173 @@
+package algo
+
+import (
+	"iter"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/sequence/queue"
+	"codeberg.org/yaadata/bina/sequence/stack"
+	"codeberg.org/yaadata/bina/set/hashset"
+)
+
+// TraversalConfiguration holds options for [BFS] and [DFS].
+type TraversalConfiguration[V comparable] struct {
+	sources Option[[]V]
+	pre     Option[func(V)]
+	post    Option[func(V)]
+}
+
+// TraversalOption configures a traversal.
+type TraversalOption[V comparable] func(cfg *TraversalConfiguration[V])
+
+// WithSources returns an option that starts the traversal from sources, in
+// order, instead of from every vertex of the graph. Sources that are not in
+// the graph are skipped.
+func WithSources[V comparable](sources ...V) TraversalOption[V] {
+	return func(cfg *TraversalConfiguration[V]) {
+		cfg.sources = Some(sources)
+	}
+}
+
+// WithPreOrder returns an option that calls fn with each vertex when it is
+// visited, just before it is yielded.
+func WithPreOrder[V comparable](fn func(vertex V)) TraversalOption[V] {
+	return func(cfg *TraversalConfiguration[V]) {
+		cfg.pre = Some(fn)
+	}
+}
+
+// WithPostOrder returns an option that calls fn with each vertex once it is
+// finished: for [BFS] after its neighbours have been discovered, for [DFS]
+// after every vertex reached from it has been finished. Vertices still open
+// when the caller stops iterating are never finished.
+func WithPostOrder[V comparable](fn func(vertex V)) TraversalOption[V] {
+	return func(cfg *TraversalConfiguration[V]) {
+		cfg.post = Some(fn)
+	}
+}
+
+func configure[V comparable](opts []TraversalOption[V]) *TraversalConfiguration[V] {
+	cfg := &TraversalConfiguration[V]{
+		sources: None[[]V](),
+		pre:     None[func(V)](),
+		post:    None[func(V)](),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// roots returns the vertices to start from: the configured sources or, by
+// default, vertices.
+func (cfg *TraversalConfiguration[V]) roots(vertices iter.Seq[V]) iter.Seq[V] {
+	if cfg.sources.IsSome() {
+		return slices.Values(cfg.sources.Unwrap())
+	}
+	return vertices
+}
+
+func (cfg *TraversalConfiguration[V]) enter(vertex V) {
+	if cfg.pre.IsSome() {
+		cfg.pre.Unwrap()(vertex)
+	}
+}
+
+func (cfg *TraversalConfiguration[V]) exit(vertex V) {
+	if cfg.post.IsSome() {
+		cfg.post.Unwrap()(vertex)
+	}
+}
+
+// BFS returns an iterator over the vertices of g in breadth-first order.
+// Each unvisited source starts a new search, so by default every vertex is
+// yielded exactly once. Neighbours are discovered in the order
+// [collection.Graph.Neighbors] yields them.
+func BFS[V comparable, E any](g collection.Graph[V, E], opts ...TraversalOption[V]) iter.Seq[V] {
+	cfg := configure(opts)
+	return func(yield func(V) bool) {
+		visited := hashset.NewBuiltinBuilder[V]().Build()
+		builder := queue.NewBuiltinBuilder[V]()
+		builder.BackedBy(queue.QueueBackedBySinglyLinkedList)
+		pending := builder.Build()
+		for source := range cfg.roots(g.Vertices()) {
+			if !g.Contains(source) || !visited.Add(source) {
+				continue
+			}
+			pending.Enqueue(source)
+			for !pending.IsEmpty() {
+				vertex := pending.Dequeue().Unwrap()
+				cfg.enter(vertex)
+				if !yield(vertex) {
+					return
+				}
+				for next := range g.Neighbors(vertex) {
+					if visited.Add(next) {
+						pending.Enqueue(next)
+					}
+				}
+				cfg.exit(vertex)
+			}
+		}
+	}
+}
+
+// frame is a vertex on the current DFS path with the neighbours still to be
+// explored from it.
+type frame[V comparable] struct {
+	vertex    V
+	neighbors []V
+	next      int
+}
+
+func newFrame[V comparable, E any](g collection.Graph[V, E], vertex V) *frame[V] {
+	f := &frame[V]{vertex: vertex}
+	for next := range g.Neighbors(vertex) {
+		f.neighbors = append(f.neighbors, next)
+	}
+	return f
+}
+
+// DFS returns an iterator over the vertices of g in depth-first preorder.
+// Each unvisited source starts a new search, so by default every vertex is
+// yielded exactly once. The search keeps the current path on an explicit
+// stack, so it does not grow the goroutine stack on deep graphs.
+func DFS[V comparable, E any](g collection.Graph[V, E], opts ...TraversalOption[V]) iter.Seq[V] {
+	cfg := configure(opts)
+	return func(yield func(V) bool) {
+		visited := hashset.NewBuiltinBuilder[V]().Build()
+		path := stack.NewBuiltinBuilder[*frame[V]]().Build()
+		visit := func(vertex V) bool {
+			cfg.enter(vertex)
+			if !yield(vertex) {
+				return false
+			}
+			path.Push(newFrame(g, vertex))
+			return true
+		}
+		for source := range cfg.roots(g.Vertices()) {
+			if !g.Contains(source) || !visited.Add(source) {
+				continue
+			}
+			if !visit(source) {
+				return
+			}
+			for !path.IsEmpty() {
+				top := path.Peek().Unwrap()
+				if top.next == len(top.neighbors) {
+					path.Pop()
+					cfg.exit(top.vertex)
+					continue
+				}
+				next := top.neighbors[top.next]
+				top.next++
+				if visited.Add(next) && !visit(next) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package algo_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	adjacencylist "codeberg.org/yaadata/bina/graph/adjacency_list"
+	"codeberg.org/yaadata/bina/graph/algo"
+)
+
+// build returns an adjacency list graph that allows parallel edges, holding
+// vertices and then edges, each of weight 1.
+func build(directed bool, vertices []string, edges ...[2]string) collection.Graph[string, int] {
+	builder := adjacencylist.NewBuilder[string, int]().
+		EdgePolicy(collection.GraphEdgePolicyAllow).
+		Vertices(vertices...)
+	if directed {
+		builder.Directed()
+	}
+	g := builder.Build()
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1], 1)
+	}
+	return g
+}
+
+func TestBFS(t *testing.T) {
+	// a - b - d
+	// |   |
+	// c - e   f
+	g := build(false, nil,
+		[2]string{"a", "b"},
+		[2]string{"a", "c"},
+		[2]string{"b", "d"},
+		[2]string{"b", "e"},
+		[2]string{"c", "e"},
+	)
+	g.AddVertex("f")
+
+	t.Run("Visits every vertex", func(t *testing.T) {
+		// ========= [A]ct     =========
+		order := slices.Collect(algo.BFS(g))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"a", "b", "c", "d", "e", "f"}, order)
+	})
+
+	t.Run("Visits from sources", func(t *testing.T) {
+		// ========= [A]ct     =========
+		order := slices.Collect(algo.BFS(g, algo.WithSources("e", "missing")))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"e", "b", "c", "a", "d"}, order)
+	})
+
+	t.Run("Calls hooks", func(t *testing.T) {
+		// ========= [A]rrange =========
+		var events []string
+		// ========= [A]ct     =========
+		for range algo.BFS(g,
+			algo.WithSources("d"),
+			algo.WithPreOrder(func(v string) { events = append(events, "+"+v) }),
+			algo.WithPostOrder(func(v string) { events = append(events, "-"+v) }),
+		) {
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"+d", "-d", "+b", "-b", "+a", "-a", "+e", "-e", "+c", "-c"}, events)
+	})
+
+	t.Run("Stops early", func(t *testing.T) {
+		// ========= [A]rrange =========
+		var finished []string
+		var order []string
+		// ========= [A]ct     =========
+		for v := range algo.BFS(g, algo.WithPostOrder(func(v string) { finished = append(finished, v) })) {
+			order = append(order, v)
+			if v == "c" {
+				break
+			}
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"a", "b", "c"}, order)
+		must.Eq(t, []string{"a", "b"}, finished)
+	})
+
+	t.Run("Follows edge direction", func(t *testing.T) {
+		// ========= [A]rrange =========
+		directed := build(true, nil,
+			[2]string{"b", "a"},
+			[2]string{"b", "c"},
+			[2]string{"c", "a"},
+		)
+		// ========= [A]ct     =========
+		order := slices.Collect(algo.BFS(directed, algo.WithSources("c")))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"c", "a"}, order)
+	})
+}
+
+func TestDFS(t *testing.T) {
+	// a -> b -> d
+	// |    |
+	// v    v
+	// c -> e    f -> a
+	g := build(true, nil,
+		[2]string{"a", "b"},
+		[2]string{"a", "c"},
+		[2]string{"b", "d"},
+		[2]string{"b", "e"},
+		[2]string{"c", "e"},
+		[2]string{"f", "a"},
+	)
+
+	t.Run("Visits every vertex", func(t *testing.T) {
+		// ========= [A]ct     =========
+		order := slices.Collect(algo.DFS(g))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"a", "b", "d", "e", "c", "f"}, order)
+	})
+
+	t.Run("Calls hooks", func(t *testing.T) {
+		// ========= [A]rrange =========
+		var pre, post []string
+		// ========= [A]ct     =========
+		for range algo.DFS(g,
+			algo.WithPreOrder(func(v string) { pre = append(pre, v) }),
+			algo.WithPostOrder(func(v string) { post = append(post, v) }),
+		) {
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"a", "b", "d", "e", "c", "f"}, pre)
+		must.Eq(t, []string{"d", "e", "b", "c", "a", "f"}, post)
+	})
+
+	t.Run("Visits from sources", func(t *testing.T) {
+		// ========= [A]ct     =========
+		order := slices.Collect(algo.DFS(g, algo.WithSources("c", "b")))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"c", "e", "b", "d"}, order)
+	})
+
+	t.Run("Stops early", func(t *testing.T) {
+		// ========= [A]rrange =========
+		var finished []string
+		var order []string
+		// ========= [A]ct     =========
+		for v := range algo.DFS(g, algo.WithPostOrder(func(v string) { finished = append(finished, v) })) {
+			order = append(order, v)
+			if v == "e" {
+				break
+			}
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"a", "b", "d", "e"}, order)
+		must.Eq(t, []string{"d"}, finished)
+	})
+
+	t.Run("Handles deep graphs", func(t *testing.T) {
+		// ========= [A]rrange =========
+		const depth = 100_000
+		path := adjacencylist.NewBuilder[int, int]().Directed().Build()
+		for i := range depth {
+			path.AddEdge(i, i+1, 0)
+		}
+		var finished int
+		// ========= [A]ct     =========
+		var last int
+		for v := range algo.DFS(path, algo.WithPostOrder(func(int) { finished++ })) {
+			last = v
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, depth, last)
+		must.Eq(t, depth+1, finished)
+	})
+
+	t.Run("Follows undirected edges both ways", func(t *testing.T) {
+		// ========= [A]rrange =========
+		undirected := adjacencylist.NewBuilder[string, int]().
+			From(
+				edge.New("a", "b", 0),
+				edge.New("c", "b", 0),
+			).
+			Build()
+		// ========= [A]ct     =========
+		order := slices.Collect(algo.DFS(undirected, algo.WithSources("c")))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"c", "b", "a"}, order)
+	})
+}
@@ -0,0 +1,23 @@
+package builder
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+)
+
+// BaseBuilder defines a fluent builder for [collection.Graph] implementations.
+// The Self type parameter enables method chaining.
+type BaseBuilder[V comparable, E any, Target collection.Graph[V, E], Self BaseBuilder[V, E, Target, Self]] interface {
+	// Build constructs and returns the target graph.
+	Build() Target
+	// Directed makes edges one-way. Default is undirected.
+	Directed() Self
+	// EdgePolicy sets how edges parallel to an existing one are handled.
+	// Default is [collection.GraphEdgePolicyReject].
+	EdgePolicy(policy collection.GraphEdgePolicy) Self
+	// From initializes the graph with the given edges and their endpoints.
+	From(edges ...edge.Edge[V, E]) Self
+	// Vertices initializes the graph with the given vertices, which may be
+	// isolated. They are added before the edges.
+	Vertices(vertices ...V) Self
+}
@@ -0,0 +1,4 @@
+// Package builder provides a builder interface for [collection.Graph] implementations.
+package builder
+
+import _ "codeberg.org/yaadata/bina/core/collection"
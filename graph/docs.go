@@ -0,0 +1,4 @@
+// Package graph provides [collection.Graph] implementations.
+package graph
+
+import _ "codeberg.org/yaadata/bina/core/collection"
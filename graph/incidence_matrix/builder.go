@@ -0,0 +1,11 @@
+package incidencematrix
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/graph/builder"
+)
+
+// Builder is a [builder.BaseBuilder] for incidence matrix graphs.
+type Builder[V comparable, E any, Target collection.Graph[V, E], Self Builder[V, E, Target, Self]] interface {
+	builder.BaseBuilder[V, E, Target, Self]
+}
@@ -0,0 +1,7 @@
+// Package incidencematrix implements builders for [collection.Graph] backed
+// by an incidence matrix, with a row per vertex and a column per edge. Memory
+// grows with V·E, so it mostly suits small graphs and algorithms that work
+// on edge-vertex relationships directly.
+package incidencematrix
+
+import _ "codeberg.org/yaadata/bina/core/collection"
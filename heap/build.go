@@ -0,0 +1,145 @@
+package heap
+
+import (
+	"cmp"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/internal/heap"
+	mergeableheap "codeberg.org/yaadata/bina/internal/mergeable_heap"
+)
+
+// NewBuiltinBuilder returns a [Builder] for creating a min-heap of ordered elements.
+func NewBuiltinBuilder[T cmp.Ordered]() Builder[T, collection.Heap[T], *builder[T]] {
+	return NewBuilder[T](compare.Builtin[T])
+}
+
+// NewBuilder returns a [Builder] for creating a heap whose top element is the
+// one that orders first under fn.
+func NewBuilder[T any](fn func(a, b T) compare.Order) Builder[T, collection.Heap[T], *builder[T]] {
+	return &builder[T]{
+		compare: fn,
+		arity:   None[int](),
+		from:    None[[]T](),
+	}
+}
+
+type builder[T any] struct {
+	compare func(a, b T) compare.Order
+	arity   Option[int]
+	from    Option[[]T]
+}
+
+func (b *builder[T]) Arity(arity int) *builder[T] {
+	b.arity = Some(arity)
+	return b
+}
+
+func (b *builder[T]) From(items ...T) *builder[T] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *builder[T]) FromSlice(slice collection.Slice[T]) *builder[T] {
+	b.from = Some(slices.Collect(slice.Values()))
+	return b
+}
+
+func (b *builder[T]) Build() collection.Heap[T] {
+	arity := b.arity.UnwrapOrElse(func() int {
+		return 2
+	})
+	return heap.NewDAry(b.compare, arity, slices.Clone(b.from.UnwrapOrDefault()))
+}
+
+// NewMinMaxBuiltinBuilder returns a [MinMaxBuilder] for creating a min-max heap of ordered elements.
+func NewMinMaxBuiltinBuilder[T cmp.Ordered]() MinMaxBuilder[T, collection.MinMaxHeap[T], *minMaxBuilder[T]] {
+	return NewMinMaxBuilder[T](compare.Builtin[T])
+}
+
+// NewMinMaxBuilder returns a [MinMaxBuilder] for creating a min-max heap
+// ordered by fn.
+func NewMinMaxBuilder[T any](fn func(a, b T) compare.Order) MinMaxBuilder[T, collection.MinMaxHeap[T], *minMaxBuilder[T]] {
+	return &minMaxBuilder[T]{
+		compare: fn,
+		from:    None[[]T](),
+	}
+}
+
+type minMaxBuilder[T any] struct {
+	compare func(a, b T) compare.Order
+	from    Option[[]T]
+}
+
+func (b *minMaxBuilder[T]) From(items ...T) *minMaxBuilder[T] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *minMaxBuilder[T]) FromSlice(slice collection.Slice[T]) *minMaxBuilder[T] {
+	b.from = Some(slices.Collect(slice.Values()))
+	return b
+}
+
+func (b *minMaxBuilder[T]) Build() collection.MinMaxHeap[T] {
+	return heap.NewMinMax(b.compare, slices.Clone(b.from.UnwrapOrDefault()))
+}
+
+// NewMergeableBuiltinBuilder returns a [MergeableBuilder] for creating a mergeable min-heap of ordered elements.
+func NewMergeableBuiltinBuilder[T cmp.Ordered]() MergeableBuilder[T, collection.MergeableHeap[T], *mergeableBuilder[T]] {
+	return NewMergeableBuilder[T](compare.Builtin[T])
+}
+
+// NewMergeableBuilder returns a [MergeableBuilder] for creating a mergeable
+// heap whose top element is the one that orders first under fn.
+func NewMergeableBuilder[T any](fn func(a, b T) compare.Order) MergeableBuilder[T, collection.MergeableHeap[T], *mergeableBuilder[T]] {
+	return &mergeableBuilder[T]{
+		compare: fn,
+		kind:    None[MergeableHeapKind](),
+		from:    None[[]T](),
+	}
+}
+
+type mergeableBuilder[T any] struct {
+	compare func(a, b T) compare.Order
+	kind    Option[MergeableHeapKind]
+	from    Option[[]T]
+}
+
+func (b *mergeableBuilder[T]) Kind(kind MergeableHeapKind) *mergeableBuilder[T] {
+	b.kind = Some(kind)
+	return b
+}
+
+func (b *mergeableBuilder[T]) From(items ...T) *mergeableBuilder[T] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *mergeableBuilder[T]) FromSlice(slice collection.Slice[T]) *mergeableBuilder[T] {
+	b.from = Some(slices.Collect(slice.Values()))
+	return b
+}
+
+func (b *mergeableBuilder[T]) Build() collection.MergeableHeap[T] {
+	var resp collection.MergeableHeap[T]
+	switch b.kind.UnwrapOrDefault() {
+	case MergeableHeapBinomial:
+		resp = mergeableheap.NewBinomial(b.compare)
+	case MergeableHeapLeftist:
+		resp = mergeableheap.NewLeftist(b.compare)
+	case MergeableHeapSkew:
+		resp = mergeableheap.NewSkew(b.compare)
+	case MergeableHeapFibonacci:
+		resp = mergeableheap.NewFibonacci(b.compare)
+	default:
+		resp = mergeableheap.NewPairing(b.compare)
+	}
+	for _, item := range b.from.UnwrapOrDefault() {
+		resp.Push(item)
+	}
+	return resp
+}
@@ -0,0 +1,65 @@
+package heap
+
+import "codeberg.org/yaadata/bina/core/collection"
+
+// Builder defines the fluent interface for constructing d-ary heaps.
+// Use [NewBuilder] or [NewBuiltinBuilder] to obtain one.
+type Builder[T any, Target collection.Heap[T], Self Builder[T, Target, Self]] interface {
+	// Build constructs and returns the target heap.
+	Build() Target
+	// Arity sets the number of children per node. Default is 2, and values
+	// below 2 are raised to 2.
+	Arity(arity int) Self
+	// From initializes the heap with a copy of the given items.
+	From(items ...T) Self
+	// FromSlice initializes the heap with a copy of the elements of slice.
+	FromSlice(slice collection.Slice[T]) Self
+}
+
+// MinMaxBuilder defines the fluent interface for constructing min-max heaps.
+// Use [NewMinMaxBuilder] or [NewMinMaxBuiltinBuilder] to obtain one.
+type MinMaxBuilder[T any, Target collection.MinMaxHeap[T], Self MinMaxBuilder[T, Target, Self]] interface {
+	// Build constructs and returns the target heap.
+	Build() Target
+	// From initializes the heap with a copy of the given items.
+	From(items ...T) Self
+	// FromSlice initializes the heap with a copy of the elements of slice.
+	FromSlice(slice collection.Slice[T]) Self
+}
+
+// MergeableHeapKind selects the structure behind a [collection.MergeableHeap].
+type MergeableHeapKind int
+
+const (
+	// MergeableHeapPairing uses a pairing heap: O(1) Insert and Meld and
+	// amortized O(log n) Pop and DecreaseKey.
+	MergeableHeapPairing MergeableHeapKind = iota
+	// MergeableHeapBinomial uses a binomial heap: worst-case O(log n) for
+	// every operation.
+	MergeableHeapBinomial
+	// MergeableHeapLeftist uses a leftist heap: worst-case O(log n) Insert,
+	// Pop and Meld.
+	MergeableHeapLeftist
+	// MergeableHeapSkew uses a skew heap: amortized O(log n) for every
+	// operation, with the least bookkeeping per node.
+	MergeableHeapSkew
+	// MergeableHeapFibonacci uses a Fibonacci heap: amortized O(1) Insert,
+	// Meld and DecreaseKey and amortized O(log n) Pop. It suits workloads
+	// that lower keys far more often than they pop, such as shortest path
+	// searches on dense graphs.
+	MergeableHeapFibonacci
+)
+
+// MergeableBuilder defines the fluent interface for constructing mergeable
+// heaps. Use [NewMergeableBuilder] or [NewMergeableBuiltinBuilder] to obtain
+// one.
+type MergeableBuilder[T any, Target collection.MergeableHeap[T], Self MergeableBuilder[T, Target, Self]] interface {
+	// Build constructs and returns the target heap.
+	Build() Target
+	// Kind sets the structure behind the heap. Default is [MergeableHeapPairing].
+	Kind(kind MergeableHeapKind) Self
+	// From initializes the heap with the given items.
+	From(items ...T) Self
+	// FromSlice initializes the heap with the elements of slice.
+	FromSlice(slice collection.Slice[T]) Self
+}
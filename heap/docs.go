@@ -0,0 +1,24 @@
+// Package heap provides [collection.Heap], [collection.MinMaxHeap] and
+// [collection.MergeableHeap] implementations.
+//
+// Heaps built with [NewBuilder] are d-ary heaps, binary unless
+// [Builder.Arity] says otherwise. Heaps built with [NewMinMaxBuilder] are
+// min-max heaps, which can pop from either end:
+//
+//	tasks := heap.NewMinMaxBuilder(byDeadline).From(pending...).Build()
+//	urgent := tasks.PopMin()
+//	slack := tasks.PopMax()
+//
+// Both are backed by a slice and heapify their initial items in O(n).
+//
+// Heaps built with [NewMergeableBuilder] are pointer-based. They give up the
+// compact layout in exchange for cheap [collection.MergeableHeap.Meld] and
+// for handles that can be passed to
+// [collection.MergeableHeap.DecreaseKey]:
+//
+//	queue := heap.NewMergeableBuilder(byDistance).Build()
+//	handle := queue.Insert(start)
+//	queue.DecreaseKey(handle, closer)
+package heap
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,132 @@
+package heap_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/heap"
+)
+
+const benchmarkSize = 10_000
+
+func benchmarkItems() []int {
+	rng := rand.New(rand.NewPCG(1, 2))
+	return rng.Perm(benchmarkSize)
+}
+
+// benchmarkHeaps returns the heaps under comparison. The binary heap is the
+// baseline; it has no native meld and absorbs other heaps by pushing.
+func benchmarkHeaps() map[string]func() collection.Heap[int] {
+	heaps := map[string]func() collection.Heap[int]{
+		"Binary": func() collection.Heap[int] {
+			return heap.NewBuiltinBuilder[int]().Build()
+		},
+	}
+	for name, kind := range mergeableKinds() {
+		heaps[name] = func() collection.Heap[int] {
+			return heap.NewMergeableBuiltinBuilder[int]().Kind(kind).Build()
+		}
+	}
+	return heaps
+}
+
+// meld moves the elements of other into h natively when both are mergeable.
+func meld(h, other collection.Heap[int]) {
+	if h, ok := h.(collection.MergeableHeap[int]); ok {
+		h.Meld(other.(collection.MergeableHeap[int]))
+		return
+	}
+	for value := range other.Values() {
+		h.Push(value)
+	}
+	other.Clear()
+}
+
+// BenchmarkBulkInsert pushes every item and then drains the heap.
+func BenchmarkBulkInsert(b *testing.B) {
+	items := benchmarkItems()
+	for name, newHeap := range benchmarkHeaps() {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				h := newHeap()
+				for _, item := range items {
+					h.Push(item)
+				}
+				for !h.IsEmpty() {
+					h.Pop()
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkInterleavedPop keeps the heap at a steady size, popping once for
+// every two pushes.
+func BenchmarkInterleavedPop(b *testing.B) {
+	items := benchmarkItems()
+	for name, newHeap := range benchmarkHeaps() {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				h := newHeap()
+				for index, item := range items {
+					h.Push(item)
+					if index%2 == 1 {
+						h.Pop()
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFrequentMeld spreads the items over per-worker heaps and
+// repeatedly melds pairs of them until one is left, popping from each
+// survivor along the way.
+func BenchmarkFrequentMeld(b *testing.B) {
+	const workers = 64
+	items := benchmarkItems()
+	for name, newHeap := range benchmarkHeaps() {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				heaps := make([]collection.Heap[int], workers)
+				for index := range heaps {
+					heaps[index] = newHeap()
+				}
+				for index, item := range items {
+					heaps[index%workers].Push(item)
+				}
+				for len(heaps) > 1 {
+					var next []collection.Heap[int]
+					for index := 0; index+1 < len(heaps); index += 2 {
+						meld(heaps[index], heaps[index+1])
+						heaps[index].Pop()
+						next = append(next, heaps[index])
+					}
+					heaps = next
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecreaseKey inserts every item and then lowers each one once, as
+// a shortest path search would.
+func BenchmarkDecreaseKey(b *testing.B) {
+	items := benchmarkItems()
+	for name, kind := range mergeableKinds() {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				h := heap.NewMergeableBuiltinBuilder[int]().Kind(kind).Build()
+				handles := make([]collection.HeapNode[int], len(items))
+				for index, item := range items {
+					handles[index] = h.Insert(item)
+				}
+				for _, handle := range handles {
+					h.DecreaseKey(handle, handle.Value()-benchmarkSize)
+				}
+				h.Pop()
+			}
+		})
+	}
+}
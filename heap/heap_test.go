@@ -0,0 +1,262 @@
+package heap_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/heap"
+	"codeberg.org/yaadata/bina/internal/heaptest"
+	"codeberg.org/yaadata/bina/sequence/slice"
+)
+
+func descending(a, b int) compare.Order {
+	return compare.Builtin(b, a)
+}
+
+func TestHeap(t *testing.T) {
+	for _, arity := range []int{2, 3, 4, 8} {
+		t.Run(fmt.Sprintf("Arity %d", arity), func(t *testing.T) {
+			heaptest.Run(t, func(items ...int) collection.Heap[int] {
+				return heap.NewBuiltinBuilder[int]().
+					Arity(arity).
+					From(items...).
+					Build()
+			})
+		})
+	}
+
+	t.Run("Min-max", func(t *testing.T) {
+		heaptest.Run(t, func(items ...int) collection.Heap[int] {
+			return heap.NewMinMaxBuiltinBuilder[int]().
+				From(items...).
+				Build()
+		})
+	})
+}
+
+func TestHeapBuilder(t *testing.T) {
+	t.Run("Can build with comparator", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := heap.NewBuilder(descending).
+			From(3, 9, 1, 7).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, []int{9, 7, 3, 1}, heaptest.Drain(h))
+	})
+
+	t.Run("Can build from slice", func(t *testing.T) {
+		// ========= [A]rrange =========
+		source := slice.NewBuiltinBuilder[int]().
+			From(4, 2, 6, 1).
+			Build()
+		h := heap.NewBuiltinBuilder[int]().
+			Arity(3).
+			FromSlice(source).
+			Build()
+		// ========= [A]ct     =========
+		h.Push(0)
+		// ========= [A]ssert  =========
+		must.Eq(t, []int{0, 1, 2, 4, 6}, heaptest.Drain(h))
+		must.Eq(t, []int{4, 2, 6, 1}, slices.Collect(source.Values()))
+	})
+
+	t.Run("From copies items", func(t *testing.T) {
+		// ========= [A]rrange =========
+		items := []int{5, 4, 3, 2, 1}
+		h := heap.NewBuiltinBuilder[int]().
+			From(items...).
+			Build()
+		// ========= [A]ct     =========
+		h.Pop()
+		// ========= [A]ssert  =========
+		must.Eq(t, []int{5, 4, 3, 2, 1}, items)
+	})
+
+	t.Run("Arity is at least 2", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := heap.NewBuiltinBuilder[int]().
+			Arity(0).
+			From(2, 3, 1).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, []int{1, 2, 3}, heaptest.Drain(h))
+	})
+}
+
+func TestMinMaxHeap(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := heap.NewMinMaxBuiltinBuilder[int]().
+			Build()
+		// ========= [A]ssert  =========
+		must.True(t, h.PeekMin().IsNone())
+		must.True(t, h.PeekMax().IsNone())
+		must.True(t, h.PopMax().IsNone())
+	})
+
+	t.Run("Both ends work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := heap.NewMinMaxBuiltinBuilder[int]().
+			From(7, 3, 11, 5, 2, 13).
+			Build()
+
+		// SCENARIO: Peek
+		t.Run("Peek", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, h.PeekMin().Unwrap())
+			must.Eq(t, 13, h.PeekMax().Unwrap())
+			must.Eq(t, 2, h.Peek().Unwrap())
+		})
+		// SCENARIO: Pop alternating ends
+		t.Run("Pop", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var actual []int
+			for !h.IsEmpty() {
+				actual = append(actual, h.PopMax().Unwrap())
+				if !h.IsEmpty() {
+					actual = append(actual, h.PopMin().Unwrap())
+				}
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{13, 2, 11, 3, 7, 5}, actual)
+		})
+	})
+
+	t.Run("Can build with comparator", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := heap.NewMinMaxBuilder(descending).
+			From(1, 5, 3).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 5, h.PeekMin().Unwrap())
+		must.Eq(t, 1, h.PeekMax().Unwrap())
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(6, 2))
+		var expected []int
+		for range 100 {
+			expected = append(expected, rng.IntN(500))
+		}
+		h := heap.NewMinMaxBuiltinBuilder[int]().From(expected...).Build()
+		slices.Sort(expected)
+		// ========= [A]ct     =========
+		for range 5_000 {
+			switch rng.IntN(5) {
+			case 0:
+				if len(expected) > 0 {
+					must.Eq(t, expected[0], h.PopMin().Unwrap())
+					expected = expected[1:]
+				}
+			case 1:
+				if len(expected) > 0 {
+					must.Eq(t, expected[len(expected)-1], h.PopMax().Unwrap())
+					expected = expected[:len(expected)-1]
+				}
+			default:
+				item := rng.IntN(500)
+				h.Push(item)
+				index, _ := slices.BinarySearch(expected, item)
+				expected = slices.Insert(expected, index, item)
+			}
+			// ========= [A]ssert  =========
+			if len(expected) > 0 {
+				must.Eq(t, expected[0], h.PeekMin().Unwrap())
+				must.Eq(t, expected[len(expected)-1], h.PeekMax().Unwrap())
+			}
+		}
+	})
+}
+
+func mergeableKinds() map[string]heap.MergeableHeapKind {
+	return map[string]heap.MergeableHeapKind{
+		"Pairing":   heap.MergeableHeapPairing,
+		"Binomial":  heap.MergeableHeapBinomial,
+		"Leftist":   heap.MergeableHeapLeftist,
+		"Skew":      heap.MergeableHeapSkew,
+		"Fibonacci": heap.MergeableHeapFibonacci,
+	}
+}
+
+func TestMergeableHeap(t *testing.T) {
+	for name, kind := range mergeableKinds() {
+		t.Run(name, func(t *testing.T) {
+			heaptest.RunMergeable(t, func(items ...int) collection.MergeableHeap[int] {
+				return heap.NewMergeableBuiltinBuilder[int]().
+					Kind(kind).
+					From(items...).
+					Build()
+			})
+		})
+	}
+
+	t.Run("Meld across kinds works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := heap.NewMergeableBuiltinBuilder[int]().
+			Kind(heap.MergeableHeapBinomial).
+			From(5, 1).
+			Build()
+		other := heap.NewMergeableBuiltinBuilder[int]().
+			Kind(heap.MergeableHeapSkew).
+			From(4, 2).
+			Build()
+		handle := other.Insert(3)
+		// ========= [A]ct     =========
+		h.Meld(other)
+		// ========= [A]ssert  =========
+		must.True(t, other.IsEmpty())
+		must.False(t, h.DecreaseKey(handle, 0))
+		must.Eq(t, []int{1, 2, 3, 4, 5}, heaptest.Drain(h))
+	})
+
+	t.Run("Can build with comparator", func(t *testing.T) {
+		// ========= [A]rrange =========
+		source := slice.NewBuiltinBuilder[int]().
+			From(2, 8, 5).
+			Build()
+		h := heap.NewMergeableBuilder(descending).
+			Kind(heap.MergeableHeapLeftist).
+			FromSlice(source).
+			Build()
+		// ========= [A]ct     =========
+		handle := h.Insert(1)
+		decreased := h.DecreaseKey(handle, 9)
+		// ========= [A]ssert  =========
+		must.True(t, decreased)
+		must.Eq(t, []int{9, 8, 5, 2}, heaptest.Drain(h))
+	})
+}
+
+func TestFibonacciHeap(t *testing.T) {
+	t.Run("Cascading cuts keep the heap consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := heap.NewMergeableBuiltinBuilder[int]().
+			Kind(heap.MergeableHeapFibonacci).
+			Build()
+		handles := make([]collection.HeapNode[int], 64)
+		for index := range handles {
+			handles[index] = h.Insert(1_000 + index)
+		}
+		// Consolidate the roots into deep trees.
+		h.Insert(0)
+		h.Pop()
+		// ========= [A]ct     =========
+		for index := len(handles) - 1; index >= 0; index-- {
+			must.True(t, h.DecreaseKey(handles[index], index))
+			must.Eq(t, index, h.Peek().Unwrap())
+		}
+		// ========= [A]ssert  =========
+		expected := make([]int, len(handles))
+		for index := range expected {
+			expected[index] = index
+		}
+		must.Eq(t, expected, heaptest.Drain(h))
+	})
+}
@@ -0,0 +1,5 @@
+// Package adjacencylist implements [collection.Graph] with per-vertex edge
+// lists, suited to sparse graphs.
+package adjacencylist
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,315 @@
+package adjacencylist
+
+import (
+	"iter"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/internal/footprint"
+)
+
+// half is one end of an edge as seen from the vertex whose list holds it.
+// An undirected edge between distinct vertices is held by both of them, and
+// forward marks the copy held by the vertex it was added from.
+type half[V comparable, E any] struct {
+	to      V
+	weight  E
+	forward bool
+}
+
+// vertex holds the outgoing edges of a vertex and, in a directed graph, the
+// incoming ones with to naming their source.
+type vertex[V comparable, E any] struct {
+	value V
+	out   []half[V, E]
+	in    []half[V, E]
+}
+
+type adjacencyList[V comparable, E any] struct {
+	directed bool
+	policy   collection.GraphEdgePolicy
+	vertices []*vertex[V, E]
+	index    map[V]*vertex[V, E]
+	edges    int
+}
+
+var _ collection.Graph[int, int] = (*adjacencyList[int, int])(nil)
+
+// New returns an empty graph.
+func New[V comparable, E any](directed bool, policy collection.GraphEdgePolicy) *adjacencyList[V, E] {
+	return &adjacencyList[V, E]{
+		directed: directed,
+		policy:   policy,
+		index:    make(map[V]*vertex[V, E]),
+	}
+}
+
+func (g *adjacencyList[V, E]) Len() int {
+	return len(g.vertices)
+}
+
+func (g *adjacencyList[V, E]) Contains(vertex V) bool {
+	_, ok := g.index[vertex]
+	return ok
+}
+
+func (g *adjacencyList[V, E]) IsEmpty() bool {
+	return len(g.vertices) == 0
+}
+
+func (g *adjacencyList[V, E]) Clear() {
+	clear(g.index)
+	clear(g.vertices)
+	g.vertices = g.vertices[:0]
+	g.edges = 0
+}
+
+func (g *adjacencyList[V, E]) Any(pred predicate.Predicate[V]) bool {
+	for vertex := range g.Vertices() {
+		if pred(vertex) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *adjacencyList[V, E]) Count(pred predicate.Predicate[V]) int {
+	var count int
+	for vertex := range g.Vertices() {
+		if pred(vertex) {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *adjacencyList[V, E]) Every(pred predicate.Predicate[V]) bool {
+	for vertex := range g.Vertices() {
+		if !pred(vertex) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *adjacencyList[V, E]) ForEach(fn func(V)) {
+	for vertex := range g.Vertices() {
+		fn(vertex)
+	}
+}
+
+func (g *adjacencyList[V, E]) IsDirected() bool {
+	return g.directed
+}
+
+func (g *adjacencyList[V, E]) EdgePolicy() collection.GraphEdgePolicy {
+	return g.policy
+}
+
+func (g *adjacencyList[V, E]) AddVertex(value V) bool {
+	if g.Contains(value) {
+		return false
+	}
+	g.vertex(value)
+	return true
+}
+
+func (g *adjacencyList[V, E]) AddEdge(from, to V, weight E) bool {
+	source, target := g.vertex(from), g.vertex(to)
+	if index := slices.IndexFunc(source.out, leadsTo[V, E](to)); index >= 0 {
+		switch g.policy {
+		case collection.GraphEdgePolicyReject:
+			return false
+		case collection.GraphEdgePolicyReplace:
+			source.out[index].weight = weight
+			mirror := target.out
+			if g.directed {
+				mirror = target.in
+			}
+			if index := slices.IndexFunc(mirror, leadsTo[V, E](from)); index >= 0 {
+				mirror[index].weight = weight
+			}
+			return true
+		}
+	}
+	source.out = append(source.out, half[V, E]{to: to, weight: weight, forward: true})
+	switch {
+	case g.directed:
+		target.in = append(target.in, half[V, E]{to: from, weight: weight})
+	case from != to:
+		target.out = append(target.out, half[V, E]{to: from, weight: weight})
+	}
+	g.edges++
+	return true
+}
+
+func (g *adjacencyList[V, E]) RemoveVertex(value V) bool {
+	removed, ok := g.index[value]
+	if !ok {
+		return false
+	}
+	for _, h := range removed.out {
+		if h.to == value {
+			continue
+		}
+		neighbor := g.index[h.to]
+		if g.directed {
+			neighbor.in = slices.DeleteFunc(neighbor.in, leadsTo[V, E](value))
+		} else {
+			neighbor.out = slices.DeleteFunc(neighbor.out, leadsTo[V, E](value))
+		}
+	}
+	g.edges -= len(removed.out)
+	for _, h := range removed.in {
+		if h.to != value {
+			neighbor := g.index[h.to]
+			neighbor.out = slices.DeleteFunc(neighbor.out, leadsTo[V, E](value))
+			g.edges--
+		}
+	}
+	delete(g.index, value)
+	g.vertices = slices.DeleteFunc(g.vertices, func(v *vertex[V, E]) bool {
+		return v == removed
+	})
+	return true
+}
+
+func (g *adjacencyList[V, E]) RemoveEdge(from, to V) bool {
+	source, ok := g.index[from]
+	if !ok {
+		return false
+	}
+	target := g.index[to]
+	before := len(source.out)
+	source.out = slices.DeleteFunc(source.out, leadsTo[V, E](to))
+	removed := before - len(source.out)
+	if removed == 0 {
+		return false
+	}
+	switch {
+	case g.directed:
+		target.in = slices.DeleteFunc(target.in, leadsTo[V, E](from))
+	case from != to:
+		target.out = slices.DeleteFunc(target.out, leadsTo[V, E](from))
+	}
+	g.edges -= removed
+	return true
+}
+
+func (g *adjacencyList[V, E]) HasEdge(from, to V) bool {
+	return g.Weight(from, to).IsSome()
+}
+
+func (g *adjacencyList[V, E]) Weight(from, to V) Option[E] {
+	source, ok := g.index[from]
+	if !ok {
+		return None[E]()
+	}
+	if index := slices.IndexFunc(source.out, leadsTo[V, E](to)); index >= 0 {
+		return Some(source.out[index].weight)
+	}
+	return None[E]()
+}
+
+func (g *adjacencyList[V, E]) Neighbors(value V) iter.Seq2[V, E] {
+	return func(yield func(V, E) bool) {
+		source, ok := g.index[value]
+		if !ok {
+			return
+		}
+		for _, h := range source.out {
+			if !yield(h.to, h.weight) {
+				return
+			}
+		}
+	}
+}
+
+func (g *adjacencyList[V, E]) InDegree(value V) int {
+	target, ok := g.index[value]
+	if !ok {
+		return 0
+	}
+	if g.directed {
+		return len(target.in)
+	}
+	return len(target.out)
+}
+
+func (g *adjacencyList[V, E]) OutDegree(value V) int {
+	source, ok := g.index[value]
+	if !ok {
+		return 0
+	}
+	return len(source.out)
+}
+
+func (g *adjacencyList[V, E]) EdgeCount() int {
+	return g.edges
+}
+
+func (g *adjacencyList[V, E]) Vertices() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range g.vertices {
+			if !yield(v.value) {
+				return
+			}
+		}
+	}
+}
+
+func (g *adjacencyList[V, E]) Edges() iter.Seq[edge.Edge[V, E]] {
+	return func(yield func(edge.Edge[V, E]) bool) {
+		for _, v := range g.vertices {
+			for _, h := range v.out {
+				if h.forward && !yield(edge.New(v.value, h.to, h.weight)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (g *adjacencyList[V, E]) MemoryFootprint() int {
+	bytes := footprint.Of[adjacencyList[V, E]]() +
+		footprint.Slice[*vertex[V, E]](cap(g.vertices)) +
+		footprint.Map[V, *vertex[V, E]](len(g.index))
+	for _, v := range g.vertices {
+		bytes += footprint.Of[vertex[V, E]]() +
+			footprint.Slice[half[V, E]](cap(v.out)) +
+			footprint.Slice[half[V, E]](cap(v.in))
+	}
+	return bytes
+}
+
+// Footprint estimates the bytes held by a graph with the given numbers of
+// vertices and edges, assuming slices without spare capacity. Directed and
+// undirected edges alike are stored twice.
+func Footprint[V comparable, E any](vertices, edges int, _ bool) int {
+	return footprint.Of[adjacencyList[V, E]]() +
+		footprint.Slice[*vertex[V, E]](vertices) +
+		footprint.Map[V, *vertex[V, E]](vertices) +
+		vertices*footprint.Of[vertex[V, E]]() +
+		2*edges*footprint.Of[half[V, E]]()
+}
+
+// vertex returns the vertex holding value, adding it first if absent.
+func (g *adjacencyList[V, E]) vertex(value V) *vertex[V, E] {
+	if v, ok := g.index[value]; ok {
+		return v
+	}
+	v := &vertex[V, E]{value: value}
+	g.index[value] = v
+	g.vertices = append(g.vertices, v)
+	return v
+}
+
+func leadsTo[V comparable, E any](to V) func(half[V, E]) bool {
+	return func(h half[V, E]) bool {
+		return h.to == to
+	}
+}
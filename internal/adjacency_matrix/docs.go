@@ -0,0 +1,5 @@
+// Package adjacencymatrix implements [collection.Graph] with a vertex by
+// vertex matrix, suited to small and dense graphs.
+package adjacencymatrix
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,307 @@
+package adjacencymatrix
+
+import (
+	"iter"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/internal/footprint"
+)
+
+// entry is one edge stored in a cell. An undirected edge between distinct
+// vertices is stored in both of its cells, and forward marks the copy in the
+// row of the vertex it was added from.
+type entry[E any] struct {
+	weight  E
+	forward bool
+}
+
+// adjacencyMatrix stores in cells[i][j] the edges from the i-th vertex to the
+// j-th. A cell is nil when the vertices are not adjacent and holds more than
+// one entry only when parallel edges are allowed.
+type adjacencyMatrix[V comparable, E any] struct {
+	directed bool
+	policy   collection.GraphEdgePolicy
+	vertices []V
+	index    map[V]int
+	cells    [][][]entry[E]
+	edges    int
+}
+
+var _ collection.Graph[int, int] = (*adjacencyMatrix[int, int])(nil)
+
+// New returns an empty graph.
+func New[V comparable, E any](directed bool, policy collection.GraphEdgePolicy) *adjacencyMatrix[V, E] {
+	return &adjacencyMatrix[V, E]{
+		directed: directed,
+		policy:   policy,
+		index:    make(map[V]int),
+	}
+}
+
+func (g *adjacencyMatrix[V, E]) Len() int {
+	return len(g.vertices)
+}
+
+func (g *adjacencyMatrix[V, E]) Contains(vertex V) bool {
+	_, ok := g.index[vertex]
+	return ok
+}
+
+func (g *adjacencyMatrix[V, E]) IsEmpty() bool {
+	return len(g.vertices) == 0
+}
+
+func (g *adjacencyMatrix[V, E]) Clear() {
+	clear(g.index)
+	clear(g.vertices)
+	g.vertices = g.vertices[:0]
+	g.cells = nil
+	g.edges = 0
+}
+
+func (g *adjacencyMatrix[V, E]) Any(pred predicate.Predicate[V]) bool {
+	return slices.ContainsFunc(g.vertices, pred)
+}
+
+func (g *adjacencyMatrix[V, E]) Count(pred predicate.Predicate[V]) int {
+	var count int
+	for _, vertex := range g.vertices {
+		if pred(vertex) {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *adjacencyMatrix[V, E]) Every(pred predicate.Predicate[V]) bool {
+	for _, vertex := range g.vertices {
+		if !pred(vertex) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *adjacencyMatrix[V, E]) ForEach(fn func(V)) {
+	for _, vertex := range g.vertices {
+		fn(vertex)
+	}
+}
+
+func (g *adjacencyMatrix[V, E]) IsDirected() bool {
+	return g.directed
+}
+
+func (g *adjacencyMatrix[V, E]) EdgePolicy() collection.GraphEdgePolicy {
+	return g.policy
+}
+
+func (g *adjacencyMatrix[V, E]) AddVertex(vertex V) bool {
+	if g.Contains(vertex) {
+		return false
+	}
+	g.vertex(vertex)
+	return true
+}
+
+// AddEdge runs in O(1) once both vertices are present. Adding a vertex grows
+// every row and costs O(n).
+func (g *adjacencyMatrix[V, E]) AddEdge(from, to V, weight E) bool {
+	i, j := g.vertex(from), g.vertex(to)
+	if len(g.cells[i][j]) > 0 {
+		switch g.policy {
+		case collection.GraphEdgePolicyReject:
+			return false
+		case collection.GraphEdgePolicyReplace:
+			g.cells[i][j][0].weight = weight
+			if !g.directed {
+				g.cells[j][i][0].weight = weight
+			}
+			return true
+		}
+	}
+	g.cells[i][j] = append(g.cells[i][j], entry[E]{weight: weight, forward: true})
+	if !g.directed && i != j {
+		g.cells[j][i] = append(g.cells[j][i], entry[E]{weight: weight})
+	}
+	g.edges++
+	return true
+}
+
+// RemoveVertex removes a row and a column, compacting the matrix in O(n²).
+func (g *adjacencyMatrix[V, E]) RemoveVertex(vertex V) bool {
+	i, ok := g.index[vertex]
+	if !ok {
+		return false
+	}
+	g.edges -= g.OutDegree(vertex)
+	if g.directed {
+		g.edges -= g.InDegree(vertex) - len(g.cells[i][i])
+	}
+	g.cells = slices.Delete(g.cells, i, i+1)
+	for row := range g.cells {
+		g.cells[row] = slices.Delete(g.cells[row], i, i+1)
+	}
+	g.vertices = slices.Delete(g.vertices, i, i+1)
+	delete(g.index, vertex)
+	for index, v := range g.vertices[i:] {
+		g.index[v] = i + index
+	}
+	return true
+}
+
+func (g *adjacencyMatrix[V, E]) RemoveEdge(from, to V) bool {
+	i, j, ok := g.cell(from, to)
+	if !ok {
+		return false
+	}
+	g.edges -= len(g.cells[i][j])
+	g.cells[i][j] = nil
+	if !g.directed {
+		g.cells[j][i] = nil
+	}
+	return true
+}
+
+func (g *adjacencyMatrix[V, E]) HasEdge(from, to V) bool {
+	_, _, ok := g.cell(from, to)
+	return ok
+}
+
+func (g *adjacencyMatrix[V, E]) Weight(from, to V) Option[E] {
+	i, j, ok := g.cell(from, to)
+	if !ok {
+		return None[E]()
+	}
+	return Some(g.cells[i][j][0].weight)
+}
+
+// Neighbors scans a whole row and runs in O(n).
+func (g *adjacencyMatrix[V, E]) Neighbors(vertex V) iter.Seq2[V, E] {
+	return func(yield func(V, E) bool) {
+		i, ok := g.index[vertex]
+		if !ok {
+			return
+		}
+		for j, cell := range g.cells[i] {
+			for _, e := range cell {
+				if !yield(g.vertices[j], e.weight) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (g *adjacencyMatrix[V, E]) InDegree(vertex V) int {
+	j, ok := g.index[vertex]
+	if !ok {
+		return 0
+	}
+	if !g.directed {
+		return g.OutDegree(vertex)
+	}
+	var degree int
+	for _, row := range g.cells {
+		degree += len(row[j])
+	}
+	return degree
+}
+
+func (g *adjacencyMatrix[V, E]) OutDegree(vertex V) int {
+	i, ok := g.index[vertex]
+	if !ok {
+		return 0
+	}
+	var degree int
+	for _, cell := range g.cells[i] {
+		degree += len(cell)
+	}
+	return degree
+}
+
+func (g *adjacencyMatrix[V, E]) EdgeCount() int {
+	return g.edges
+}
+
+func (g *adjacencyMatrix[V, E]) Vertices() iter.Seq[V] {
+	return slices.Values(g.vertices)
+}
+
+// Edges yields edges in row-major order of the matrix.
+func (g *adjacencyMatrix[V, E]) Edges() iter.Seq[edge.Edge[V, E]] {
+	return func(yield func(edge.Edge[V, E]) bool) {
+		for i, row := range g.cells {
+			for j, cell := range row {
+				for _, e := range cell {
+					if e.forward && !yield(edge.New(g.vertices[i], g.vertices[j], e.weight)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func (g *adjacencyMatrix[V, E]) MemoryFootprint() int {
+	bytes := footprint.Of[adjacencyMatrix[V, E]]() +
+		footprint.Slice[V](cap(g.vertices)) +
+		footprint.Map[V, int](len(g.index)) +
+		footprint.Slice[[][]entry[E]](cap(g.cells))
+	for _, row := range g.cells {
+		bytes += footprint.Slice[[]entry[E]](cap(row))
+		for _, cell := range row {
+			bytes += footprint.Slice[entry[E]](cap(cell))
+		}
+	}
+	return bytes
+}
+
+// Footprint estimates the bytes held by a graph with the given numbers of
+// vertices and edges, assuming slices without spare capacity.
+func Footprint[V comparable, E any](vertices, edges int, directed bool) int {
+	if !directed {
+		edges *= 2
+	}
+	return footprint.Of[adjacencyMatrix[V, E]]() +
+		footprint.Slice[V](vertices) +
+		footprint.Map[V, int](vertices) +
+		footprint.Slice[[][]entry[E]](vertices) +
+		vertices*footprint.Slice[[]entry[E]](vertices) +
+		edges*footprint.Of[entry[E]]()
+}
+
+// vertex returns the index of vertex, adding a row and a column for it first
+// if absent.
+func (g *adjacencyMatrix[V, E]) vertex(vertex V) int {
+	if i, ok := g.index[vertex]; ok {
+		return i
+	}
+	i := len(g.vertices)
+	g.index[vertex] = i
+	g.vertices = append(g.vertices, vertex)
+	for row := range g.cells {
+		g.cells[row] = append(g.cells[row], nil)
+	}
+	g.cells = append(g.cells, make([][]entry[E], i+1))
+	return i
+}
+
+// cell returns the row and column of the cell holding the edges from from to
+// to. It reports false if either vertex is absent or the cell is empty.
+func (g *adjacencyMatrix[V, E]) cell(from, to V) (int, int, bool) {
+	i, ok := g.index[from]
+	if !ok {
+		return 0, 0, false
+	}
+	j, ok := g.index[to]
+	if !ok || len(g.cells[i][j]) == 0 {
+		return 0, 0, false
+	}
+	return i, j, true
+}
@@ -0,0 +1,4 @@
+// Package avl implements [collection.AVLTree].
+package avl
+
+import _ "codeberg.org/yaadata/bina/core/collection"
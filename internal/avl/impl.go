@@ -0,0 +1,286 @@
+package avl
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/where"
+	searchtree "codeberg.org/yaadata/bina/internal/search_tree"
+)
+
+type avlTree[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	root    *node[K, V]
+	len     int
+}
+
+var _ collection.AVLTree[int, int] = (*avlTree[int, int])(nil)
+
+// New returns an empty AVL tree whose keys are ordered by fn.
+func New[K any, V any](fn func(a, b K) compare.Order) *avlTree[K, V] {
+	return &avlTree[K, V]{
+		compare: fn,
+		root:    nil,
+		len:     0,
+	}
+}
+
+func (t *avlTree[K, V]) Len() int {
+	return t.len
+}
+
+func (t *avlTree[K, V]) Contains(element K) bool {
+	return t.find(element) != nil
+}
+
+func (t *avlTree[K, V]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *avlTree[K, V]) Clear() {
+	t.root = nil
+	t.len = 0
+}
+
+func (t *avlTree[K, V]) Any(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *avlTree[K, V]) Every(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if !pred(kv.New(key, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *avlTree[K, V]) Count(pred predicate.Predicate[kv.Pair[K, V]]) int {
+	var count int
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *avlTree[K, V]) ForEach(fn func(pair kv.Pair[K, V])) {
+	for key, value := range t.All() {
+		fn(kv.New(key, value))
+	}
+}
+
+func (t *avlTree[K, V]) Delete(key K) Option[V] {
+	var deleted Option[V]
+	t.root, deleted = t.delete(t.root, key)
+	if deleted.IsSome() {
+		t.len--
+	}
+	return deleted
+}
+
+func (t *avlTree[K, V]) Get(key K) Option[V] {
+	n := t.find(key)
+	if n == nil {
+		return None[V]()
+	}
+	return Some(n.value)
+}
+
+func (t *avlTree[K, V]) Put(key K, value V) {
+	var inserted bool
+	t.root, inserted = t.insert(t.root, key, value)
+	if inserted {
+		t.len++
+	}
+}
+
+// Height returns the height of the tree. Heights are maintained on every
+// node, so this runs in O(1).
+func (t *avlTree[K, V]) Height() int {
+	return height(t.root)
+}
+
+func (t *avlTree[K, V]) Min() Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	n := minimum(t.root)
+	return Some(kv.New(n.key, n.value))
+}
+
+func (t *avlTree[K, V]) Max() Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	n := maximum(t.root)
+	return Some(kv.New(n.key, n.value))
+}
+
+func (t *avlTree[K, V]) Floor(key K) Option[kv.Pair[K, V]] {
+	var candidate *node[K, V]
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsEqual():
+			return Some(kv.New(current.key, current.value))
+		case order.IsLess():
+			current = current.left
+		default:
+			candidate = current
+			current = current.right
+		}
+	}
+	if candidate == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(candidate.key, candidate.value))
+}
+
+func (t *avlTree[K, V]) Ceiling(key K) Option[kv.Pair[K, V]] {
+	var candidate *node[K, V]
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsEqual():
+			return Some(kv.New(current.key, current.value))
+		case order.IsGreater():
+			current = current.right
+		default:
+			candidate = current
+			current = current.left
+		}
+	}
+	if candidate == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(candidate.key, candidate.value))
+}
+
+func (t *avlTree[K, V]) Range(opts ...where.WhereOption[K]) iter.Seq2[K, V] {
+	return t.walker().Range(t.compare, opts...)
+}
+
+func (t *avlTree[K, V]) All(opts ...collection.SearchTreeTraversalOption) iter.Seq2[K, V] {
+	return t.walker().All(opts...)
+}
+
+// walker describes the nodes of the tree to the shared traversals.
+func (t *avlTree[K, V]) walker() searchtree.Walker[K, V, *node[K, V]] {
+	return searchtree.Walker[K, V, *node[K, V]]{
+		Root: func() *node[K, V] {
+			return t.root
+		},
+		Left: func(n *node[K, V]) *node[K, V] {
+			return n.left
+		},
+		Right: func(n *node[K, V]) *node[K, V] {
+			return n.right
+		},
+		Entry: func(n *node[K, V]) (K, V) {
+			return n.key, n.value
+		},
+	}
+}
+
+func (t *avlTree[K, V]) Root() Option[collection.AVLTreeNode[K, V]] {
+	return someNode(t.root)
+}
+
+func (t *avlTree[K, V]) GetNode(key K) Option[collection.AVLTreeNode[K, V]] {
+	return someNode(t.find(key))
+}
+
+func (t *avlTree[K, V]) find(key K) *node[K, V] {
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsLess():
+			current = current.left
+		case order.IsGreater():
+			current = current.right
+		default:
+			return current
+		}
+	}
+	return nil
+}
+
+// insert adds or updates key in the subtree rooted at n and returns the new
+// subtree root, reporting whether a node was added.
+func (t *avlTree[K, V]) insert(n *node[K, V], key K, value V) (*node[K, V], bool) {
+	if n == nil {
+		return &node[K, V]{
+			key:    key,
+			value:  value,
+			height: 1,
+		}, true
+	}
+	var inserted bool
+	order := t.compare(key, n.key)
+	switch {
+	case order.IsLess():
+		n.left, inserted = t.insert(n.left, key, value)
+	case order.IsGreater():
+		n.right, inserted = t.insert(n.right, key, value)
+	default:
+		n.value = value
+		return n, false
+	}
+	return rebalance(n), inserted
+}
+
+// delete removes key from the subtree rooted at n and returns the new subtree
+// root along with the removed value.
+func (t *avlTree[K, V]) delete(n *node[K, V], key K) (*node[K, V], Option[V]) {
+	if n == nil {
+		return nil, None[V]()
+	}
+	var deleted Option[V]
+	order := t.compare(key, n.key)
+	switch {
+	case order.IsLess():
+		n.left, deleted = t.delete(n.left, key)
+	case order.IsGreater():
+		n.right, deleted = t.delete(n.right, key)
+	default:
+		deleted = Some(n.value)
+		if n.left == nil {
+			return n.right, deleted
+		}
+		if n.right == nil {
+			return n.left, deleted
+		}
+		// Replace n with its in-order successor
+		successor := minimum(n.right)
+		n.right = deleteMin(n.right)
+		successor.left = n.left
+		successor.right = n.right
+		n = successor
+	}
+	return rebalance(n), deleted
+}
+
+// deleteMin unlinks the smallest node of the subtree rooted at n and returns
+// the new subtree root.
+func deleteMin[K any, V any](n *node[K, V]) *node[K, V] {
+	if n.left == nil {
+		return n.right
+	}
+	n.left = deleteMin(n.left)
+	return rebalance(n)
+}
@@ -0,0 +1,126 @@
+package avl
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+type node[K any, V any] struct {
+	key    K
+	value  V
+	height int
+	left   *node[K, V]
+	right  *node[K, V]
+}
+
+// compile time interface guard check
+var _ collection.AVLTreeNode[int, int] = (*node[int, int])(nil)
+
+func (n *node[K, V]) Key() K {
+	return n.key
+}
+
+func (n *node[K, V]) Value() V {
+	return n.value
+}
+
+func (n *node[K, V]) Left() Option[collection.AVLTreeNode[K, V]] {
+	return someNode(n.left)
+}
+
+func (n *node[K, V]) Right() Option[collection.AVLTreeNode[K, V]] {
+	return someNode(n.right)
+}
+
+func (n *node[K, V]) Children() iter.Seq[collection.AVLTreeNode[K, V]] {
+	return func(yield func(collection.AVLTreeNode[K, V]) bool) {
+		if n.left != nil && !yield(n.left) {
+			return
+		}
+		if n.right != nil {
+			yield(n.right)
+		}
+	}
+}
+
+func (n *node[K, V]) BalanceFactor() int {
+	return height(n.right) - height(n.left)
+}
+
+func (n *node[K, V]) Height() int {
+	return n.height
+}
+
+func someNode[K any, V any](n *node[K, V]) Option[collection.AVLTreeNode[K, V]] {
+	if n == nil {
+		return None[collection.AVLTreeNode[K, V]]()
+	}
+	var res collection.AVLTreeNode[K, V] = n
+	return Some(res)
+}
+
+func height[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *node[K, V]) update() {
+	n.height = 1 + max(height(n.left), height(n.right))
+}
+
+func rotateLeft[K any, V any](n *node[K, V]) *node[K, V] {
+	pivot := n.right
+	n.right = pivot.left
+	pivot.left = n
+	n.update()
+	pivot.update()
+	return pivot
+}
+
+func rotateRight[K any, V any](n *node[K, V]) *node[K, V] {
+	pivot := n.left
+	n.left = pivot.right
+	pivot.right = n
+	n.update()
+	pivot.update()
+	return pivot
+}
+
+// rebalance recomputes the height of n and applies the single or double
+// rotation needed to bring its balance factor back within [-1, 1].
+func rebalance[K any, V any](n *node[K, V]) *node[K, V] {
+	n.update()
+	switch balance := n.BalanceFactor(); {
+	case balance > 1:
+		if n.right.BalanceFactor() < 0 {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	case balance < -1:
+		if n.left.BalanceFactor() > 0 {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	default:
+		return n
+	}
+}
+
+func minimum[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func maximum[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
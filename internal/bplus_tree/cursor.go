@@ -0,0 +1,69 @@
+package bplustree
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+)
+
+// cursor points at a position within a leaf. A nil leaf means unpositioned.
+type cursor[K any, V any] struct {
+	tree  *bplusTree[K, V]
+	leaf  *node[K, V]
+	index int
+}
+
+var _ collection.BPlusTreeCursor[int, int] = (*cursor[int, int])(nil)
+
+func (c *cursor[K, V]) Current() Option[kv.Pair[K, V]] {
+	if c.leaf == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(c.leaf.keys[c.index], c.leaf.values[c.index]))
+}
+
+func (c *cursor[K, V]) First() Option[kv.Pair[K, V]] {
+	c.leaf, c.index = c.tree.head, 0
+	return c.Current()
+}
+
+func (c *cursor[K, V]) Last() Option[kv.Pair[K, V]] {
+	c.leaf = c.tree.tail
+	if c.leaf != nil {
+		c.index = len(c.leaf.keys) - 1
+	}
+	return c.Current()
+}
+
+func (c *cursor[K, V]) Next() Option[kv.Pair[K, V]] {
+	switch {
+	case c.leaf == nil:
+		return c.First()
+	case c.index+1 < len(c.leaf.keys):
+		c.index++
+	default:
+		c.leaf, c.index = c.leaf.next, 0
+	}
+	return c.Current()
+}
+
+func (c *cursor[K, V]) Prev() Option[kv.Pair[K, V]] {
+	switch {
+	case c.leaf == nil:
+		return c.Last()
+	case c.index > 0:
+		c.index--
+	default:
+		c.leaf = c.leaf.previous
+		if c.leaf != nil {
+			c.index = len(c.leaf.keys) - 1
+		}
+	}
+	return c.Current()
+}
+
+func (c *cursor[K, V]) Seek(key K) Option[kv.Pair[K, V]] {
+	c.leaf, c.index = c.tree.seek(key)
+	return c.Current()
+}
@@ -0,0 +1,4 @@
+// Package bplustree implements [collection.BPlusTree].
+package bplustree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,454 @@
+package bplustree
+
+import (
+	"iter"
+	"slices"
+	"sort"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+type bplusTree[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	head    *node[K, V]
+	tail    *node[K, V]
+	root    *node[K, V]
+	height  int
+	len     int
+	order   int
+}
+
+var _ collection.BPlusTree[int, int] = (*bplusTree[int, int])(nil)
+
+// New returns an empty B+ tree. Every node other than the root holds between
+// order-1 and 2*order-1 keys; orders below 2 are raised to 2.
+func New[K any, V any](order int, fn func(a, b K) compare.Order) *bplusTree[K, V] {
+	return &bplusTree[K, V]{
+		compare: fn,
+		head:    nil,
+		tail:    nil,
+		root:    nil,
+		height:  0,
+		len:     0,
+		order:   max(order, 2),
+	}
+}
+
+func (t *bplusTree[K, V]) maxKeys() int {
+	return 2*t.order - 1
+}
+
+func (t *bplusTree[K, V]) minKeys() int {
+	return t.order - 1
+}
+
+func (t *bplusTree[K, V]) Len() int {
+	return t.len
+}
+
+func (t *bplusTree[K, V]) Contains(element K) bool {
+	return t.Get(element).IsSome()
+}
+
+func (t *bplusTree[K, V]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *bplusTree[K, V]) Clear() {
+	t.head = nil
+	t.tail = nil
+	t.root = nil
+	t.height = 0
+	t.len = 0
+}
+
+func (t *bplusTree[K, V]) Any(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *bplusTree[K, V]) Every(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if !pred(kv.New(key, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *bplusTree[K, V]) Count(pred predicate.Predicate[kv.Pair[K, V]]) int {
+	var count int
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *bplusTree[K, V]) ForEach(fn func(pair kv.Pair[K, V])) {
+	for key, value := range t.All() {
+		fn(kv.New(key, value))
+	}
+}
+
+func (t *bplusTree[K, V]) Delete(key K) Option[V] {
+	if t.root == nil {
+		return None[V]()
+	}
+	deleted := t.delete(t.root, key)
+	if deleted.IsNone() {
+		return deleted
+	}
+	t.len--
+	switch {
+	case t.root.isLeaf() && len(t.root.keys) == 0:
+		t.Clear()
+	case !t.root.isLeaf() && len(t.root.keys) == 0:
+		// Root lost its last separator after a merge; promote its only child
+		t.root = t.root.children[0]
+		t.height--
+	}
+	return deleted
+}
+
+func (t *bplusTree[K, V]) Get(key K) Option[V] {
+	if t.root == nil {
+		return None[V]()
+	}
+	leaf := t.findLeaf(key)
+	index, found := leaf.search(key, t.compare)
+	if !found {
+		return None[V]()
+	}
+	return Some(leaf.values[index])
+}
+
+func (t *bplusTree[K, V]) Put(key K, value V) {
+	if t.root == nil {
+		leaf := newLeaf[K, V]()
+		leaf.keys = append(leaf.keys, key)
+		leaf.values = append(leaf.values, value)
+		t.root = leaf
+		t.head = leaf
+		t.tail = leaf
+		t.height = 1
+		t.len = 1
+		return
+	}
+	separator, right, inserted := t.insert(t.root, key, value)
+	if right != nil {
+		// Root was split, grow the tree by one level
+		t.root = &node[K, V]{
+			keys:     []K{separator},
+			children: []*node[K, V]{t.root, right},
+		}
+		t.height++
+	}
+	if inserted {
+		t.len++
+	}
+}
+
+func (t *bplusTree[K, V]) Height() int {
+	return t.height
+}
+
+func (t *bplusTree[K, V]) Min() Option[kv.Pair[K, V]] {
+	if t.head == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(t.head.keys[0], t.head.values[0]))
+}
+
+func (t *bplusTree[K, V]) Max() Option[kv.Pair[K, V]] {
+	if t.tail == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	last := len(t.tail.keys) - 1
+	return Some(kv.New(t.tail.keys[last], t.tail.values[last]))
+}
+
+func (t *bplusTree[K, V]) Floor(key K) Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	leaf := t.findLeaf(key)
+	index := sort.Search(len(leaf.keys), func(i int) bool {
+		return t.compare(leaf.keys[i], key).IsGreater()
+	})
+	if index > 0 {
+		return Some(kv.New(leaf.keys[index-1], leaf.values[index-1]))
+	}
+	if leaf.previous != nil {
+		last := len(leaf.previous.keys) - 1
+		return Some(kv.New(leaf.previous.keys[last], leaf.previous.values[last]))
+	}
+	return None[kv.Pair[K, V]]()
+}
+
+func (t *bplusTree[K, V]) Ceiling(key K) Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	leaf, index := t.seek(key)
+	if leaf == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(leaf.keys[index], leaf.values[index]))
+}
+
+func (t *bplusTree[K, V]) Range(opts ...where.WhereOption[K]) iter.Seq2[K, V] {
+	wh := where.Default[K]()
+	for _, opt := range opts {
+		opt(wh)
+	}
+	return func(yield func(K, V) bool) {
+		leaf, index := t.head, 0
+		if from := wh.From().Key(); from.IsSome() {
+			leaf, index = t.seek(from.Unwrap())
+		}
+		// One descent to the first candidate leaf, then a linked scan
+		for ; leaf != nil; leaf, index = leaf.next, 0 {
+			for ; index < len(leaf.keys); index++ {
+				key := leaf.keys[index]
+				if !wh.AdmitsFrom(key, t.compare) {
+					continue
+				}
+				if !wh.AdmitsTo(key, t.compare) {
+					return
+				}
+				if !yield(key, leaf.values[index]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// All returns an iterator over all entries in key order. Only the in-order
+// strategy is supported: entries live only in the leaves, so there is no
+// pre-order or post-order to give and the traversal options are ignored.
+func (t *bplusTree[K, V]) All(_ ...collection.SearchTreeTraversalOption) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for leaf := t.head; leaf != nil; leaf = leaf.next {
+			for index, key := range leaf.keys {
+				if !yield(key, leaf.values[index]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t *bplusTree[K, V]) Cursor() collection.BPlusTreeCursor[K, V] {
+	return &cursor[K, V]{
+		tree:  t,
+		leaf:  nil,
+		index: 0,
+	}
+}
+
+func (t *bplusTree[K, V]) Order() int {
+	return t.order
+}
+
+// findLeaf descends from the root to the leaf whose key range covers key.
+func (t *bplusTree[K, V]) findLeaf(key K) *node[K, V] {
+	current := t.root
+	for !current.isLeaf() {
+		current = current.children[current.childIndex(key, t.compare)]
+	}
+	return current
+}
+
+// seek returns the leaf and position of the smallest key greater than or equal
+// to key, or a nil leaf if every key is smaller.
+func (t *bplusTree[K, V]) seek(key K) (*node[K, V], int) {
+	if t.root == nil {
+		return nil, 0
+	}
+	leaf := t.findLeaf(key)
+	index, _ := leaf.search(key, t.compare)
+	if index == len(leaf.keys) {
+		return leaf.next, 0
+	}
+	return leaf, index
+}
+
+// insert adds or updates key in the subtree rooted at n. When n splits it
+// returns the separator and the new right sibling for the parent to adopt.
+func (t *bplusTree[K, V]) insert(n *node[K, V], key K, value V) (K, *node[K, V], bool) {
+	var zero K
+	if n.isLeaf() {
+		index, found := n.search(key, t.compare)
+		if found {
+			n.values[index] = value
+			return zero, nil, false
+		}
+		n.keys = slices.Insert(n.keys, index, key)
+		n.values = slices.Insert(n.values, index, value)
+		if len(n.keys) <= t.maxKeys() {
+			return zero, nil, true
+		}
+		separator, right := t.splitLeaf(n)
+		return separator, right, true
+	}
+
+	index := n.childIndex(key, t.compare)
+	separator, right, inserted := t.insert(n.children[index], key, value)
+	if right == nil {
+		return zero, nil, inserted
+	}
+	n.keys = slices.Insert(n.keys, index, separator)
+	n.children = slices.Insert(n.children, index+1, right)
+	if len(n.keys) <= t.maxKeys() {
+		return zero, nil, inserted
+	}
+	separator, right = t.splitInternal(n)
+	return separator, right, inserted
+}
+
+// splitLeaf moves the upper half of a leaf into a new right sibling and links
+// it into the leaf chain. The separator is a copy of the sibling's first key.
+func (t *bplusTree[K, V]) splitLeaf(n *node[K, V]) (K, *node[K, V]) {
+	mid := len(n.keys) / 2
+	right := &node[K, V]{
+		keys:     slices.Clone(n.keys[mid:]),
+		values:   slices.Clone(n.values[mid:]),
+		previous: n,
+		next:     n.next,
+	}
+	n.keys = n.keys[:mid]
+	n.values = n.values[:mid]
+	if n.next != nil {
+		n.next.previous = right
+	} else {
+		t.tail = right
+	}
+	n.next = right
+	return right.keys[0], right
+}
+
+// splitInternal moves the upper half of an internal node into a new right
+// sibling. The median key moves up to the parent.
+func (t *bplusTree[K, V]) splitInternal(n *node[K, V]) (K, *node[K, V]) {
+	mid := len(n.keys) / 2
+	separator := n.keys[mid]
+	right := &node[K, V]{
+		keys:     slices.Clone(n.keys[mid+1:]),
+		children: slices.Clone(n.children[mid+1:]),
+	}
+	n.keys = n.keys[:mid]
+	n.children = n.children[:mid+1]
+	return separator, right
+}
+
+// delete removes key from the subtree rooted at n, rebalancing any child left
+// with too few keys.
+func (t *bplusTree[K, V]) delete(n *node[K, V], key K) Option[V] {
+	if n.isLeaf() {
+		index, found := n.search(key, t.compare)
+		if !found {
+			return None[V]()
+		}
+		value := n.values[index]
+		n.keys = slices.Delete(n.keys, index, index+1)
+		n.values = slices.Delete(n.values, index, index+1)
+		return Some(value)
+	}
+
+	index := n.childIndex(key, t.compare)
+	deleted := t.delete(n.children[index], key)
+	if deleted.IsSome() && len(n.children[index].keys) < t.minKeys() {
+		t.rebalance(n, index)
+	}
+	return deleted
+}
+
+// rebalance fixes underflow in the child at index by borrowing or merging.
+func (t *bplusTree[K, V]) rebalance(parent *node[K, V], index int) {
+	if index > 0 && len(parent.children[index-1].keys) > t.minKeys() {
+		t.borrowFromLeft(parent, index)
+		return
+	}
+	if index+1 < len(parent.children) && len(parent.children[index+1].keys) > t.minKeys() {
+		t.borrowFromRight(parent, index)
+		return
+	}
+	if index > 0 {
+		t.merge(parent, index-1)
+	} else {
+		t.merge(parent, index)
+	}
+}
+
+func (t *bplusTree[K, V]) borrowFromLeft(parent *node[K, V], index int) {
+	child := parent.children[index]
+	left := parent.children[index-1]
+	last := len(left.keys) - 1
+	if child.isLeaf() {
+		child.keys = slices.Insert(child.keys, 0, left.keys[last])
+		child.values = slices.Insert(child.values, 0, left.values[last])
+		left.keys = left.keys[:last]
+		left.values = left.values[:last]
+		parent.keys[index-1] = child.keys[0]
+		return
+	}
+	lastChild := len(left.children) - 1
+	child.keys = slices.Insert(child.keys, 0, parent.keys[index-1])
+	child.children = slices.Insert(child.children, 0, left.children[lastChild])
+	parent.keys[index-1] = left.keys[last]
+	left.keys = left.keys[:last]
+	left.children = left.children[:lastChild]
+}
+
+func (t *bplusTree[K, V]) borrowFromRight(parent *node[K, V], index int) {
+	child := parent.children[index]
+	right := parent.children[index+1]
+	if child.isLeaf() {
+		child.keys = append(child.keys, right.keys[0])
+		child.values = append(child.values, right.values[0])
+		right.keys = slices.Delete(right.keys, 0, 1)
+		right.values = slices.Delete(right.values, 0, 1)
+		parent.keys[index] = right.keys[0]
+		return
+	}
+	child.keys = append(child.keys, parent.keys[index])
+	child.children = append(child.children, right.children[0])
+	parent.keys[index] = right.keys[0]
+	right.keys = slices.Delete(right.keys, 0, 1)
+	right.children = slices.Delete(right.children, 0, 1)
+}
+
+// merge folds the child at index+1 into the child at index.
+func (t *bplusTree[K, V]) merge(parent *node[K, V], index int) {
+	left := parent.children[index]
+	right := parent.children[index+1]
+	if left.isLeaf() {
+		left.keys = append(left.keys, right.keys...)
+		left.values = append(left.values, right.values...)
+		left.next = right.next
+		if right.next != nil {
+			right.next.previous = left
+		} else {
+			t.tail = left
+		}
+	} else {
+		left.keys = append(left.keys, parent.keys[index])
+		left.keys = append(left.keys, right.keys...)
+		left.children = append(left.children, right.children...)
+	}
+	parent.keys = slices.Delete(parent.keys, index, index+1)
+	parent.children = slices.Delete(parent.children, index+1, index+2)
+}
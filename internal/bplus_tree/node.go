@@ -0,0 +1,44 @@
+package bplustree
+
+import (
+	"sort"
+
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// node is either an internal node, which holds separator keys and children,
+// or a leaf, which holds the entries and links to its neighbouring leaves.
+type node[K any, V any] struct {
+	keys     []K
+	values   []V
+	children []*node[K, V]
+	previous *node[K, V]
+	next     *node[K, V]
+}
+
+func newLeaf[K any, V any]() *node[K, V] {
+	return &node[K, V]{
+		keys:   make([]K, 0),
+		values: make([]V, 0),
+	}
+}
+
+func (n *node[K, V]) isLeaf() bool {
+	return n.children == nil
+}
+
+// childIndex returns the child whose subtree may contain key.
+// Child i holds keys in [keys[i-1], keys[i]).
+func (n *node[K, V]) childIndex(key K, fn func(a, b K) compare.Order) int {
+	return sort.Search(len(n.keys), func(i int) bool {
+		return fn(n.keys[i], key).IsGreater()
+	})
+}
+
+// search returns the position of key within a leaf and whether it was found.
+func (n *node[K, V]) search(key K, fn func(a, b K) compare.Order) (int, bool) {
+	index := sort.Search(len(n.keys), func(i int) bool {
+		return fn(n.keys[i], key).IsGreaterThanOrEqualTo()
+	})
+	return index, index < len(n.keys) && fn(n.keys[index], key).IsEqual()
+}
@@ -245,6 +245,15 @@ func (b *builtinImpl[K, V]) Order() int {
 	return b.order
 }
 
+func (b *builtinImpl[K, V]) Root() Option[collection.BTreeNode[K, V]] {
+	if b.root.IsNone() {
+		return None[collection.BTreeNode[K, V]]()
+	}
+	root := b.root.Unwrap()
+	var res collection.BTreeNode[K, V] = &root
+	return Some(res)
+}
+
 func (b *builtinImpl[K, V]) All(opts ...collection.SearchTreeTraversalOption) iter.Seq2[K, V] {
 	traversalCfg := collection.DefaultSearchTreeTraversalConfiguration()
 	for _, opt := range opts {
@@ -501,7 +510,7 @@ func deleteKey[K cmp.Ordered, V any](
 
 		// Update the left child
 		node.children.RemoveAt(idx)
-		node.children.Insert(idx, newLeftChild)
+		insertChild(node.children, idx, newLeftChild)
 
 		// Check if left child needs rebalancing
 		minKeys := branchingFactor - 1
@@ -530,7 +539,7 @@ func deleteKey[K cmp.Ordered, V any](
 
 	// Update the child
 	node.children.RemoveAt(childIdx)
-	node.children.Insert(childIdx, newChild)
+	insertChild(node.children, childIdx, newChild)
 
 	// Check if child needs rebalancing
 	minKeys := branchingFactor - 1
@@ -603,14 +612,14 @@ func borrowFromLeft[K cmp.Ordered, V any](parent *Node[K, V], childIdx int) {
 	if leftSibling.children.Len() > 0 {
 		movedChild := leftSibling.children.Get(leftSibling.children.Len() - 1).Unwrap()
 		leftSibling.children.RemoveAt(leftSibling.children.Len() - 1)
-		child.children.Insert(0, movedChild)
+		insertChild(child.children, 0, movedChild)
 	}
 
 	// Update children in parent
 	parent.children.RemoveAt(childIdx)
-	parent.children.Insert(childIdx, child)
+	insertChild(parent.children, childIdx, child)
 	parent.children.RemoveAt(childIdx - 1)
-	parent.children.Insert(childIdx-1, leftSibling)
+	insertChild(parent.children, childIdx-1, leftSibling)
 }
 
 // borrowFromRight borrows a key from the right sibling through the parent
@@ -635,9 +644,9 @@ func borrowFromRight[K cmp.Ordered, V any](parent *Node[K, V], childIdx int) {
 
 	// Update children in parent
 	parent.children.RemoveAt(childIdx)
-	parent.children.Insert(childIdx, child)
+	insertChild(parent.children, childIdx, child)
 	parent.children.RemoveAt(childIdx + 1)
-	parent.children.Insert(childIdx+1, rightSibling)
+	insertChild(parent.children, childIdx+1, rightSibling)
 }
 
 // mergeChildren merges child at idx with child at idx+1
@@ -663,7 +672,7 @@ func mergeChildren[K cmp.Ordered, V any](parent *Node[K, V], idx int) {
 	// Remove right child and update left child
 	parent.children.RemoveAt(idx + 1)
 	parent.children.RemoveAt(idx)
-	parent.children.Insert(idx, leftChild)
+	insertChild(parent.children, idx, leftChild)
 }
 
 // promotedKey holds a key promoted during split, with its left and right children
@@ -714,8 +723,8 @@ func insert[K cmp.Ordered, V any](
 		// Replace the child that was split with the left part
 		// and insert the right part after it
 		node.children.RemoveAt(lo)
-		node.children.Insert(lo, p.left)
-		node.children.Insert(lo+1, p.right)
+		insertChild(node.children, lo, p.left)
+		insertChild(node.children, lo+1, p.right)
 
 		resultNode, resultPromoted := insertPromoted(node, p.key, branchingFactor)
 		return resultNode, resultPromoted, wasInserted
@@ -723,7 +732,7 @@ func insert[K cmp.Ordered, V any](
 
 	// Update the child with the new version
 	node.children.RemoveAt(lo)
-	node.children.Insert(lo, newChild)
+	insertChild(node.children, lo, newChild)
 
 	return *node, None[promotedKey[K, V]](), wasInserted
 }
@@ -878,3 +887,13 @@ func rangeInorder[K cmp.Ordered, V any](
 
 	return res
 }
+
+// insertChild inserts child at index, appending when index is one past the
+// last child. Slice.Insert only accepts the index of an existing element.
+func insertChild[K cmp.Ordered, V any](children collection.Slice[Node[K, V]], index int, child Node[K, V]) {
+	if index == children.Len() {
+		children.Append(child)
+		return
+	}
+	children.Insert(index, child)
+}
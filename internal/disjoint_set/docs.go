@@ -0,0 +1,5 @@
+// Package disjointset implements [collection.DisjointSet] with a forest
+// stored in slices, using path halving and union by rank.
+package disjointset
+
+import _ "codeberg.org/yaadata/bina/core/collection"
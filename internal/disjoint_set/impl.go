@@ -0,0 +1,235 @@
+package disjointset
+
+import (
+	"iter"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/hashable"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/internal/hashset"
+)
+
+// disjointSet numbers elements in the order they are added and keeps the
+// forest in slices indexed by that number. parent[i] == i marks a root, and
+// size is only maintained for roots.
+type disjointSet[K comparable, T any] struct {
+	key      func(element T) K
+	newSet   func(capacity int) collection.Set[T]
+	index    map[K]int
+	elements []T
+	parent   []int
+	rank     []uint8
+	size     []int
+	sets     int
+}
+
+func _[T comparable]() {
+	var _ collection.DisjointSet[T] = (*disjointSet[T, T])(nil)
+}
+
+// DisjointSetFromBuiltin returns an empty disjoint set of comparable elements.
+func DisjointSetFromBuiltin[T comparable](capacity int) collection.DisjointSet[T] {
+	return newDisjointSet(
+		func(element T) T {
+			return element
+		},
+		hashset.HashSetFromBuiltin[T],
+		capacity,
+	)
+}
+
+// DisjointSetFromHashable returns an empty disjoint set of elements keyed by
+// their Hash.
+func DisjointSetFromHashable[K comparable, T hashable.Hashable[K]](capacity int) collection.DisjointSet[T] {
+	return newDisjointSet(
+		func(element T) K {
+			return element.Hash()
+		},
+		hashset.HashSetFromHashable[K, T],
+		capacity,
+	)
+}
+
+func newDisjointSet[K comparable, T any](
+	key func(element T) K,
+	newSet func(capacity int) collection.Set[T],
+	capacity int,
+) *disjointSet[K, T] {
+	return &disjointSet[K, T]{
+		key:      key,
+		newSet:   newSet,
+		index:    make(map[K]int, capacity),
+		elements: make([]T, 0, capacity),
+		parent:   make([]int, 0, capacity),
+		rank:     make([]uint8, 0, capacity),
+		size:     make([]int, 0, capacity),
+	}
+}
+
+func (d *disjointSet[K, T]) Len() int {
+	return len(d.elements)
+}
+
+func (d *disjointSet[K, T]) Contains(element T) bool {
+	_, ok := d.index[d.key(element)]
+	return ok
+}
+
+func (d *disjointSet[K, T]) IsEmpty() bool {
+	return len(d.elements) == 0
+}
+
+func (d *disjointSet[K, T]) Clear() {
+	clear(d.index)
+	clear(d.elements)
+	d.elements = d.elements[:0]
+	d.parent = d.parent[:0]
+	d.rank = d.rank[:0]
+	d.size = d.size[:0]
+	d.sets = 0
+}
+
+func (d *disjointSet[K, T]) Any(pred predicate.Predicate[T]) bool {
+	return slices.ContainsFunc(d.elements, pred)
+}
+
+func (d *disjointSet[K, T]) Count(pred predicate.Predicate[T]) int {
+	var count int
+	for _, element := range d.elements {
+		if pred(element) {
+			count++
+		}
+	}
+	return count
+}
+
+func (d *disjointSet[K, T]) Every(pred predicate.Predicate[T]) bool {
+	for _, element := range d.elements {
+		if !pred(element) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *disjointSet[K, T]) ForEach(fn func(element T)) {
+	for _, element := range d.elements {
+		fn(element)
+	}
+}
+
+func (d *disjointSet[K, T]) Values() iter.Seq[T] {
+	return slices.Values(d.elements)
+}
+
+func (d *disjointSet[K, T]) MakeSet(element T) bool {
+	if d.Contains(element) {
+		return false
+	}
+	d.add(element)
+	return true
+}
+
+func (d *disjointSet[K, T]) Union(a, b T) bool {
+	x, y := d.root(d.indexOf(a)), d.root(d.indexOf(b))
+	if x == y {
+		return false
+	}
+	if d.rank[x] < d.rank[y] {
+		x, y = y, x
+	}
+	d.parent[y] = x
+	d.size[x] += d.size[y]
+	if d.rank[x] == d.rank[y] {
+		d.rank[x]++
+	}
+	d.sets--
+	return true
+}
+
+func (d *disjointSet[K, T]) Find(element T) Option[T] {
+	index, ok := d.index[d.key(element)]
+	if !ok {
+		return None[T]()
+	}
+	return Some(d.elements[d.root(index)])
+}
+
+func (d *disjointSet[K, T]) Connected(a, b T) bool {
+	x, ok := d.index[d.key(a)]
+	if !ok {
+		return false
+	}
+	y, ok := d.index[d.key(b)]
+	if !ok {
+		return false
+	}
+	return d.root(x) == d.root(y)
+}
+
+func (d *disjointSet[K, T]) SetSize(element T) int {
+	index, ok := d.index[d.key(element)]
+	if !ok {
+		return 0
+	}
+	return d.size[d.root(index)]
+}
+
+func (d *disjointSet[K, T]) SetCount() int {
+	return d.sets
+}
+
+func (d *disjointSet[K, T]) Groups() iter.Seq[collection.Set[T]] {
+	return func(yield func(collection.Set[T]) bool) {
+		// group maps a root to the position of its set in groups.
+		group := make(map[int]int, d.sets)
+		groups := make([]collection.Set[T], 0, d.sets)
+		for index, element := range d.elements {
+			root := d.root(index)
+			position, ok := group[root]
+			if !ok {
+				position = len(groups)
+				group[root] = position
+				groups = append(groups, d.newSet(d.size[root]))
+			}
+			groups[position].Add(element)
+		}
+		for _, set := range groups {
+			if !yield(set) {
+				return
+			}
+		}
+	}
+}
+
+// indexOf returns the index of element, adding it first if absent.
+func (d *disjointSet[K, T]) indexOf(element T) int {
+	if index, ok := d.index[d.key(element)]; ok {
+		return index
+	}
+	return d.add(element)
+}
+
+func (d *disjointSet[K, T]) add(element T) int {
+	index := len(d.elements)
+	d.index[d.key(element)] = index
+	d.elements = append(d.elements, element)
+	d.parent = append(d.parent, index)
+	d.rank = append(d.rank, 0)
+	d.size = append(d.size, 1)
+	d.sets++
+	return index
+}
+
+// root returns the root of the tree containing index, halving the path on
+// the way up.
+func (d *disjointSet[K, T]) root(index int) int {
+	for d.parent[index] != index {
+		d.parent[index] = d.parent[d.parent[index]]
+		index = d.parent[index]
+	}
+	return index
+}
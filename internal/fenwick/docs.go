@@ -0,0 +1,4 @@
+// Package fenwick implements [collection.FenwickTree] and [collection.FenwickTree2D].
+package fenwick
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,100 @@
+package fenwick
+
+import (
+	"math/bits"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/numeric"
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+// fenwickTree keeps one-based partial sums: nodes[i] holds the sum of the
+// lowbit(i) elements ending at element i-1.
+type fenwickTree[T numeric.Number] struct {
+	nodes []T
+}
+
+var _ collection.FenwickTree[int] = (*fenwickTree[int])(nil)
+
+// New returns a Fenwick tree over items, built in O(n).
+func New[T numeric.Number](items []T) *fenwickTree[T] {
+	nodes := make([]T, len(items)+1)
+	copy(nodes[1:], items)
+	for i := 1; i < len(nodes); i++ {
+		if parent := i + lowbit(i); parent < len(nodes) {
+			nodes[parent] += nodes[i]
+		}
+	}
+	return &fenwickTree[T]{
+		nodes: nodes,
+	}
+}
+
+func (t *fenwickTree[T]) Len() int {
+	return len(t.nodes) - 1
+}
+
+func (t *fenwickTree[T]) Get(index int) Option[T] {
+	if index < 0 || index >= t.Len() {
+		return None[T]()
+	}
+	return Some(t.prefix(index+1) - t.prefix(index))
+}
+
+func (t *fenwickTree[T]) Add(index int, delta T) bool {
+	if index < 0 || index >= t.Len() {
+		return false
+	}
+	for i := index + 1; i < len(t.nodes); i += lowbit(i) {
+		t.nodes[i] += delta
+	}
+	return true
+}
+
+func (t *fenwickTree[T]) PrefixSum(index int) T {
+	return t.prefix(min(max(index+1, 0), t.Len()))
+}
+
+func (t *fenwickTree[T]) RangeSum(opts ...where.WhereOption[int]) T {
+	start, end := where.Indices(t.Len(), opts...)
+	return t.prefix(end) - t.prefix(start)
+}
+
+func (t *fenwickTree[T]) LowerBound(sum T) Option[int] {
+	var position int
+	remaining := sum
+	// Descend by decreasing powers of two, skipping blocks whose sum falls short
+	for step := highbit(t.Len()); step > 0; step >>= 1 {
+		if next := position + step; next < len(t.nodes) && t.nodes[next] < remaining {
+			position = next
+			remaining -= t.nodes[next]
+		}
+	}
+	if position >= t.Len() {
+		return None[int]()
+	}
+	return Some(position)
+}
+
+// prefix returns the sum of the first count elements.
+func (t *fenwickTree[T]) prefix(count int) T {
+	var sum T
+	for i := count; i > 0; i -= lowbit(i) {
+		sum += t.nodes[i]
+	}
+	return sum
+}
+
+func lowbit(i int) int {
+	return i & -i
+}
+
+// highbit returns the largest power of two not greater than n, or 0 if n is 0.
+func highbit(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return 1 << (bits.Len(uint(n)) - 1)
+}
@@ -0,0 +1,92 @@
+package fenwick
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/numeric"
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+// fenwickTree2D nests the one-dimensional scheme: nodes[i][j] holds the sum
+// of the lowbit(i) by lowbit(j) block ending at cell (i-1, j-1).
+type fenwickTree2D[T numeric.Number] struct {
+	nodes [][]T
+	rows  int
+	cols  int
+}
+
+var _ collection.FenwickTree2D[int] = (*fenwickTree2D[int])(nil)
+
+// New2D returns a rows by cols Fenwick tree. Cells missing from grid are zero
+// and cells outside the dimensions are ignored.
+func New2D[T numeric.Number](rows, cols int, grid [][]T) *fenwickTree2D[T] {
+	rows, cols = max(rows, 0), max(cols, 0)
+	t := &fenwickTree2D[T]{
+		nodes: make([][]T, rows+1),
+		rows:  rows,
+		cols:  cols,
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make([]T, cols+1)
+	}
+	for row := range min(rows, len(grid)) {
+		for col := range min(cols, len(grid[row])) {
+			t.Add(row, col, grid[row][col])
+		}
+	}
+	return t
+}
+
+func (t *fenwickTree2D[T]) Rows() int {
+	return t.rows
+}
+
+func (t *fenwickTree2D[T]) Cols() int {
+	return t.cols
+}
+
+func (t *fenwickTree2D[T]) Get(row, col int) Option[T] {
+	if row < 0 || row >= t.rows || col < 0 || col >= t.cols {
+		return None[T]()
+	}
+	return Some(t.rectangle(row, row+1, col, col+1))
+}
+
+func (t *fenwickTree2D[T]) Add(row, col int, delta T) bool {
+	if row < 0 || row >= t.rows || col < 0 || col >= t.cols {
+		return false
+	}
+	for i := row + 1; i <= t.rows; i += lowbit(i) {
+		for j := col + 1; j <= t.cols; j += lowbit(j) {
+			t.nodes[i][j] += delta
+		}
+	}
+	return true
+}
+
+func (t *fenwickTree2D[T]) PrefixSum(row, col int) T {
+	return t.prefix(min(max(row+1, 0), t.rows), min(max(col+1, 0), t.cols))
+}
+
+func (t *fenwickTree2D[T]) RangeSum(rows []where.WhereOption[int], cols []where.WhereOption[int]) T {
+	top, bottom := where.Indices(t.rows, rows...)
+	left, right := where.Indices(t.cols, cols...)
+	return t.rectangle(top, bottom, left, right)
+}
+
+// rectangle returns the sum of rows [top, bottom) and columns [left, right).
+func (t *fenwickTree2D[T]) rectangle(top, bottom, left, right int) T {
+	return t.prefix(bottom, right) - t.prefix(top, right) - t.prefix(bottom, left) + t.prefix(top, left)
+}
+
+// prefix returns the sum of the first rowCount rows and colCount columns.
+func (t *fenwickTree2D[T]) prefix(rowCount, colCount int) T {
+	var sum T
+	for i := rowCount; i > 0; i -= lowbit(i) {
+		for j := colCount; j > 0; j -= lowbit(j) {
+			sum += t.nodes[i][j]
+		}
+	}
+	return sum
+}
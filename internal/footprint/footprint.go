@@ -0,0 +1,27 @@
+// Package footprint estimates the memory held by slices and maps, for
+// collections that report their own size.
+package footprint
+
+import "reflect"
+
+// mapOverhead approximates the bytes a Go map spends per entry beyond the key
+// and value themselves: control bytes, group headers and unused slots at the
+// typical load factor.
+const mapOverhead = 12
+
+// Of returns the size in bytes of a value of type T, not counting memory it
+// references.
+func Of[T any]() int {
+	return int(reflect.TypeFor[T]().Size())
+}
+
+// Slice returns the bytes held by the backing array of a slice of T with the
+// given capacity. The header is part of whatever holds the slice.
+func Slice[T any](capacity int) int {
+	return capacity * Of[T]()
+}
+
+// Map returns the approximate bytes held by a map from K to V with n entries.
+func Map[K comparable, V any](n int) int {
+	return 48 + n*(Of[K]()+Of[V]()+mapOverhead)
+}
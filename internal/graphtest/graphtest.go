@@ -0,0 +1,340 @@
+// Package graphtest holds the conformance checks every [collection.Graph]
+// implementation must pass. It is imported only from tests.
+package graphtest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+)
+
+// NewGraph returns an empty graph with the given directedness and edge policy.
+type NewGraph func(directed bool, policy collection.GraphEdgePolicy) collection.Graph[string, int]
+
+// Run checks that graphs returned by newGraph behave as [collection.Graph]
+// requires.
+func Run(t *testing.T, newGraph NewGraph) {
+	t.Helper()
+
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := newGraph(false, collection.GraphEdgePolicyReject)
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, g.Len())
+		must.Eq(t, 0, g.EdgeCount())
+		must.True(t, g.IsEmpty())
+		must.False(t, g.IsDirected())
+		must.Eq(t, collection.GraphEdgePolicyReject, g.EdgePolicy())
+		must.SliceEmpty(t, Edges(g))
+	})
+
+	t.Run("Undirected edges work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := newGraph(false, collection.GraphEdgePolicyReject)
+		g.AddEdge("a", "b", 1)
+		g.AddEdge("c", "a", 2)
+		g.AddEdge("b", "c", 3)
+		g.AddVertex("d")
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"a", "b", "c", "d"}, Vertices(g))
+		must.Eq(t, 3, g.EdgeCount())
+		must.True(t, g.HasEdge("a", "c"))
+		must.True(t, g.HasEdge("c", "a"))
+		must.False(t, g.HasEdge("a", "d"))
+		must.Eq(t, 2, g.Weight("a", "c").Unwrap())
+		must.True(t, g.Weight("a", "d").IsNone())
+		must.Eq(t, []string{"b:1", "c:2"}, Neighbors(g, "a"))
+		must.Eq(t, 2, g.OutDegree("a"))
+		must.Eq(t, 2, g.InDegree("a"))
+		must.Eq(t, 0, g.OutDegree("d"))
+		must.Eq(t, []string{"a->b:1", "b->c:3", "c->a:2"}, Edges(g))
+	})
+
+	t.Run("Directed edges work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := newGraph(true, collection.GraphEdgePolicyReject)
+		g.AddEdge("a", "b", 1)
+		g.AddEdge("c", "a", 2)
+		g.AddEdge("a", "c", 3)
+		// ========= [A]ssert  =========
+		must.True(t, g.IsDirected())
+		must.Eq(t, 3, g.EdgeCount())
+		must.True(t, g.HasEdge("a", "b"))
+		must.False(t, g.HasEdge("b", "a"))
+		must.Eq(t, 2, g.Weight("c", "a").Unwrap())
+		must.Eq(t, 3, g.Weight("a", "c").Unwrap())
+		must.Eq(t, []string{"b:1", "c:3"}, Neighbors(g, "a"))
+		must.SliceEmpty(t, Neighbors(g, "b"))
+		must.Eq(t, 2, g.OutDegree("a"))
+		must.Eq(t, 1, g.InDegree("a"))
+		must.Eq(t, 1, g.InDegree("b"))
+		must.Eq(t, 0, g.OutDegree("b"))
+		must.Eq(t, []string{"a->b:1", "a->c:3", "c->a:2"}, Edges(g))
+	})
+
+	t.Run("Self-loops work", func(t *testing.T) {
+		for _, directed := range []bool{false, true} {
+			// ========= [A]rrange =========
+			g := newGraph(directed, collection.GraphEdgePolicyReject)
+			// ========= [A]ct     =========
+			g.AddEdge("a", "a", 7)
+			g.AddEdge("a", "b", 1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, g.EdgeCount())
+			must.True(t, g.HasEdge("a", "a"))
+			must.Eq(t, 2, g.OutDegree("a"))
+			must.Eq(t, []string{"a->a:7", "a->b:1"}, Edges(g))
+			must.True(t, g.RemoveEdge("a", "a"))
+			must.Eq(t, 1, g.EdgeCount())
+			must.Eq(t, 1, g.OutDegree("a"))
+		}
+	})
+
+	t.Run("Edge policies work", func(t *testing.T) {
+		// SCENARIO: Reject
+		t.Run("EdgePolicy - reject", func(t *testing.T) {
+			// ========= [A]rrange =========
+			g := newGraph(false, collection.GraphEdgePolicyReject)
+			g.AddEdge("a", "b", 1)
+			// ========= [A]ct     =========
+			added := g.AddEdge("b", "a", 2)
+			// ========= [A]ssert  =========
+			must.False(t, added)
+			must.Eq(t, 1, g.EdgeCount())
+			must.Eq(t, 1, g.Weight("b", "a").Unwrap())
+		})
+		// SCENARIO: Replace
+		t.Run("EdgePolicy - replace", func(t *testing.T) {
+			// ========= [A]rrange =========
+			g := newGraph(false, collection.GraphEdgePolicyReplace)
+			g.AddEdge("a", "b", 1)
+			// ========= [A]ct     =========
+			added := g.AddEdge("b", "a", 2)
+			// ========= [A]ssert  =========
+			must.True(t, added)
+			must.Eq(t, 1, g.EdgeCount())
+			must.Eq(t, 2, g.Weight("a", "b").Unwrap())
+			must.Eq(t, 2, g.Weight("b", "a").Unwrap())
+		})
+		// SCENARIO: Replace keeps directions apart
+		t.Run("EdgePolicy - replace directed", func(t *testing.T) {
+			// ========= [A]rrange =========
+			g := newGraph(true, collection.GraphEdgePolicyReplace)
+			g.AddEdge("a", "b", 1)
+			g.AddEdge("b", "a", 2)
+			// ========= [A]ct     =========
+			g.AddEdge("a", "b", 3)
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, g.EdgeCount())
+			must.Eq(t, []string{"a->b:3", "b->a:2"}, Edges(g))
+		})
+		// SCENARIO: Allow
+		t.Run("EdgePolicy - allow", func(t *testing.T) {
+			// ========= [A]rrange =========
+			g := newGraph(false, collection.GraphEdgePolicyAllow)
+			g.AddEdge("a", "b", 1)
+			// ========= [A]ct     =========
+			added := g.AddEdge("b", "a", 2)
+			// ========= [A]ssert  =========
+			must.True(t, added)
+			must.Eq(t, 2, g.EdgeCount())
+			must.Eq(t, 2, g.OutDegree("a"))
+			must.Eq(t, []string{"b:1", "b:2"}, Neighbors(g, "a"))
+			must.Eq(t, []string{"a->b:1", "b->a:2"}, Edges(g))
+		})
+	})
+
+	t.Run("Removal works", func(t *testing.T) {
+		for _, directed := range []bool{false, true} {
+			t.Run(fmt.Sprintf("Directed %t", directed), func(t *testing.T) {
+				// ========= [A]rrange =========
+				g := newGraph(directed, collection.GraphEdgePolicyAllow)
+				g.AddEdge("a", "b", 1)
+				g.AddEdge("b", "c", 2)
+				g.AddEdge("c", "a", 3)
+				g.AddEdge("a", "b", 4)
+				g.AddEdge("b", "b", 5)
+
+				// SCENARIO: RemoveEdge
+				t.Run("RemoveEdge", func(t *testing.T) {
+					// ========= [A]ct     =========
+					removed := g.RemoveEdge("a", "b")
+					missing := g.RemoveEdge("a", "b")
+					// ========= [A]ssert  =========
+					must.True(t, removed)
+					must.False(t, missing)
+					must.False(t, g.HasEdge("a", "b"))
+					must.False(t, g.HasEdge("b", "a"))
+					must.Eq(t, 3, g.EdgeCount())
+					must.Eq(t, []string{"b->b:5", "b->c:2", "c->a:3"}, Edges(g))
+				})
+				// SCENARIO: RemoveVertex
+				t.Run("RemoveVertex", func(t *testing.T) {
+					// ========= [A]ct     =========
+					removed := g.RemoveVertex("b")
+					missing := g.RemoveVertex("b")
+					// ========= [A]ssert  =========
+					must.True(t, removed)
+					must.False(t, missing)
+					must.Eq(t, []string{"a", "c"}, Vertices(g))
+					must.Eq(t, 1, g.EdgeCount())
+					must.Eq(t, []string{"c->a:3"}, Edges(g))
+					must.Eq(t, 0, g.InDegree("b"))
+					must.SliceEmpty(t, Neighbors(g, "b"))
+				})
+			})
+		}
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		g := newGraph(false, collection.GraphEdgePolicyReject)
+		g.AddEdge("a", "bb", 1)
+		g.AddEdge("bb", "ccc", 1)
+		short := func(vertex string) bool {
+			return len(vertex) < 3
+		}
+
+		// SCENARIO: Contains
+		t.Run("Contains", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, g.Contains("bb"))
+			must.False(t, g.Contains("d"))
+		})
+		// SCENARIO: Aggregate
+		t.Run("Aggregate", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var visited []string
+			g.ForEach(func(vertex string) {
+				visited = append(visited, vertex)
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"a", "bb", "ccc"}, visited)
+			must.True(t, g.Any(short))
+			must.False(t, g.Every(short))
+			must.Eq(t, 2, g.Count(short))
+		})
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]ct     =========
+			g.Clear()
+			g.AddEdge("x", "y", 1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, g.Len())
+			must.Eq(t, 1, g.EdgeCount())
+			must.False(t, g.Contains("a"))
+		})
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		for _, directed := range []bool{false, true} {
+			t.Run(fmt.Sprintf("Directed %t", directed), func(t *testing.T) {
+				// ========= [A]rrange =========
+				rng := rand.New(rand.NewPCG(7, 5))
+				g := newGraph(directed, collection.GraphEdgePolicyAllow)
+				// The model keeps vertices in insertion order and edges as added.
+				var vertices []string
+				type modelEdge struct {
+					from, to string
+					weight   int
+				}
+				var edges []modelEdge
+				joins := func(e modelEdge, from, to string) bool {
+					return e.from == from && e.to == to || !directed && e.from == to && e.to == from
+				}
+				names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+				// ========= [A]ct     =========
+				for range 1_000 {
+					from, to := names[rng.IntN(len(names))], names[rng.IntN(len(names))]
+					switch rng.IntN(6) {
+					case 0:
+						must.Eq(t, g.RemoveVertex(from), slices.Contains(vertices, from))
+						vertices = slices.DeleteFunc(vertices, func(v string) bool {
+							return v == from
+						})
+						edges = slices.DeleteFunc(edges, func(e modelEdge) bool {
+							return e.from == from || e.to == from
+						})
+					case 1:
+						removed := slices.DeleteFunc(edges, func(e modelEdge) bool {
+							return joins(e, from, to)
+						})
+						must.Eq(t, len(removed) < len(edges), g.RemoveEdge(from, to))
+						edges = removed
+					default:
+						weight := rng.IntN(100)
+						must.True(t, g.AddEdge(from, to, weight))
+						for _, v := range []string{from, to} {
+							if !slices.Contains(vertices, v) {
+								vertices = append(vertices, v)
+							}
+						}
+						edges = append(edges, modelEdge{from, to, weight})
+					}
+				}
+				// ========= [A]ssert  =========
+				must.Eq(t, vertices, Vertices(g))
+				must.Eq(t, len(edges), g.EdgeCount())
+				var expected []string
+				for _, e := range edges {
+					expected = append(expected, fmt.Sprintf("%s->%s:%d", e.from, e.to, e.weight))
+				}
+				slices.Sort(expected)
+				must.Eq(t, expected, Edges(g))
+				for _, from := range names {
+					var out, in int
+					for _, e := range edges {
+						if e.from == from || !directed && e.to == from && e.from != from {
+							out++
+						}
+						if e.to == from || !directed && e.from == from && e.to != from {
+							in++
+						}
+					}
+					must.Eq(t, out, g.OutDegree(from))
+					must.Eq(t, in, g.InDegree(from))
+					for _, to := range names {
+						must.Eq(t, slices.ContainsFunc(edges, func(e modelEdge) bool {
+							return joins(e, from, to)
+						}), g.HasEdge(from, to))
+					}
+				}
+			})
+		}
+	})
+}
+
+// Vertices returns the vertices of g in iteration order.
+func Vertices[V comparable, E any](g collection.Graph[V, E]) []V {
+	return slices.Collect(g.Vertices())
+}
+
+// Neighbors returns the neighbours of vertex as "to:weight", sorted.
+func Neighbors[V comparable, E any](g collection.Graph[V, E], vertex V) []string {
+	var res []string
+	for to, weight := range g.Neighbors(vertex) {
+		res = append(res, fmt.Sprintf("%v:%v", to, weight))
+	}
+	slices.Sort(res)
+	return res
+}
+
+// Edges returns the edges of g as "from->to:weight", sorted.
+func Edges[V comparable, E any](g collection.Graph[V, E]) []string {
+	var res []string
+	for e := range g.Edges() {
+		res = append(res, format(e))
+	}
+	slices.Sort(res)
+	return res
+}
+
+func format[V comparable, E any](e edge.Edge[V, E]) string {
+	return fmt.Sprintf("%v->%v:%v", e.From(), e.To(), e.Weight())
+}
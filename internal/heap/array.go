@@ -0,0 +1,91 @@
+package heap
+
+import (
+	"iter"
+	"slices"
+
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/predicate"
+)
+
+// array holds the state and the order-independent methods shared by the
+// slice-backed heaps.
+type array[T any] struct {
+	compare func(a, b T) compare.Order
+	items   []T
+}
+
+func (h *array[T]) Len() int {
+	return len(h.items)
+}
+
+// Contains reports whether an element that orders equal to element is
+// present. It runs in O(n).
+func (h *array[T]) Contains(element T) bool {
+	return slices.ContainsFunc(h.items, func(item T) bool {
+		return h.compare(item, element) == compare.OrderEqual
+	})
+}
+
+func (h *array[T]) IsEmpty() bool {
+	return len(h.items) == 0
+}
+
+func (h *array[T]) Clear() {
+	clear(h.items)
+	h.items = h.items[:0]
+}
+
+func (h *array[T]) Any(pred predicate.Predicate[T]) bool {
+	return slices.ContainsFunc(h.items, pred)
+}
+
+func (h *array[T]) Count(pred predicate.Predicate[T]) int {
+	var count int
+	for _, item := range h.items {
+		if pred(item) {
+			count++
+		}
+	}
+	return count
+}
+
+func (h *array[T]) Every(pred predicate.Predicate[T]) bool {
+	for _, item := range h.items {
+		if !pred(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *array[T]) ForEach(fn func(T)) {
+	for _, item := range h.items {
+		fn(item)
+	}
+}
+
+func (h *array[T]) Values() iter.Seq[T] {
+	return slices.Values(h.items)
+}
+
+// less reports whether the element at i orders strictly before the one at j.
+func (h *array[T]) less(i, j int) bool {
+	return h.compare(h.items[i], h.items[j]) == compare.OrderLess
+}
+
+func (h *array[T]) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+// removeAt moves the last element into index and returns the element that was
+// there. The caller restores the heap property at index.
+func (h *array[T]) removeAt(index int) T {
+	last := len(h.items) - 1
+	removed := h.items[index]
+	h.items[index] = h.items[last]
+	var zero T
+	h.items[last] = zero
+	h.items = h.items[:last]
+	return removed
+}
@@ -0,0 +1,87 @@
+package heap
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// dAryHeap is an implicit heap in which node i has children d*i+1 through
+// d*i+d. A larger arity makes the tree shallower, trading cheaper pushes for
+// more comparisons per pop.
+type dAryHeap[T any] struct {
+	array[T]
+	arity int
+}
+
+var _ collection.Heap[int] = (*dAryHeap[int])(nil)
+
+// NewDAry returns a heap of the given arity ordered by fn. It takes
+// ownership of items and heapifies them in O(n). Arities below 2 are raised
+// to 2.
+func NewDAry[T any](fn func(a, b T) compare.Order, arity int, items []T) *dAryHeap[T] {
+	h := &dAryHeap[T]{
+		array: array[T]{
+			compare: fn,
+			items:   items,
+		},
+		arity: max(arity, 2),
+	}
+	for i := (len(items) - 2) / h.arity; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+func (h *dAryHeap[T]) Push(element T) {
+	h.items = append(h.items, element)
+	h.up(len(h.items) - 1)
+}
+
+func (h *dAryHeap[T]) Pop() Option[T] {
+	if len(h.items) == 0 {
+		return None[T]()
+	}
+	removed := h.removeAt(0)
+	h.down(0)
+	return Some(removed)
+}
+
+func (h *dAryHeap[T]) Peek() Option[T] {
+	if len(h.items) == 0 {
+		return None[T]()
+	}
+	return Some(h.items[0])
+}
+
+func (h *dAryHeap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / h.arity
+		if !h.less(i, parent) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *dAryHeap[T]) down(i int) {
+	for {
+		first := h.arity*i + 1
+		if first >= len(h.items) {
+			return
+		}
+		best := i
+		for child := first; child < min(first+h.arity, len(h.items)); child++ {
+			if h.less(child, best) {
+				best = child
+			}
+		}
+		if best == i {
+			return
+		}
+		h.swap(i, best)
+		i = best
+	}
+}
@@ -0,0 +1,5 @@
+// Package heap implements [collection.Heap] and [collection.MinMaxHeap] on
+// top of a plain slice.
+package heap
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,159 @@
+package heap
+
+import (
+	"math/bits"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// minMaxHeap is Atkinson et al.'s min-max heap: a binary implicit heap whose
+// even levels are ordered like a min-heap and whose odd levels are ordered
+// like a max-heap. The first element is the root and the last element is one
+// of its children.
+type minMaxHeap[T any] struct {
+	array[T]
+}
+
+var _ collection.MinMaxHeap[int] = (*minMaxHeap[int])(nil)
+
+// NewMinMax returns a min-max heap ordered by fn. It takes ownership of items
+// and heapifies them in O(n).
+func NewMinMax[T any](fn func(a, b T) compare.Order, items []T) *minMaxHeap[T] {
+	h := &minMaxHeap[T]{
+		array: array[T]{
+			compare: fn,
+			items:   items,
+		},
+	}
+	for i := len(items)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+func (h *minMaxHeap[T]) Push(element T) {
+	h.items = append(h.items, element)
+	h.up(len(h.items) - 1)
+}
+
+func (h *minMaxHeap[T]) Pop() Option[T] {
+	return h.PopMin()
+}
+
+func (h *minMaxHeap[T]) Peek() Option[T] {
+	return h.PeekMin()
+}
+
+func (h *minMaxHeap[T]) PopMin() Option[T] {
+	if len(h.items) == 0 {
+		return None[T]()
+	}
+	return Some(h.remove(0))
+}
+
+func (h *minMaxHeap[T]) PopMax() Option[T] {
+	if len(h.items) == 0 {
+		return None[T]()
+	}
+	return Some(h.remove(h.maxIndex()))
+}
+
+func (h *minMaxHeap[T]) PeekMin() Option[T] {
+	if len(h.items) == 0 {
+		return None[T]()
+	}
+	return Some(h.items[0])
+}
+
+func (h *minMaxHeap[T]) PeekMax() Option[T] {
+	if len(h.items) == 0 {
+		return None[T]()
+	}
+	return Some(h.items[h.maxIndex()])
+}
+
+// maxIndex returns the index of the last element of a non-empty heap.
+func (h *minMaxHeap[T]) maxIndex() int {
+	switch {
+	case len(h.items) == 1:
+		return 0
+	case len(h.items) == 2 || h.less(2, 1):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (h *minMaxHeap[T]) remove(index int) T {
+	removed := h.removeAt(index)
+	if index < len(h.items) {
+		h.down(index)
+	}
+	return removed
+}
+
+// onMinLevel reports whether index lies on an even level of the tree.
+func onMinLevel(index int) bool {
+	return bits.Len(uint(index+1))%2 == 1
+}
+
+// before reports whether the element at i belongs above the one at j on a
+// level of the given kind.
+func (h *minMaxHeap[T]) before(i, j int, minLevel bool) bool {
+	if minLevel {
+		return h.less(i, j)
+	}
+	return h.less(j, i)
+}
+
+func (h *minMaxHeap[T]) up(i int) {
+	if i == 0 {
+		return
+	}
+	minLevel := onMinLevel(i)
+	parent := (i - 1) / 2
+	if h.before(parent, i, minLevel) {
+		h.swap(i, parent)
+		i = parent
+		minLevel = !minLevel
+	}
+	for i >= 3 {
+		grandparent := (i - 3) / 4
+		if !h.before(i, grandparent, minLevel) {
+			return
+		}
+		h.swap(i, grandparent)
+		i = grandparent
+	}
+}
+
+func (h *minMaxHeap[T]) down(i int) {
+	minLevel := onMinLevel(i)
+	for {
+		first := 2*i + 1
+		if first >= len(h.items) {
+			return
+		}
+		// Find the best of the up to two children and four grandchildren.
+		best := first
+		for _, candidate := range []int{first + 1, 2*first + 1, 2*first + 2, 2*first + 3, 2*first + 4} {
+			if candidate < len(h.items) && h.before(candidate, best, minLevel) {
+				best = candidate
+			}
+		}
+		if !h.before(best, i, minLevel) {
+			return
+		}
+		h.swap(i, best)
+		if best <= first+1 {
+			return
+		}
+		if parent := (best - 1) / 2; h.before(parent, best, minLevel) {
+			h.swap(best, parent)
+		}
+		i = best
+	}
+}
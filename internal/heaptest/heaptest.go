@@ -0,0 +1,274 @@
+// Package heaptest holds the conformance checks every [collection.Heap]
+// implementation must pass. It is imported only from tests.
+package heaptest
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// Run checks that heaps returned by newHeap behave as min-heaps of ints.
+// newHeap must return a heap holding exactly the given items.
+func Run(t *testing.T, newHeap func(items ...int) collection.Heap[int]) {
+	t.Helper()
+
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := newHeap()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, h.Len())
+		must.True(t, h.IsEmpty())
+		must.True(t, h.Peek().IsNone())
+		must.True(t, h.Pop().IsNone())
+	})
+
+	t.Run("Can build from items", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := newHeap(5, 3, 8, 1, 9, 2, 3)
+		// ========= [A]ssert  =========
+		must.Eq(t, 7, h.Len())
+		must.Eq(t, 1, h.Peek().Unwrap())
+		must.Eq(t, []int{1, 2, 3, 3, 5, 8, 9}, Drain(h))
+		must.True(t, h.IsEmpty())
+	})
+
+	t.Run("Push and Pop work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := newHeap()
+		// ========= [A]ct     =========
+		for _, item := range []int{4, 7, 1, 1, 9, 0, 6} {
+			h.Push(item)
+		}
+		first := h.Pop()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, first.Unwrap())
+		must.Eq(t, 6, h.Len())
+		must.Eq(t, []int{1, 1, 4, 6, 7, 9}, Drain(h))
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := newHeap(6, 2, 4, 8)
+		even := func(item int) bool {
+			return item%2 == 0
+		}
+		large := func(item int) bool {
+			return item > 5
+		}
+
+		// SCENARIO: Contains
+		t.Run("Contains", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, h.Contains(4))
+			must.False(t, h.Contains(5))
+		})
+		// SCENARIO: Aggregate
+		t.Run("Aggregate", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var sum int
+			h.ForEach(func(item int) {
+				sum += item
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 20, sum)
+			must.True(t, h.Every(even))
+			must.True(t, h.Any(large))
+			must.Eq(t, 2, h.Count(large))
+		})
+		// SCENARIO: Values
+		t.Run("Values", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := slices.Sorted(h.Values())
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 4, 6, 8}, actual)
+		})
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]ct     =========
+			h.Clear()
+			h.Push(3)
+			// ========= [A]ssert  =========
+			must.Eq(t, 1, h.Len())
+			must.Eq(t, 3, h.Peek().Unwrap())
+		})
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(4, 1))
+		var expected []int
+		for range 200 {
+			expected = append(expected, rng.IntN(1_000))
+		}
+		h := newHeap(expected...)
+		slices.Sort(expected)
+		// ========= [A]ct     =========
+		for range 5_000 {
+			if rng.IntN(5) < 2 && len(expected) > 0 {
+				must.Eq(t, expected[0], h.Pop().Unwrap())
+				expected = expected[1:]
+				continue
+			}
+			item := rng.IntN(1_000)
+			h.Push(item)
+			index, _ := slices.BinarySearch(expected, item)
+			expected = slices.Insert(expected, index, item)
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, len(expected), h.Len())
+		must.Eq(t, expected, Drain(h))
+	})
+}
+
+// Drain pops every element off h and returns them in the order popped.
+func Drain[T any](h collection.Heap[T]) []T {
+	var res []T
+	for item := h.Pop(); item.IsSome(); item = h.Pop() {
+		res = append(res, item.Unwrap())
+	}
+	return res
+}
+
+// RunMergeable runs [Run] and then checks the handle and meld operations of
+// heaps returned by newHeap.
+func RunMergeable(t *testing.T, newHeap func(items ...int) collection.MergeableHeap[int]) {
+	t.Helper()
+
+	Run(t, func(items ...int) collection.Heap[int] {
+		return newHeap(items...)
+	})
+
+	t.Run("DecreaseKey works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := newHeap(10, 20, 30)
+		handle := h.Insert(40)
+		other := newHeap()
+		foreign := other.Insert(5)
+
+		// SCENARIO: decrease to the top
+		t.Run("DecreaseKey - to top", func(t *testing.T) {
+			// ========= [A]ct     =========
+			decreased := h.DecreaseKey(handle, 1)
+			// ========= [A]ssert  =========
+			must.True(t, decreased)
+			must.Eq(t, 1, handle.Value())
+			must.Eq(t, 1, h.Peek().Unwrap())
+		})
+		// SCENARIO: increasing is rejected
+		t.Run("DecreaseKey - increase", func(t *testing.T) {
+			// ========= [A]ct     =========
+			decreased := h.DecreaseKey(handle, 50)
+			// ========= [A]ssert  =========
+			must.False(t, decreased)
+			must.Eq(t, 1, handle.Value())
+		})
+		// SCENARIO: a handle of another heap is rejected
+		t.Run("DecreaseKey - foreign handle", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.False(t, h.DecreaseKey(foreign, 0))
+			must.Eq(t, 5, foreign.Value())
+		})
+		// SCENARIO: a popped handle is rejected
+		t.Run("DecreaseKey - popped handle", func(t *testing.T) {
+			// ========= [A]ct     =========
+			h.Pop()
+			// ========= [A]ssert  =========
+			must.False(t, h.DecreaseKey(handle, 0))
+			must.Eq(t, []int{10, 20, 30}, Drain(h))
+		})
+		// SCENARIO: a handle of a cleared heap is rejected
+		t.Run("DecreaseKey - cleared", func(t *testing.T) {
+			// ========= [A]ct     =========
+			other.Clear()
+			// ========= [A]ssert  =========
+			must.False(t, other.DecreaseKey(foreign, 0))
+		})
+	})
+
+	t.Run("Meld works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		h := newHeap(8, 2, 6)
+		other := newHeap(7, 1)
+		handle := other.Insert(9)
+		// ========= [A]ct     =========
+		h.Meld(other)
+		h.Meld(h)
+		decreased := h.DecreaseKey(handle, 0)
+		// ========= [A]ssert  =========
+		must.True(t, decreased)
+		must.Eq(t, 6, h.Len())
+		must.True(t, other.IsEmpty())
+		must.True(t, other.Pop().IsNone())
+		must.Eq(t, []int{0, 1, 2, 6, 7, 8}, Drain(h))
+	})
+
+	t.Run("Random handle operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(9, 3))
+		h := newHeap()
+		// Values stay distinct so a popped value identifies its handle.
+		var expected []int
+		handles := map[int]collection.HeapNode[int]{}
+		add := func(value int, handle collection.HeapNode[int]) {
+			index, _ := slices.BinarySearch(expected, value)
+			expected = slices.Insert(expected, index, value)
+			handles[value] = handle
+		}
+		remove := func(value int) {
+			index, _ := slices.BinarySearch(expected, value)
+			expected = slices.Delete(expected, index, index+1)
+			delete(handles, value)
+		}
+		fresh := func(below int) Option[int] {
+			value := rng.IntN(below)
+			if _, ok := handles[value]; ok {
+				return None[int]()
+			}
+			return Some(value)
+		}
+		// ========= [A]ct     =========
+		for range 5_000 {
+			switch rng.IntN(6) {
+			case 0, 1:
+				if value := fresh(1 << 20); value.IsSome() {
+					add(value.Unwrap(), h.Insert(value.Unwrap()))
+				}
+			case 2:
+				other := newHeap()
+				for range rng.IntN(10) {
+					if value := fresh(1 << 20); value.IsSome() {
+						add(value.Unwrap(), other.Insert(value.Unwrap()))
+					}
+				}
+				h.Meld(other)
+			case 3:
+				if len(expected) > 0 {
+					// ========= [A]ssert  =========
+					must.Eq(t, expected[0], h.Pop().Unwrap())
+					remove(expected[0])
+				}
+			default:
+				if len(expected) == 0 {
+					break
+				}
+				value := expected[rng.IntN(len(expected))]
+				if lower := fresh(value + 1); lower.IsSome() {
+					handle := handles[value]
+					must.True(t, h.DecreaseKey(handle, lower.Unwrap()))
+					remove(value)
+					add(lower.Unwrap(), handle)
+				}
+			}
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, len(expected), h.Len())
+		must.Eq(t, expected, Drain(h))
+	})
+}
@@ -0,0 +1,5 @@
+// Package incidencematrix implements [collection.Graph] with a vertex by
+// edge matrix.
+package incidencematrix
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,374 @@
+package incidencematrix
+
+import (
+	"iter"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/edge"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/internal/footprint"
+)
+
+// incidence records how a vertex takes part in an edge. Undirected edges
+// keep the orientation they were added with, so they use the same values.
+type incidence uint8
+
+const (
+	// none means the vertex is not an endpoint of the edge.
+	none incidence = iota
+	// tail means the edge leaves the vertex.
+	tail
+	// head means the edge enters the vertex.
+	head
+	// loop means the edge leaves and enters the vertex.
+	loop
+)
+
+// incidenceMatrix stores in rows[i][e] how the i-th vertex takes part in the
+// e-th edge, and the weight of that edge in weights[e]. Every column holds
+// either a tail and a head or a single loop. Finding the endpoints of an edge
+// scans its column, so most queries cost O(n) per edge.
+type incidenceMatrix[V comparable, E any] struct {
+	directed bool
+	policy   collection.GraphEdgePolicy
+	vertices []V
+	index    map[V]int
+	rows     [][]incidence
+	weights  []E
+}
+
+var _ collection.Graph[int, int] = (*incidenceMatrix[int, int])(nil)
+
+// New returns an empty graph.
+func New[V comparable, E any](directed bool, policy collection.GraphEdgePolicy) *incidenceMatrix[V, E] {
+	return &incidenceMatrix[V, E]{
+		directed: directed,
+		policy:   policy,
+		index:    make(map[V]int),
+	}
+}
+
+func (g *incidenceMatrix[V, E]) Len() int {
+	return len(g.vertices)
+}
+
+func (g *incidenceMatrix[V, E]) Contains(vertex V) bool {
+	_, ok := g.index[vertex]
+	return ok
+}
+
+func (g *incidenceMatrix[V, E]) IsEmpty() bool {
+	return len(g.vertices) == 0
+}
+
+func (g *incidenceMatrix[V, E]) Clear() {
+	clear(g.index)
+	clear(g.vertices)
+	clear(g.weights)
+	g.vertices = g.vertices[:0]
+	g.weights = g.weights[:0]
+	g.rows = nil
+}
+
+func (g *incidenceMatrix[V, E]) Any(pred predicate.Predicate[V]) bool {
+	return slices.ContainsFunc(g.vertices, pred)
+}
+
+func (g *incidenceMatrix[V, E]) Count(pred predicate.Predicate[V]) int {
+	var count int
+	for _, vertex := range g.vertices {
+		if pred(vertex) {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *incidenceMatrix[V, E]) Every(pred predicate.Predicate[V]) bool {
+	for _, vertex := range g.vertices {
+		if !pred(vertex) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *incidenceMatrix[V, E]) ForEach(fn func(V)) {
+	for _, vertex := range g.vertices {
+		fn(vertex)
+	}
+}
+
+func (g *incidenceMatrix[V, E]) IsDirected() bool {
+	return g.directed
+}
+
+func (g *incidenceMatrix[V, E]) EdgePolicy() collection.GraphEdgePolicy {
+	return g.policy
+}
+
+func (g *incidenceMatrix[V, E]) AddVertex(vertex V) bool {
+	if g.Contains(vertex) {
+		return false
+	}
+	g.vertex(vertex)
+	return true
+}
+
+// AddEdge appends a column to every row and runs in O(n).
+func (g *incidenceMatrix[V, E]) AddEdge(from, to V, weight E) bool {
+	i, j := g.vertex(from), g.vertex(to)
+	if e := g.find(i, j); e >= 0 {
+		switch g.policy {
+		case collection.GraphEdgePolicyReject:
+			return false
+		case collection.GraphEdgePolicyReplace:
+			g.weights[e] = weight
+			return true
+		}
+	}
+	for row := range g.rows {
+		g.rows[row] = append(g.rows[row], none)
+	}
+	e := len(g.weights)
+	g.weights = append(g.weights, weight)
+	if i == j {
+		g.rows[i][e] = loop
+	} else {
+		g.rows[i][e] = tail
+		g.rows[j][e] = head
+	}
+	return true
+}
+
+func (g *incidenceMatrix[V, E]) RemoveVertex(vertex V) bool {
+	i, ok := g.index[vertex]
+	if !ok {
+		return false
+	}
+	g.removeColumns(func(e int) bool {
+		return g.rows[i][e] != none
+	})
+	g.rows = slices.Delete(g.rows, i, i+1)
+	g.vertices = slices.Delete(g.vertices, i, i+1)
+	delete(g.index, vertex)
+	for index, v := range g.vertices[i:] {
+		g.index[v] = i + index
+	}
+	return true
+}
+
+func (g *incidenceMatrix[V, E]) RemoveEdge(from, to V) bool {
+	i, j, ok := g.endpoints(from, to)
+	if !ok {
+		return false
+	}
+	before := len(g.weights)
+	g.removeColumns(func(e int) bool {
+		return g.joins(i, j, e)
+	})
+	return len(g.weights) < before
+}
+
+func (g *incidenceMatrix[V, E]) HasEdge(from, to V) bool {
+	return g.Weight(from, to).IsSome()
+}
+
+func (g *incidenceMatrix[V, E]) Weight(from, to V) Option[E] {
+	i, j, ok := g.endpoints(from, to)
+	if !ok {
+		return None[E]()
+	}
+	if e := g.find(i, j); e >= 0 {
+		return Some(g.weights[e])
+	}
+	return None[E]()
+}
+
+// Neighbors scans the row of vertex and then the column of each incident
+// edge, in O(m + n·degree).
+func (g *incidenceMatrix[V, E]) Neighbors(vertex V) iter.Seq2[V, E] {
+	return func(yield func(V, E) bool) {
+		i, ok := g.index[vertex]
+		if !ok {
+			return
+		}
+		for e, role := range g.rows[i] {
+			if role == none || g.directed && role == head {
+				continue
+			}
+			if !yield(g.vertices[g.other(i, e)], g.weights[e]) {
+				return
+			}
+		}
+	}
+}
+
+func (g *incidenceMatrix[V, E]) InDegree(vertex V) int {
+	if !g.directed {
+		return g.OutDegree(vertex)
+	}
+	return g.degree(vertex, head)
+}
+
+func (g *incidenceMatrix[V, E]) OutDegree(vertex V) int {
+	if !g.directed {
+		return g.degree(vertex, none)
+	}
+	return g.degree(vertex, tail)
+}
+
+func (g *incidenceMatrix[V, E]) EdgeCount() int {
+	return len(g.weights)
+}
+
+func (g *incidenceMatrix[V, E]) Vertices() iter.Seq[V] {
+	return slices.Values(g.vertices)
+}
+
+// Edges yields edges in the order they were added.
+func (g *incidenceMatrix[V, E]) Edges() iter.Seq[edge.Edge[V, E]] {
+	return func(yield func(edge.Edge[V, E]) bool) {
+		for e, weight := range g.weights {
+			from, to := -1, -1
+			for i, row := range g.rows {
+				switch row[e] {
+				case tail:
+					from = i
+				case head:
+					to = i
+				case loop:
+					from, to = i, i
+				}
+			}
+			if !yield(edge.New(g.vertices[from], g.vertices[to], weight)) {
+				return
+			}
+		}
+	}
+}
+
+func (g *incidenceMatrix[V, E]) MemoryFootprint() int {
+	bytes := footprint.Of[incidenceMatrix[V, E]]() +
+		footprint.Slice[V](cap(g.vertices)) +
+		footprint.Map[V, int](len(g.index)) +
+		footprint.Slice[[]incidence](cap(g.rows)) +
+		footprint.Slice[E](cap(g.weights))
+	for _, row := range g.rows {
+		bytes += footprint.Slice[incidence](cap(row))
+	}
+	return bytes
+}
+
+// Footprint estimates the bytes held by a graph with the given numbers of
+// vertices and edges, assuming slices without spare capacity.
+func Footprint[V comparable, E any](vertices, edges int, _ bool) int {
+	return footprint.Of[incidenceMatrix[V, E]]() +
+		footprint.Slice[V](vertices) +
+		footprint.Map[V, int](vertices) +
+		footprint.Slice[[]incidence](vertices) +
+		vertices*footprint.Slice[incidence](edges) +
+		footprint.Slice[E](edges)
+}
+
+// vertex returns the index of vertex, adding an empty row for it first if
+// absent.
+func (g *incidenceMatrix[V, E]) vertex(vertex V) int {
+	if i, ok := g.index[vertex]; ok {
+		return i
+	}
+	i := len(g.vertices)
+	g.index[vertex] = i
+	g.vertices = append(g.vertices, vertex)
+	g.rows = append(g.rows, make([]incidence, len(g.weights)))
+	return i
+}
+
+// endpoints returns the indices of from and to, reporting false if either is
+// absent.
+func (g *incidenceMatrix[V, E]) endpoints(from, to V) (int, int, bool) {
+	i, ok := g.index[from]
+	if !ok {
+		return 0, 0, false
+	}
+	j, ok := g.index[to]
+	return i, j, ok
+}
+
+// find returns the column of the first edge from the i-th vertex to the j-th,
+// or -1 if there is none.
+func (g *incidenceMatrix[V, E]) find(i, j int) int {
+	for e := range g.weights {
+		if g.joins(i, j, e) {
+			return e
+		}
+	}
+	return -1
+}
+
+// joins reports whether edge e leads from the i-th vertex to the j-th.
+func (g *incidenceMatrix[V, E]) joins(i, j, e int) bool {
+	switch role := g.rows[i][e]; {
+	case i == j:
+		return role == loop
+	case role == tail:
+		return g.rows[j][e] == head
+	case role == head && !g.directed:
+		return g.rows[j][e] == tail
+	default:
+		return false
+	}
+}
+
+// other returns the index of the endpoint of edge e that is not the i-th
+// vertex, or i itself for a loop.
+func (g *incidenceMatrix[V, E]) other(i, e int) int {
+	if g.rows[i][e] == loop {
+		return i
+	}
+	for j, row := range g.rows {
+		if j != i && row[e] != none {
+			return j
+		}
+	}
+	return i
+}
+
+// degree counts the edges in which vertex takes the given role, counting
+// loops for either role, or every incident edge if role is none.
+func (g *incidenceMatrix[V, E]) degree(vertex V, role incidence) int {
+	i, ok := g.index[vertex]
+	if !ok {
+		return 0
+	}
+	var degree int
+	for _, r := range g.rows[i] {
+		if r != none && (r == role || r == loop || role == none) {
+			degree++
+		}
+	}
+	return degree
+}
+
+// removeColumns deletes the edges whose column index satisfies drop.
+func (g *incidenceMatrix[V, E]) removeColumns(drop func(e int) bool) {
+	kept := 0
+	for e := range g.weights {
+		if drop(e) {
+			continue
+		}
+		for _, row := range g.rows {
+			row[kept] = row[e]
+		}
+		g.weights[kept] = g.weights[e]
+		kept++
+	}
+	clear(g.weights[kept:])
+	g.weights = g.weights[:kept]
+	for i := range g.rows {
+		g.rows[i] = g.rows[i][:kept]
+	}
+}
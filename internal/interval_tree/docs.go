@@ -0,0 +1,4 @@
+// Package intervaltree implements [collection.IntervalTree].
+package intervaltree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,222 @@
+package intervaltree
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/interval"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+)
+
+// intervalTree is an AVL tree of intervals ordered by [interval.Compare].
+// Every node records the greatest end point in its subtree, which lets
+// queries skip subtrees that finish before the query starts.
+type intervalTree[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	root    *node[K, V]
+	len     int
+}
+
+var _ collection.IntervalTree[int, int] = (*intervalTree[int, int])(nil)
+
+// New returns an empty interval tree whose end points are ordered by fn.
+func New[K any, V any](fn func(a, b K) compare.Order) *intervalTree[K, V] {
+	return &intervalTree[K, V]{
+		compare: fn,
+		root:    nil,
+		len:     0,
+	}
+}
+
+func (t *intervalTree[K, V]) Len() int {
+	return t.len
+}
+
+func (t *intervalTree[K, V]) Contains(element interval.Interval[K]) bool {
+	return t.find(element) != nil
+}
+
+func (t *intervalTree[K, V]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *intervalTree[K, V]) Clear() {
+	t.root = nil
+	t.len = 0
+}
+
+func (t *intervalTree[K, V]) Any(pred predicate.Predicate[kv.Pair[interval.Interval[K], V]]) bool {
+	for iv, value := range t.All() {
+		if pred(kv.New(iv, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *intervalTree[K, V]) Every(pred predicate.Predicate[kv.Pair[interval.Interval[K], V]]) bool {
+	for iv, value := range t.All() {
+		if !pred(kv.New(iv, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *intervalTree[K, V]) Count(pred predicate.Predicate[kv.Pair[interval.Interval[K], V]]) int {
+	var count int
+	for iv, value := range t.All() {
+		if pred(kv.New(iv, value)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *intervalTree[K, V]) ForEach(fn func(pair kv.Pair[interval.Interval[K], V])) {
+	for iv, value := range t.All() {
+		fn(kv.New(iv, value))
+	}
+}
+
+func (t *intervalTree[K, V]) Insert(iv interval.Interval[K], value V) {
+	var inserted bool
+	t.root, inserted = t.insert(t.root, iv, value)
+	if inserted {
+		t.len++
+	}
+}
+
+func (t *intervalTree[K, V]) Delete(iv interval.Interval[K]) Option[V] {
+	var deleted Option[V]
+	t.root, deleted = t.delete(t.root, iv)
+	if deleted.IsSome() {
+		t.len--
+	}
+	return deleted
+}
+
+func (t *intervalTree[K, V]) Get(iv interval.Interval[K]) Option[V] {
+	n := t.find(iv)
+	if n == nil {
+		return None[V]()
+	}
+	return Some(n.value)
+}
+
+func (t *intervalTree[K, V]) Overlapping(iv interval.Interval[K]) iter.Seq2[interval.Interval[K], V] {
+	var walk func(n *node[K, V], yield func(interval.Interval[K], V) bool) bool
+	walk = func(n *node[K, V], yield func(interval.Interval[K], V) bool) bool {
+		// Nothing in this subtree ends after the query starts
+		if n == nil || !t.reaches(iv, n.maxEnd) {
+			return true
+		}
+		if !walk(n.left, yield) {
+			return false
+		}
+		if n.interval.Overlaps(iv, t.compare) && !yield(n.interval, n.value) {
+			return false
+		}
+		// Everything to the right starts no earlier than n
+		return !t.reaches(n.interval, iv) || walk(n.right, yield)
+	}
+	return func(yield func(interval.Interval[K], V) bool) {
+		if iv.IsEmpty(t.compare) {
+			return
+		}
+		walk(t.root, yield)
+	}
+}
+
+func (t *intervalTree[K, V]) Containing(point K) iter.Seq2[interval.Interval[K], V] {
+	return t.Overlapping(interval.Point(point))
+}
+
+func (t *intervalTree[K, V]) All() iter.Seq2[interval.Interval[K], V] {
+	return func(yield func(interval.Interval[K], V) bool) {
+		inorder(t.root, yield)
+	}
+}
+
+func (t *intervalTree[K, V]) find(iv interval.Interval[K]) *node[K, V] {
+	current := t.root
+	for current != nil {
+		order := interval.Compare(iv, current.interval, t.compare)
+		switch {
+		case order.IsLess():
+			current = current.left
+		case order.IsGreater():
+			current = current.right
+		default:
+			return current
+		}
+	}
+	return nil
+}
+
+// reaches reports whether some point lies between the start of from and the
+// end of to.
+func (t *intervalTree[K, V]) reaches(from, to interval.Interval[K]) bool {
+	start, end := from.Start(), to.End()
+	return !interval.WithBounds(start.Key(), start.Value(), end.Key(), end.Value()).IsEmpty(t.compare)
+}
+
+// insert adds or updates iv in the subtree rooted at n and returns the new
+// subtree root, reporting whether a node was added.
+func (t *intervalTree[K, V]) insert(n *node[K, V], iv interval.Interval[K], value V) (*node[K, V], bool) {
+	if n == nil {
+		return &node[K, V]{
+			interval: iv,
+			value:    value,
+			maxEnd:   iv,
+			height:   1,
+		}, true
+	}
+	var inserted bool
+	order := interval.Compare(iv, n.interval, t.compare)
+	switch {
+	case order.IsLess():
+		n.left, inserted = t.insert(n.left, iv, value)
+	case order.IsGreater():
+		n.right, inserted = t.insert(n.right, iv, value)
+	default:
+		n.value = value
+		return n, false
+	}
+	return rebalance(n, t.compare), inserted
+}
+
+// delete removes iv from the subtree rooted at n and returns the new subtree
+// root along with the removed payload.
+func (t *intervalTree[K, V]) delete(n *node[K, V], iv interval.Interval[K]) (*node[K, V], Option[V]) {
+	if n == nil {
+		return nil, None[V]()
+	}
+	var deleted Option[V]
+	order := interval.Compare(iv, n.interval, t.compare)
+	switch {
+	case order.IsLess():
+		n.left, deleted = t.delete(n.left, iv)
+	case order.IsGreater():
+		n.right, deleted = t.delete(n.right, iv)
+	default:
+		deleted = Some(n.value)
+		if n.left == nil {
+			return n.right, deleted
+		}
+		if n.right == nil {
+			return n.left, deleted
+		}
+		// Replace n with its in-order successor
+		successor := minimum(n.right)
+		n.right = deleteMin(n.right, t.compare)
+		successor.left = n.left
+		successor.right = n.right
+		n = successor
+	}
+	return rebalance(n, t.compare), deleted
+}
@@ -0,0 +1,103 @@
+package intervaltree
+
+import (
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/interval"
+)
+
+// node is an AVL node augmented with the interval of greatest end point in
+// its subtree.
+type node[K any, V any] struct {
+	interval interval.Interval[K]
+	value    V
+	maxEnd   interval.Interval[K]
+	height   int
+	left     *node[K, V]
+	right    *node[K, V]
+}
+
+func height[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *node[K, V]) balanceFactor() int {
+	return height(n.right) - height(n.left)
+}
+
+// update recomputes the height and greatest end point of n from its children.
+func (n *node[K, V]) update(fn func(a, b K) compare.Order) {
+	n.height = 1 + max(height(n.left), height(n.right))
+	n.maxEnd = n.interval
+	for _, child := range []*node[K, V]{n.left, n.right} {
+		if child != nil && interval.CompareEnd(child.maxEnd, n.maxEnd, fn).IsGreater() {
+			n.maxEnd = child.maxEnd
+		}
+	}
+}
+
+func rotateLeft[K any, V any](n *node[K, V], fn func(a, b K) compare.Order) *node[K, V] {
+	pivot := n.right
+	n.right = pivot.left
+	pivot.left = n
+	n.update(fn)
+	pivot.update(fn)
+	return pivot
+}
+
+func rotateRight[K any, V any](n *node[K, V], fn func(a, b K) compare.Order) *node[K, V] {
+	pivot := n.left
+	n.left = pivot.right
+	pivot.right = n
+	n.update(fn)
+	pivot.update(fn)
+	return pivot
+}
+
+// rebalance recomputes the augmented fields of n and applies the single or
+// double rotation needed to bring its balance factor back within [-1, 1].
+func rebalance[K any, V any](n *node[K, V], fn func(a, b K) compare.Order) *node[K, V] {
+	n.update(fn)
+	switch balance := n.balanceFactor(); {
+	case balance > 1:
+		if n.right.balanceFactor() < 0 {
+			n.right = rotateRight(n.right, fn)
+		}
+		return rotateLeft(n, fn)
+	case balance < -1:
+		if n.left.balanceFactor() > 0 {
+			n.left = rotateLeft(n.left, fn)
+		}
+		return rotateRight(n, fn)
+	default:
+		return n
+	}
+}
+
+func minimum[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+// deleteMin unlinks the smallest node of the subtree rooted at n and returns
+// the new subtree root.
+func deleteMin[K any, V any](n *node[K, V], fn func(a, b K) compare.Order) *node[K, V] {
+	if n.left == nil {
+		return n.right
+	}
+	n.left = deleteMin(n.left, fn)
+	return rebalance(n, fn)
+}
+
+func inorder[K any, V any](n *node[K, V], yield func(interval.Interval[K], V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return inorder(n.left, yield) &&
+		yield(n.interval, n.value) &&
+		inorder(n.right, yield)
+}
@@ -0,0 +1,4 @@
+// Package kdtree implements [collection.KDTree].
+package kdtree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
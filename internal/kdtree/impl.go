@@ -0,0 +1,367 @@
+package kdtree
+
+import (
+	"iter"
+	"math"
+	"slices"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/predicate"
+)
+
+// kdTree keeps every point smaller than a node's split coordinate in its left
+// subtree and every point at least as large in its right subtree. The split
+// axis cycles through the dimensions by depth.
+type kdTree[P any] struct {
+	dimensions int
+	coordinate func(p P, axis int) float64
+	distance   func(a, b P) float64
+	root       *node[P]
+	len        int
+}
+
+type node[P any] struct {
+	point P
+	axis  int
+	left  *node[P]
+	right *node[P]
+}
+
+var _ collection.KDTree[[]float64] = (*kdTree[[]float64])(nil)
+
+// New returns a balanced K-D tree holding points.
+//
+// coordinate(p, axis) returns the coordinate of p for axis in
+// [0, dimensions). distance must never be smaller than the difference
+// between two points along any single axis, since searches use that
+// difference to skip subtrees.
+func New[P any](
+	points []P,
+	dimensions int,
+	coordinate func(p P, axis int) float64,
+	distance func(a, b P) float64,
+) *kdTree[P] {
+	t := &kdTree[P]{
+		dimensions: max(dimensions, 1),
+		coordinate: coordinate,
+		distance:   distance,
+		len:        len(points),
+	}
+	t.root = t.build(slices.Clone(points), 0)
+	return t
+}
+
+func (t *kdTree[P]) Len() int {
+	return t.len
+}
+
+func (t *kdTree[P]) Contains(element P) bool {
+	return t.find(element) != nil
+}
+
+func (t *kdTree[P]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *kdTree[P]) Clear() {
+	t.root = nil
+	t.len = 0
+}
+
+func (t *kdTree[P]) Any(pred predicate.Predicate[P]) bool {
+	for point := range t.All() {
+		if pred(point) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *kdTree[P]) Count(pred predicate.Predicate[P]) int {
+	var count int
+	for point := range t.All() {
+		if pred(point) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *kdTree[P]) Every(pred predicate.Predicate[P]) bool {
+	for point := range t.All() {
+		if !pred(point) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *kdTree[P]) ForEach(fn func(P)) {
+	for point := range t.All() {
+		fn(point)
+	}
+}
+
+func (t *kdTree[P]) Insert(point P) {
+	t.len++
+	link := &t.root
+	var depth int
+	for *link != nil {
+		n := *link
+		if t.coordinate(point, n.axis) < t.coordinate(n.point, n.axis) {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+		depth++
+	}
+	*link = &node[P]{
+		point: point,
+		axis:  depth % t.dimensions,
+	}
+}
+
+func (t *kdTree[P]) Delete(point P) bool {
+	target := t.find(point)
+	if target == nil {
+		return false
+	}
+	t.root = t.remove(t.root, target)
+	t.len--
+	return true
+}
+
+func (t *kdTree[P]) Height() int {
+	var walk func(n *node[P]) int
+	walk = func(n *node[P]) int {
+		if n == nil {
+			return 0
+		}
+		return 1 + max(walk(n.left), walk(n.right))
+	}
+	return walk(t.root)
+}
+
+// Nearest keeps the k best candidates sorted by distance and skips the far
+// side of a split once the splitting plane is farther than the worst of them.
+func (t *kdTree[P]) Nearest(point P, k int) []P {
+	if k <= 0 {
+		return nil
+	}
+	type candidate struct {
+		point    P
+		distance float64
+	}
+	best := make([]candidate, 0, min(k, t.len))
+	worst := func() float64 {
+		if len(best) < k {
+			return math.Inf(1)
+		}
+		return best[len(best)-1].distance
+	}
+	var walk func(n *node[P])
+	walk = func(n *node[P]) {
+		if n == nil {
+			return
+		}
+		if d := t.distance(point, n.point); d < worst() {
+			at, _ := slices.BinarySearchFunc(best, d, func(c candidate, d float64) int {
+				if c.distance <= d {
+					return -1
+				}
+				return 1
+			})
+			if len(best) == k {
+				best = best[:k-1]
+			}
+			best = slices.Insert(best, at, candidate{point: n.point, distance: d})
+		}
+		near, far := n.left, n.right
+		diff := t.coordinate(point, n.axis) - t.coordinate(n.point, n.axis)
+		if diff >= 0 {
+			near, far = far, near
+		}
+		walk(near)
+		if math.Abs(diff) <= worst() {
+			walk(far)
+		}
+	}
+	walk(t.root)
+	res := make([]P, len(best))
+	for i, c := range best {
+		res[i] = c.point
+	}
+	return res
+}
+
+func (t *kdTree[P]) WithinRadius(point P, radius float64) iter.Seq[P] {
+	var walk func(n *node[P], yield func(P) bool) bool
+	walk = func(n *node[P], yield func(P) bool) bool {
+		if n == nil {
+			return true
+		}
+		if t.distance(point, n.point) <= radius && !yield(n.point) {
+			return false
+		}
+		diff := t.coordinate(point, n.axis) - t.coordinate(n.point, n.axis)
+		if diff-radius < 0 && !walk(n.left, yield) {
+			return false
+		}
+		return diff+radius < 0 || walk(n.right, yield)
+	}
+	return func(yield func(P) bool) {
+		walk(t.root, yield)
+	}
+}
+
+func (t *kdTree[P]) RangeBox(min, max P) iter.Seq[P] {
+	inside := func(point P) bool {
+		for axis := range t.dimensions {
+			c := t.coordinate(point, axis)
+			if c < t.coordinate(min, axis) || c > t.coordinate(max, axis) {
+				return false
+			}
+		}
+		return true
+	}
+	var walk func(n *node[P], yield func(P) bool) bool
+	walk = func(n *node[P], yield func(P) bool) bool {
+		if n == nil {
+			return true
+		}
+		if inside(n.point) && !yield(n.point) {
+			return false
+		}
+		split := t.coordinate(n.point, n.axis)
+		if t.coordinate(min, n.axis) < split && !walk(n.left, yield) {
+			return false
+		}
+		return t.coordinate(max, n.axis) < split || walk(n.right, yield)
+	}
+	return func(yield func(P) bool) {
+		walk(t.root, yield)
+	}
+}
+
+func (t *kdTree[P]) All() iter.Seq[P] {
+	var walk func(n *node[P], yield func(P) bool) bool
+	walk = func(n *node[P], yield func(P) bool) bool {
+		return n == nil ||
+			walk(n.left, yield) &&
+				yield(n.point) &&
+				walk(n.right, yield)
+	}
+	return func(yield func(P) bool) {
+		walk(t.root, yield)
+	}
+}
+
+// build arranges points into a balanced subtree split on the axis for depth.
+// The split point is the first point holding the median coordinate, so that
+// every point to its left is strictly smaller.
+func (t *kdTree[P]) build(points []P, depth int) *node[P] {
+	if len(points) == 0 {
+		return nil
+	}
+	axis := depth % t.dimensions
+	slices.SortFunc(points, func(a, b P) int {
+		return compareFloat(t.coordinate(a, axis), t.coordinate(b, axis))
+	})
+	median := len(points) / 2
+	for median > 0 && t.coordinate(points[median-1], axis) == t.coordinate(points[median], axis) {
+		median--
+	}
+	return &node[P]{
+		point: points[median],
+		axis:  axis,
+		left:  t.build(points[:median], depth+1),
+		right: t.build(points[median+1:], depth+1),
+	}
+}
+
+// find returns a node whose point has the same coordinates as point.
+func (t *kdTree[P]) find(point P) *node[P] {
+	current := t.root
+	for current != nil {
+		if t.equal(point, current.point) {
+			return current
+		}
+		if t.coordinate(point, current.axis) < t.coordinate(current.point, current.axis) {
+			current = current.left
+		} else {
+			current = current.right
+		}
+	}
+	return nil
+}
+
+// remove unlinks target from the subtree rooted at n and returns the new
+// subtree root. A removed inner node takes the point with the smallest split
+// coordinate from its right subtree, or from its left subtree, which then
+// becomes the right one, when it has no right child.
+func (t *kdTree[P]) remove(n, target *node[P]) *node[P] {
+	if n != target {
+		if t.coordinate(target.point, n.axis) < t.coordinate(n.point, n.axis) {
+			n.left = t.remove(n.left, target)
+		} else {
+			n.right = t.remove(n.right, target)
+		}
+		return n
+	}
+	switch {
+	case n.right != nil:
+		replacement := t.minimum(n.right, n.axis)
+		n.point = replacement.point
+		n.right = t.remove(n.right, replacement)
+	case n.left != nil:
+		replacement := t.minimum(n.left, n.axis)
+		n.point = replacement.point
+		n.right = t.remove(n.left, replacement)
+		n.left = nil
+	default:
+		return nil
+	}
+	return n
+}
+
+// minimum returns the node of the subtree rooted at n with the smallest
+// coordinate along axis.
+func (t *kdTree[P]) minimum(n *node[P], axis int) *node[P] {
+	if n == nil {
+		return nil
+	}
+	if n.axis == axis && n.left == nil {
+		return n
+	}
+	best := n
+	candidates := []*node[P]{t.minimum(n.left, axis)}
+	if n.axis != axis {
+		candidates = append(candidates, t.minimum(n.right, axis))
+	}
+	for _, candidate := range candidates {
+		if candidate != nil && t.coordinate(candidate.point, axis) < t.coordinate(best.point, axis) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+func (t *kdTree[P]) equal(a, b P) bool {
+	for axis := range t.dimensions {
+		if t.coordinate(a, axis) != t.coordinate(b, axis) {
+			return false
+		}
+	}
+	return true
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
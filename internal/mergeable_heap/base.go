@@ -0,0 +1,166 @@
+package mergeableheap
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/predicate"
+)
+
+// base holds the state and the shape-independent methods shared by the
+// heaps in this package.
+type base[T any] struct {
+	compare func(a, b T) compare.Order
+	owner   *owner
+	root    *node[T]
+	len     int
+}
+
+func newBase[T any](fn func(a, b T) compare.Order) base[T] {
+	return base[T]{
+		compare: fn,
+		owner:   &owner{},
+	}
+}
+
+func (h *base[T]) Len() int {
+	return h.len
+}
+
+// Contains reports whether an element that orders equal to element is
+// present. It runs in O(n).
+func (h *base[T]) Contains(element T) bool {
+	return h.Any(func(item T) bool {
+		return h.compare(item, element) == compare.OrderEqual
+	})
+}
+
+func (h *base[T]) IsEmpty() bool {
+	return h.len == 0
+}
+
+// Clear empties the heap in O(1) and invalidates every handle it issued.
+func (h *base[T]) Clear() {
+	h.owner = &owner{}
+	h.root = nil
+	h.len = 0
+}
+
+func (h *base[T]) Any(pred predicate.Predicate[T]) bool {
+	for value := range h.Values() {
+		if pred(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *base[T]) Count(pred predicate.Predicate[T]) int {
+	var count int
+	for value := range h.Values() {
+		if pred(value) {
+			count++
+		}
+	}
+	return count
+}
+
+func (h *base[T]) Every(pred predicate.Predicate[T]) bool {
+	for value := range h.Values() {
+		if !pred(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *base[T]) ForEach(fn func(T)) {
+	for value := range h.Values() {
+		fn(value)
+	}
+}
+
+func (h *base[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if h.root == nil {
+			return
+		}
+		stack := []*node[T]{h.root}
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(current.item.value) {
+				return
+			}
+			if current.right != nil {
+				stack = append(stack, current.right)
+			}
+			if current.left != nil {
+				stack = append(stack, current.left)
+			}
+		}
+	}
+}
+
+// less reports whether the element at a orders strictly before the one at b.
+func (h *base[T]) less(a, b *node[T]) bool {
+	return h.compare(a.item.value, b.item.value) == compare.OrderLess
+}
+
+// newNode returns a detached node holding value, owned by this heap.
+func (h *base[T]) newNode(value T) *node[T] {
+	c := &cell[T]{}
+	c.node.item = &c.item
+	c.item.value = value
+	c.item.node = &c.node
+	c.item.owner = h.owner
+	h.len++
+	return &c.node
+}
+
+// release marks the item of a popped node as no longer part of any heap.
+func (h *base[T]) release(n *node[T]) T {
+	h.len--
+	n.item.owner = nil
+	n.item.node = nil
+	return n.item.value
+}
+
+// lower stores element behind handle and returns its node if handle is a
+// live handle of this heap and element does not order after its value.
+func (h *base[T]) lower(handle collection.HeapNode[T], element T) Option[*node[T]] {
+	item, ok := handle.(*item[T])
+	if !ok || item.owner == nil || item.owner.find() != h.owner {
+		return None[*node[T]]()
+	}
+	if h.compare(element, item.value) == compare.OrderGreater {
+		return None[*node[T]]()
+	}
+	item.value = element
+	return Some(item.node)
+}
+
+// absorb takes over the bookkeeping of other, whose nodes the caller has
+// already linked into this heap.
+func (h *base[T]) absorb(other *base[T]) {
+	other.owner.next = h.owner
+	h.len += other.len
+	other.owner = &owner{}
+	other.root = nil
+	other.len = 0
+}
+
+// meldAny moves the elements of a heap of a different kind into h one at a
+// time. Handles from other cannot be carried over and become invalid.
+func meldAny[T any](h collection.MergeableHeap[T], other collection.MergeableHeap[T]) {
+	if other == h {
+		return
+	}
+	for value := range other.Values() {
+		h.Push(value)
+	}
+	other.Clear()
+}
@@ -0,0 +1,159 @@
+package mergeableheap
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// binomialHeap is a list of binomial trees of distinct degrees, linked
+// through right in increasing order of degree. Melding adds the two lists
+// like binary numbers, in O(log n). DecreaseKey sifts the element up by
+// swapping items between nodes, in O(log n).
+type binomialHeap[T any] struct {
+	base[T]
+}
+
+var _ collection.MergeableHeap[int] = (*binomialHeap[int])(nil)
+
+// NewBinomial returns an empty binomial heap ordered by fn.
+func NewBinomial[T any](fn func(a, b T) compare.Order) *binomialHeap[T] {
+	return &binomialHeap[T]{
+		base: newBase(fn),
+	}
+}
+
+func (h *binomialHeap[T]) Push(element T) {
+	h.Insert(element)
+}
+
+func (h *binomialHeap[T]) Insert(element T) collection.HeapNode[T] {
+	n := h.newNode(element)
+	h.root = h.union(h.root, n)
+	return n.item
+}
+
+func (h *binomialHeap[T]) Pop() Option[T] {
+	if h.root == nil {
+		return None[T]()
+	}
+	var prev, before *node[T]
+	best := h.root
+	for current := h.root; current != nil; prev, current = current, current.right {
+		if h.less(current, best) {
+			best, before = current, prev
+		}
+	}
+	if before == nil {
+		h.root = best.right
+	} else {
+		before.right = best.right
+	}
+	// The children of a root are in decreasing order of degree.
+	var children *node[T]
+	for child := best.left; child != nil; {
+		next := child.right
+		child.parent = nil
+		child.right = children
+		children = child
+		child = next
+	}
+	h.root = h.union(h.root, children)
+	return Some(h.release(best))
+}
+
+func (h *binomialHeap[T]) Peek() Option[T] {
+	if h.root == nil {
+		return None[T]()
+	}
+	best := h.root
+	for current := h.root.right; current != nil; current = current.right {
+		if h.less(current, best) {
+			best = current
+		}
+	}
+	return Some(best.item.value)
+}
+
+func (h *binomialHeap[T]) DecreaseKey(handle collection.HeapNode[T], element T) bool {
+	found := h.lower(handle, element)
+	if found.IsNone() {
+		return false
+	}
+	for n := found.Unwrap(); n.parent != nil && h.less(n, n.parent); n = n.parent {
+		n.item, n.parent.item = n.parent.item, n.item
+		n.item.node = n
+		n.parent.item.node = n.parent
+	}
+	return true
+}
+
+func (h *binomialHeap[T]) Meld(other collection.MergeableHeap[T]) {
+	if other, ok := other.(*binomialHeap[T]); ok {
+		if other != h {
+			h.root = h.union(h.root, other.root)
+			h.absorb(&other.base)
+		}
+		return
+	}
+	meldAny(h, other)
+}
+
+// union merges two root lists and links trees of equal degree until every
+// degree occurs at most once.
+func (h *binomialHeap[T]) union(a, b *node[T]) *node[T] {
+	head := mergeByDegree(a, b)
+	if head == nil {
+		return nil
+	}
+	var prev *node[T]
+	current := head
+	for next := current.right; next != nil; next = current.right {
+		switch {
+		case current.rank != next.rank, next.right != nil && next.right.rank == current.rank:
+			prev, current = current, next
+		case !h.less(next, current):
+			current.right = next.right
+			link(current, next)
+		default:
+			if prev == nil {
+				head = next
+			} else {
+				prev.right = next
+			}
+			link(next, current)
+			current = next
+		}
+	}
+	return head
+}
+
+// mergeByDegree interleaves two root lists in increasing order of degree.
+func mergeByDegree[T any](a, b *node[T]) *node[T] {
+	var head node[T]
+	tail := &head
+	for a != nil && b != nil {
+		if a.rank <= b.rank {
+			tail.right, a = a, a.right
+		} else {
+			tail.right, b = b, b.right
+		}
+		tail = tail.right
+	}
+	if a != nil {
+		tail.right = a
+	} else {
+		tail.right = b
+	}
+	return head.right
+}
+
+// link makes child, a root of the same degree as parent, the first child of
+// parent.
+func link[T any](parent, child *node[T]) {
+	child.parent = parent
+	child.right = parent.left
+	parent.left = child
+	parent.rank++
+}
@@ -0,0 +1,5 @@
+// Package mergeableheap implements [collection.MergeableHeap] as binomial,
+// Fibonacci, leftist, skew and pairing heaps.
+package mergeableheap
+
+import _ "codeberg.org/yaadata/bina/core/collection"
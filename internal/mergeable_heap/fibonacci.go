@@ -0,0 +1,201 @@
+package mergeableheap
+
+import (
+	"math/bits"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// fibonacciHeap is Fredman and Tarjan's Fibonacci heap. Roots are kept in a
+// doubly linked list starting at root, and work is deferred until Pop, which
+// links roots of equal degree together. DecreaseKey cuts a node out to the
+// root list and cascades the cut up through marked ancestors, which keeps
+// every subtree of degree k at least F(k+2) nodes large.
+//
+// Insert, Meld, Peek and DecreaseKey run in amortized O(1) and Pop in
+// amortized O(log n).
+type fibonacciHeap[T any] struct {
+	base[T]
+	min  *node[T]
+	tail *node[T]
+}
+
+var _ collection.MergeableHeap[int] = (*fibonacciHeap[int])(nil)
+
+// NewFibonacci returns an empty Fibonacci heap ordered by fn.
+func NewFibonacci[T any](fn func(a, b T) compare.Order) *fibonacciHeap[T] {
+	return &fibonacciHeap[T]{
+		base: newBase(fn),
+	}
+}
+
+func (h *fibonacciHeap[T]) Clear() {
+	h.base.Clear()
+	h.min = nil
+	h.tail = nil
+}
+
+func (h *fibonacciHeap[T]) Push(element T) {
+	h.Insert(element)
+}
+
+func (h *fibonacciHeap[T]) Insert(element T) collection.HeapNode[T] {
+	n := h.newNode(element)
+	h.addRoot(n)
+	return n.item
+}
+
+func (h *fibonacciHeap[T]) Pop() Option[T] {
+	if h.min == nil {
+		return None[T]()
+	}
+	removed := h.min
+	for child := removed.left; child != nil; {
+		next := child.right
+		child.parent, child.prev, child.right = nil, nil, nil
+		child.marked = false
+		h.addRoot(child)
+		child = next
+	}
+	removed.left = nil
+	h.removeRoot(removed)
+	h.consolidate()
+	return Some(h.release(removed))
+}
+
+func (h *fibonacciHeap[T]) Peek() Option[T] {
+	if h.min == nil {
+		return None[T]()
+	}
+	return Some(h.min.item.value)
+}
+
+func (h *fibonacciHeap[T]) DecreaseKey(handle collection.HeapNode[T], element T) bool {
+	found := h.lower(handle, element)
+	if found.IsNone() {
+		return false
+	}
+	n := found.Unwrap()
+	if parent := n.parent; parent != nil && h.less(n, parent) {
+		h.cut(n)
+		for parent.parent != nil {
+			if !parent.marked {
+				parent.marked = true
+				break
+			}
+			next := parent.parent
+			h.cut(parent)
+			parent = next
+		}
+	}
+	if h.less(n, h.min) {
+		h.min = n
+	}
+	return true
+}
+
+func (h *fibonacciHeap[T]) Meld(other collection.MergeableHeap[T]) {
+	if other, ok := other.(*fibonacciHeap[T]); ok {
+		if other != h && other.root != nil {
+			if h.root == nil {
+				h.root = other.root
+			} else {
+				h.tail.right = other.root
+				other.root.prev = h.tail
+			}
+			h.tail = other.tail
+			if h.min == nil || h.less(other.min, h.min) {
+				h.min = other.min
+			}
+			h.absorb(&other.base)
+			other.min = nil
+			other.tail = nil
+		}
+		return
+	}
+	meldAny(h, other)
+}
+
+// addRoot appends a detached node to the root list.
+func (h *fibonacciHeap[T]) addRoot(n *node[T]) {
+	if h.root == nil {
+		h.root = n
+	} else {
+		h.tail.right = n
+		n.prev = h.tail
+	}
+	h.tail = n
+	if h.min == nil || h.less(n, h.min) {
+		h.min = n
+	}
+}
+
+// removeRoot unlinks n from the root list.
+func (h *fibonacciHeap[T]) removeRoot(n *node[T]) {
+	if n.prev == nil {
+		h.root = n.right
+	} else {
+		n.prev.right = n.right
+	}
+	if n.right == nil {
+		h.tail = n.prev
+	} else {
+		n.right.prev = n.prev
+	}
+	n.prev, n.right = nil, nil
+}
+
+// cut moves n from its parent's child list to the root list.
+func (h *fibonacciHeap[T]) cut(n *node[T]) {
+	parent := n.parent
+	if n.prev == nil {
+		parent.left = n.right
+	} else {
+		n.prev.right = n.right
+	}
+	if n.right != nil {
+		n.right.prev = n.prev
+	}
+	parent.rank--
+	n.parent, n.prev, n.right = nil, nil, nil
+	n.marked = false
+	h.addRoot(n)
+}
+
+// consolidate links roots of equal degree until every degree occurs at most
+// once, then rebuilds the root list and finds the new minimum.
+func (h *fibonacciHeap[T]) consolidate() {
+	// A tree of degree k holds at least F(k+2) >= phi^k nodes, so the degree
+	// is below log_phi(n) < 2*log2(n).
+	byDegree := make([]*node[T], 2*bits.Len(uint(h.len))+2)
+	for current := h.root; current != nil; {
+		next := current.right
+		current.prev, current.right = nil, nil
+		for byDegree[current.rank] != nil {
+			other := byDegree[current.rank]
+			byDegree[current.rank] = nil
+			if h.less(other, current) {
+				current, other = other, current
+			}
+			other.parent = current
+			other.right = current.left
+			if current.left != nil {
+				current.left.prev = other
+			}
+			current.left = other
+			other.marked = false
+			current.rank++
+		}
+		byDegree[current.rank] = current
+		current = next
+	}
+	h.root, h.tail, h.min = nil, nil, nil
+	for _, n := range byDegree {
+		if n != nil {
+			h.addRoot(n)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package mergeableheap
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// leftistHeap is a binary tree in which every left child has a null path
+// length, stored in rank, at least that of its sibling. The right spine is
+// therefore O(log n) long, and melding walks only right spines.
+type leftistHeap[T any] struct {
+	base[T]
+}
+
+var _ collection.MergeableHeap[int] = (*leftistHeap[int])(nil)
+
+// NewLeftist returns an empty leftist heap ordered by fn.
+func NewLeftist[T any](fn func(a, b T) compare.Order) *leftistHeap[T] {
+	return &leftistHeap[T]{
+		base: newBase(fn),
+	}
+}
+
+func (h *leftistHeap[T]) Push(element T) {
+	h.Insert(element)
+}
+
+func (h *leftistHeap[T]) Insert(element T) collection.HeapNode[T] {
+	n := h.newNode(element)
+	n.rank = 1
+	h.root = h.meld(h.root, n)
+	return n.item
+}
+
+func (h *leftistHeap[T]) Pop() Option[T] {
+	if h.root == nil {
+		return None[T]()
+	}
+	removed := h.root
+	h.root = h.meld(detach(removed.left), detach(removed.right))
+	return Some(h.release(removed))
+}
+
+func (h *leftistHeap[T]) Peek() Option[T] {
+	if h.root == nil {
+		return None[T]()
+	}
+	return Some(h.root.item.value)
+}
+
+// DecreaseKey cuts the node's subtree out, repairs the ranks above the cut
+// and melds the subtree back in at the root, in O(log n).
+func (h *leftistHeap[T]) DecreaseKey(handle collection.HeapNode[T], element T) bool {
+	found := h.lower(handle, element)
+	if found.IsNone() {
+		return false
+	}
+	n := found.Unwrap()
+	parent := n.parent
+	if parent == nil || !h.less(n, parent) {
+		return true
+	}
+	cut(n)
+	for ; parent != nil; parent = parent.parent {
+		if rank(parent.left) < rank(parent.right) {
+			parent.left, parent.right = parent.right, parent.left
+		}
+		updated := rank(parent.right) + 1
+		if updated == parent.rank {
+			break
+		}
+		parent.rank = updated
+	}
+	h.root = h.meld(h.root, n)
+	return true
+}
+
+func (h *leftistHeap[T]) Meld(other collection.MergeableHeap[T]) {
+	if other, ok := other.(*leftistHeap[T]); ok {
+		if other != h {
+			h.root = h.meld(h.root, other.root)
+			h.absorb(&other.base)
+		}
+		return
+	}
+	meldAny(h, other)
+}
+
+func (h *leftistHeap[T]) meld(a, b *node[T]) *node[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b, a) {
+		a, b = b, a
+	}
+	a.right = h.meld(a.right, b)
+	a.right.parent = a
+	if rank(a.left) < rank(a.right) {
+		a.left, a.right = a.right, a.left
+	}
+	a.rank = rank(a.right) + 1
+	return a
+}
+
+func rank[T any](n *node[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.rank
+}
+
+// detach clears the parent link of n, if any, and returns it.
+func detach[T any](n *node[T]) *node[T] {
+	if n != nil {
+		n.parent = nil
+	}
+	return n
+}
+
+// cut removes n, together with its subtree, from its parent in a binary
+// tree.
+func cut[T any](n *node[T]) {
+	if n.parent.left == n {
+		n.parent.left = nil
+	} else {
+		n.parent.right = nil
+	}
+	n.parent = nil
+}
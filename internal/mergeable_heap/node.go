@@ -0,0 +1,67 @@
+package mergeableheap
+
+import "codeberg.org/yaadata/bina/core/collection"
+
+// node is a tree node shared by every heap in this package. All of them keep
+// each node reachable from the root through left and right, but they give
+// the links different meanings:
+//
+//   - leftist and skew heaps: left and right are the two children and
+//     parent is the parent.
+//   - pairing heaps: left is the first child, right is the next sibling and
+//     parent is the previous sibling, or the parent for a first child.
+//   - binomial heaps: left is the first child, right is the next sibling or
+//     the next root, parent is the parent and rank is the degree.
+//   - Fibonacci heaps: as binomial heaps, with prev linking back to the
+//     previous sibling or root and marked recording that the node has lost
+//     a child since it last became a child itself.
+type node[T any] struct {
+	item   *item[T]
+	parent *node[T]
+	left   *node[T]
+	right  *node[T]
+	prev   *node[T]
+	rank   int
+	marked bool
+}
+
+// item is the handle returned by Insert. Binomial heaps move items between
+// nodes when an element rises, so the handle cannot be the node itself.
+type item[T any] struct {
+	value T
+	node  *node[T]
+	owner *owner
+}
+
+var _ collection.HeapNode[int] = (*item[int])(nil)
+
+func (i *item[T]) Value() T {
+	return i.value
+}
+
+// cell lets a node and its item share one allocation.
+type cell[T any] struct {
+	node node[T]
+	item item[T]
+}
+
+// owner identifies the heap an item belongs to. Melding forwards the owner of
+// the absorbed heap to the owner of the receiving one, so handles follow
+// their elements without being touched.
+type owner struct {
+	next *owner
+}
+
+// find returns the current owner, compressing the forwarding chain.
+func (o *owner) find() *owner {
+	root := o
+	for root.next != nil {
+		root = root.next
+	}
+	for o != root {
+		next := o.next
+		o.next = root
+		o = next
+	}
+	return root
+}
@@ -0,0 +1,127 @@
+package mergeableheap
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// pairingHeap is a multiway tree whose children are kept in a sibling list.
+// Insert and Meld link two roots in O(1); Pop pairs up the children of the
+// root left to right and then melds the pairs right to left, in amortized
+// O(log n).
+type pairingHeap[T any] struct {
+	base[T]
+}
+
+var _ collection.MergeableHeap[int] = (*pairingHeap[int])(nil)
+
+// NewPairing returns an empty pairing heap ordered by fn.
+func NewPairing[T any](fn func(a, b T) compare.Order) *pairingHeap[T] {
+	return &pairingHeap[T]{
+		base: newBase(fn),
+	}
+}
+
+func (h *pairingHeap[T]) Push(element T) {
+	h.Insert(element)
+}
+
+func (h *pairingHeap[T]) Insert(element T) collection.HeapNode[T] {
+	n := h.newNode(element)
+	h.root = h.meld(h.root, n)
+	return n.item
+}
+
+func (h *pairingHeap[T]) Pop() Option[T] {
+	if h.root == nil {
+		return None[T]()
+	}
+	removed := h.root
+	h.root = h.combine(removed.left)
+	return Some(h.release(removed))
+}
+
+func (h *pairingHeap[T]) Peek() Option[T] {
+	if h.root == nil {
+		return None[T]()
+	}
+	return Some(h.root.item.value)
+}
+
+// DecreaseKey unlinks the node's subtree from its sibling list and melds it
+// with the root.
+func (h *pairingHeap[T]) DecreaseKey(handle collection.HeapNode[T], element T) bool {
+	found := h.lower(handle, element)
+	if found.IsNone() {
+		return false
+	}
+	n := found.Unwrap()
+	if n == h.root {
+		return true
+	}
+	if n.parent.left == n {
+		n.parent.left = n.right
+	} else {
+		n.parent.right = n.right
+	}
+	if n.right != nil {
+		n.right.parent = n.parent
+	}
+	n.parent, n.right = nil, nil
+	h.root = h.meld(h.root, n)
+	return true
+}
+
+func (h *pairingHeap[T]) Meld(other collection.MergeableHeap[T]) {
+	if other, ok := other.(*pairingHeap[T]); ok {
+		if other != h {
+			h.root = h.meld(h.root, other.root)
+			h.absorb(&other.base)
+		}
+		return
+	}
+	meldAny(h, other)
+}
+
+// meld links two roots, making the later one the first child of the other.
+func (h *pairingHeap[T]) meld(a, b *node[T]) *node[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b, a) {
+		a, b = b, a
+	}
+	b.parent = a
+	b.right = a.left
+	if a.left != nil {
+		a.left.parent = b
+	}
+	a.left = b
+	return a
+}
+
+// combine melds a sibling list into a single tree with the two-pass
+// strategy.
+func (h *pairingHeap[T]) combine(first *node[T]) *node[T] {
+	var pairs []*node[T]
+	for current := first; current != nil; {
+		a, b := current, current.right
+		current = nil
+		if b != nil {
+			current = b.right
+			b.parent, b.right = nil, nil
+		}
+		a.parent, a.right = nil, nil
+		pairs = append(pairs, h.meld(a, b))
+	}
+	var res *node[T]
+	for i := len(pairs) - 1; i >= 0; i-- {
+		res = h.meld(pairs[i], res)
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+package mergeableheap
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// skewHeap is a leftist heap without ranks: melding swaps the children of
+// every node on the merge path unconditionally, which keeps right spines
+// short in the amortized sense.
+type skewHeap[T any] struct {
+	base[T]
+}
+
+var _ collection.MergeableHeap[int] = (*skewHeap[int])(nil)
+
+// NewSkew returns an empty skew heap ordered by fn.
+func NewSkew[T any](fn func(a, b T) compare.Order) *skewHeap[T] {
+	return &skewHeap[T]{
+		base: newBase(fn),
+	}
+}
+
+func (h *skewHeap[T]) Push(element T) {
+	h.Insert(element)
+}
+
+func (h *skewHeap[T]) Insert(element T) collection.HeapNode[T] {
+	n := h.newNode(element)
+	h.root = h.meld(h.root, n)
+	return n.item
+}
+
+func (h *skewHeap[T]) Pop() Option[T] {
+	if h.root == nil {
+		return None[T]()
+	}
+	removed := h.root
+	h.root = h.meld(detach(removed.left), detach(removed.right))
+	return Some(h.release(removed))
+}
+
+func (h *skewHeap[T]) Peek() Option[T] {
+	if h.root == nil {
+		return None[T]()
+	}
+	return Some(h.root.item.value)
+}
+
+// DecreaseKey cuts the node's subtree out and melds it back in at the root,
+// in amortized O(log n).
+func (h *skewHeap[T]) DecreaseKey(handle collection.HeapNode[T], element T) bool {
+	found := h.lower(handle, element)
+	if found.IsNone() {
+		return false
+	}
+	n := found.Unwrap()
+	if n.parent == nil || !h.less(n, n.parent) {
+		return true
+	}
+	cut(n)
+	h.root = h.meld(h.root, n)
+	return true
+}
+
+func (h *skewHeap[T]) Meld(other collection.MergeableHeap[T]) {
+	if other, ok := other.(*skewHeap[T]); ok {
+		if other != h {
+			h.root = h.meld(h.root, other.root)
+			h.absorb(&other.base)
+		}
+		return
+	}
+	meldAny(h, other)
+}
+
+func (h *skewHeap[T]) meld(a, b *node[T]) *node[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b, a) {
+		a, b = b, a
+	}
+	merged := h.meld(a.right, b)
+	merged.parent = a
+	a.left, a.right = merged, a.left
+	return a
+}
@@ -0,0 +1,5 @@
+// Package merkle implements [collection.MerkleTree] and the proof
+// verification described in RFC 6962.
+package merkle
+
+import _ "codeberg.org/yaadata/bina/core/collection"
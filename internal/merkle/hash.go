@@ -0,0 +1,39 @@
+package merkle
+
+import (
+	"hash"
+	"math/bits"
+)
+
+// Domain separation prefixes from RFC 6962, which stop a leaf from being
+// passed off as an inner node.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+func leafHash(newHash func() hash.Hash, data []byte) []byte {
+	h := newHash()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func nodeHash(newHash func() hash.Hash, left, right []byte) []byte {
+	h := newHash()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+func emptyHash(newHash func() hash.Hash) []byte {
+	return newHash().Sum(nil)
+}
+
+// split returns the largest power of two smaller than n, for n > 1. The
+// leaves of a tree of size n are divided into a complete left subtree of
+// this size and a right subtree holding the rest.
+func split(n int) int {
+	return 1 << (bits.Len(uint(n-1)) - 1)
+}
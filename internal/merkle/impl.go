@@ -0,0 +1,183 @@
+package merkle
+
+import (
+	"bytes"
+	"hash"
+	"math/bits"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/interval"
+)
+
+// merkleTree stores the hash of every complete subtree: levels[h][i] covers
+// the leaves [i*2^h, (i+1)*2^h). Any other subtree hash is derived from these
+// in O(log n).
+type merkleTree struct {
+	newHash func() hash.Hash
+	levels  [][][]byte
+}
+
+var _ collection.MerkleTree = (*merkleTree)(nil)
+
+// New returns a Merkle tree over leaves hashed with hashes from newHash.
+func New(newHash func() hash.Hash, leaves [][]byte) *merkleTree {
+	t := &merkleTree{
+		newHash: newHash,
+		levels:  [][][]byte{nil},
+	}
+	for _, leaf := range leaves {
+		t.Append(leaf)
+	}
+	return t
+}
+
+func (t *merkleTree) Len() int {
+	return len(t.levels[0])
+}
+
+func (t *merkleTree) Append(data []byte) {
+	t.levels[0] = append(t.levels[0], leafHash(t.newHash, data))
+	// Every pair completed at one level completes a subtree on the next
+	for level := 0; len(t.levels[level])%2 == 0; level++ {
+		if level+1 == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+		hashes := t.levels[level]
+		t.levels[level+1] = append(t.levels[level+1], nodeHash(t.newHash, hashes[len(hashes)-2], hashes[len(hashes)-1]))
+	}
+}
+
+func (t *merkleTree) Root() []byte {
+	return slices.Clone(t.subtree(0, t.Len()))
+}
+
+func (t *merkleTree) RootAt(size int) Option[[]byte] {
+	if size < 0 || size > t.Len() {
+		return None[[]byte]()
+	}
+	return Some(slices.Clone(t.subtree(0, size)))
+}
+
+func (t *merkleTree) LeafHash(index int) Option[[]byte] {
+	if index < 0 || index >= t.Len() {
+		return None[[]byte]()
+	}
+	return Some(slices.Clone(t.levels[0][index]))
+}
+
+func (t *merkleTree) Proof(index int) Option[[][]byte] {
+	if index < 0 || index >= t.Len() {
+		return None[[][]byte]()
+	}
+	return Some(cloneAll(t.path(index, 0, t.Len())))
+}
+
+func (t *merkleTree) ConsistencyProof(size int) Option[[][]byte] {
+	if size < 1 || size > t.Len() {
+		return None[[][]byte]()
+	}
+	return Some(cloneAll(t.subproof(size, 0, t.Len(), true)))
+}
+
+// Diff compares subtree hashes top down and only descends into subtrees
+// that differ, which takes O(d log n) for d differing leaves when other is
+// a tree from this package.
+func (t *merkleTree) Diff(other collection.MerkleTree) []interval.Interval[int] {
+	var hashes func(lo, hi int) []byte
+	if other, ok := other.(*merkleTree); ok {
+		hashes = other.subtree
+	} else {
+		hashes = func(lo, hi int) []byte {
+			if hi-lo == 1 {
+				return other.LeafHash(lo).Unwrap()
+			}
+			k := split(hi - lo)
+			return nodeHash(t.newHash, hashes(lo, lo+k), hashes(lo+k, hi))
+		}
+	}
+	shared := min(t.Len(), other.Len())
+	var res []interval.Interval[int]
+	mark := func(lo, hi int) {
+		// Extend the previous range when the two are adjacent
+		if len(res) > 0 && res[len(res)-1].End().Key() == lo {
+			lo = res[len(res)-1].Start().Key()
+			res = res[:len(res)-1]
+		}
+		res = append(res, interval.New(lo, hi))
+	}
+	var walk func(lo, hi int)
+	walk = func(lo, hi int) {
+		switch {
+		case lo >= shared:
+			mark(lo, hi)
+		case hi <= shared && bytes.Equal(t.subtree(lo, hi), hashes(lo, hi)):
+		case hi-lo == 1:
+			mark(lo, hi)
+		default:
+			k := split(hi - lo)
+			walk(lo, lo+k)
+			walk(lo+k, hi)
+		}
+	}
+	if size := max(t.Len(), other.Len()); size > 0 {
+		walk(0, size)
+	}
+	return res
+}
+
+// subtree returns the hash of the leaves [lo, hi), where lo is aligned to
+// the subtree the range belongs to. Complete subtrees return the stored hash
+// itself, so callers outside the tree must clone it.
+func (t *merkleTree) subtree(lo, hi int) []byte {
+	n := hi - lo
+	if n == 0 {
+		return emptyHash(t.newHash)
+	}
+	if n&(n-1) == 0 && lo%n == 0 {
+		return t.levels[bits.TrailingZeros(uint(n))][lo/n]
+	}
+	k := split(n)
+	return nodeHash(t.newHash, t.subtree(lo, lo+k), t.subtree(lo+k, hi))
+}
+
+// path returns the audit path of leaf m within the leaves [lo, hi), listing
+// the sibling subtree hashes from the leaf upwards.
+func (t *merkleTree) path(m, lo, hi int) [][]byte {
+	if hi-lo <= 1 {
+		return nil
+	}
+	k := split(hi - lo)
+	if m < lo+k {
+		return append(t.path(m, lo, lo+k), t.subtree(lo+k, hi))
+	}
+	return append(t.path(m, lo+k, hi), t.subtree(lo, lo+k))
+}
+
+// subproof returns the consistency proof that the first m leaves of
+// [lo, hi) form a prefix of it. complete reports whether those m leaves form
+// the whole of the older tree, whose root the verifier already holds.
+func (t *merkleTree) subproof(m, lo, hi int, complete bool) [][]byte {
+	if lo+m == hi {
+		if complete {
+			return nil
+		}
+		return [][]byte{t.subtree(lo, hi)}
+	}
+	k := split(hi - lo)
+	if m <= k {
+		return append(t.subproof(m, lo, lo+k, complete), t.subtree(lo+k, hi))
+	}
+	return append(t.subproof(m-k, lo+k, hi, false), t.subtree(lo, lo+k))
+}
+
+// cloneAll returns copies of hashes, so that callers cannot reach the stored
+// subtree hashes.
+func cloneAll(hashes [][]byte) [][]byte {
+	for i, h := range hashes {
+		hashes[i] = slices.Clone(h)
+	}
+	return hashes
+}
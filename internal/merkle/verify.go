@@ -0,0 +1,77 @@
+package merkle
+
+import (
+	"bytes"
+	"hash"
+)
+
+// VerifyInclusion reports whether proof shows that data is the leaf at index
+// of a tree with size leaves and the given root, following RFC 9162 section
+// 2.1.3.2.
+func VerifyInclusion(newHash func() hash.Hash, root, data []byte, index, size int, proof [][]byte) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+	fn, sn := index, size-1
+	r := leafHash(newHash, data)
+	for _, p := range proof {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(newHash, p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(newHash, r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(r, root)
+}
+
+// VerifyConsistency reports whether proof shows that the tree with oldSize
+// leaves and oldRoot is a prefix of the tree with newSize leaves and
+// newRoot, following RFC 9162 section 2.1.4.2.
+func VerifyConsistency(newHash func() hash.Hash, oldSize, newSize int, oldRoot, newRoot []byte, proof [][]byte) bool {
+	switch {
+	case oldSize < 1 || oldSize > newSize:
+		return false
+	case oldSize == newSize:
+		return len(proof) == 0 && bytes.Equal(oldRoot, newRoot)
+	case len(proof) == 0:
+		return false
+	}
+	// A complete older tree is a subtree of the newer one, so the proof
+	// omits its root
+	if oldSize&(oldSize-1) == 0 {
+		proof = append([][]byte{oldRoot}, proof...)
+	}
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(newHash, c, fr)
+			sr = nodeHash(newHash, c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(newHash, sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(fr, oldRoot) && bytes.Equal(sr, newRoot)
+}
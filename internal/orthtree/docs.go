@@ -0,0 +1,5 @@
+// Package orthtree implements [collection.QuadTree] and [collection.Octree]
+// with a single tree that is generic over the number of dimensions.
+package orthtree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,83 @@
+package orthtree
+
+import (
+	"math"
+
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// vec holds the coordinates of a point in up to three dimensions. Unused
+// coordinates are zero.
+type vec [3]float64
+
+// box is an axis-aligned region inclusive of its faces.
+type box struct {
+	min vec
+	max vec
+}
+
+func (b box) contains(v vec) bool {
+	for axis := range v {
+		if v[axis] < b.min[axis] || v[axis] > b.max[axis] {
+			return false
+		}
+	}
+	return true
+}
+
+func (b box) intersects(other box) bool {
+	for axis := range b.min {
+		if b.min[axis] > other.max[axis] || other.min[axis] > b.max[axis] {
+			return false
+		}
+	}
+	return true
+}
+
+// distance returns the Euclidean distance from v to the nearest point of b.
+func (b box) distance(v vec) float64 {
+	var sum float64
+	for axis := range v {
+		d := max(b.min[axis]-v[axis], 0, v[axis]-b.max[axis])
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+func (b box) center() vec {
+	var res vec
+	for axis := range res {
+		res[axis] = (b.min[axis] + b.max[axis]) / 2
+	}
+	return res
+}
+
+func pointToVec(p spatial.Point) vec {
+	return vec{p.X, p.Y}
+}
+
+func rectToBox(r spatial.Rect) box {
+	return box{min: pointToVec(r.Min), max: pointToVec(r.Max)}
+}
+
+func boxToRect(b box) spatial.Rect {
+	return spatial.Rect{
+		Min: spatial.Point{X: b.min[0], Y: b.min[1]},
+		Max: spatial.Point{X: b.max[0], Y: b.max[1]},
+	}
+}
+
+func point3ToVec(p spatial.Point3) vec {
+	return vec{p.X, p.Y, p.Z}
+}
+
+func boxToVecBox(b spatial.Box) box {
+	return box{min: point3ToVec(b.Min), max: point3ToVec(b.Max)}
+}
+
+func vecBoxToBox(b box) spatial.Box {
+	return spatial.Box{
+		Min: spatial.Point3{X: b.min[0], Y: b.min[1], Z: b.min[2]},
+		Max: spatial.Point3{X: b.max[0], Y: b.max[1], Z: b.max[2]},
+	}
+}
@@ -0,0 +1,376 @@
+package orthtree
+
+import (
+	"container/heap"
+	"iter"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// orthtree stores entries in leaf cells. A leaf holding more than capacity
+// entries splits into 2^dims children around its center unless it already
+// sits at maxDepth. A point on a splitting plane belongs to the upper child.
+type orthtree[P any, R any, V any] struct {
+	dims     int
+	toVec    func(P) vec
+	toBox    func(R) box
+	fromBox  func(box) R
+	root     *node[P, V]
+	capacity int
+	maxDepth int
+	len      int
+}
+
+type entry[P any, V any] struct {
+	point P
+	at    vec
+	value V
+}
+
+type node[P any, V any] struct {
+	region   box
+	depth    int
+	entries  []entry[P, V]
+	children []*node[P, V]
+}
+
+var (
+	_ collection.QuadTree[int] = (*orthtree[spatial.Point, spatial.Rect, int])(nil)
+	_ collection.Octree[int]   = (*orthtree[spatial.Point3, spatial.Box, int])(nil)
+)
+
+// NewQuadTree returns an empty quad tree covering bounds whose leaves split
+// once they hold more than capacity points, down to maxDepth levels.
+func NewQuadTree[V any](bounds spatial.Rect, capacity, maxDepth int) *orthtree[spatial.Point, spatial.Rect, V] {
+	return newOrthtree[spatial.Point, spatial.Rect, V](2, pointToVec, rectToBox, boxToRect, bounds, capacity, maxDepth)
+}
+
+// NewOctree returns an empty octree covering bounds whose leaves split once
+// they hold more than capacity points, down to maxDepth levels.
+func NewOctree[V any](bounds spatial.Box, capacity, maxDepth int) *orthtree[spatial.Point3, spatial.Box, V] {
+	return newOrthtree[spatial.Point3, spatial.Box, V](3, point3ToVec, boxToVecBox, vecBoxToBox, bounds, capacity, maxDepth)
+}
+
+func newOrthtree[P any, R any, V any](
+	dims int,
+	toVec func(P) vec,
+	toBox func(R) box,
+	fromBox func(box) R,
+	bounds R,
+	capacity int,
+	maxDepth int,
+) *orthtree[P, R, V] {
+	return &orthtree[P, R, V]{
+		dims:     dims,
+		toVec:    toVec,
+		toBox:    toBox,
+		fromBox:  fromBox,
+		root:     &node[P, V]{region: toBox(bounds)},
+		capacity: max(capacity, 1),
+		maxDepth: max(maxDepth, 0),
+	}
+}
+
+func (t *orthtree[P, R, V]) Len() int {
+	return t.len
+}
+
+func (t *orthtree[P, R, V]) Contains(element P) bool {
+	at := t.toVec(element)
+	leaf := t.leaf(at)
+	return leaf != nil && slices.ContainsFunc(leaf.entries, func(e entry[P, V]) bool {
+		return e.at == at
+	})
+}
+
+func (t *orthtree[P, R, V]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *orthtree[P, R, V]) Clear() {
+	t.root = &node[P, V]{region: t.root.region}
+	t.len = 0
+}
+
+func (t *orthtree[P, R, V]) Any(pred predicate.Predicate[kv.Pair[P, V]]) bool {
+	for point, value := range t.All() {
+		if pred(kv.New(point, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *orthtree[P, R, V]) Count(pred predicate.Predicate[kv.Pair[P, V]]) int {
+	var count int
+	for point, value := range t.All() {
+		if pred(kv.New(point, value)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *orthtree[P, R, V]) Every(pred predicate.Predicate[kv.Pair[P, V]]) bool {
+	for point, value := range t.All() {
+		if !pred(kv.New(point, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *orthtree[P, R, V]) ForEach(fn func(pair kv.Pair[P, V])) {
+	for point, value := range t.All() {
+		fn(kv.New(point, value))
+	}
+}
+
+func (t *orthtree[P, R, V]) Bounds() R {
+	return t.fromBox(t.root.region)
+}
+
+func (t *orthtree[P, R, V]) Insert(point P, value V) bool {
+	at := t.toVec(point)
+	if !t.root.region.contains(at) {
+		return false
+	}
+	t.insert(t.leaf(at), entry[P, V]{point: point, at: at, value: value})
+	t.len++
+	return true
+}
+
+func (t *orthtree[P, R, V]) Remove(point P) Option[V] {
+	return t.RemoveFunc(point, func(V) bool {
+		return true
+	})
+}
+
+func (t *orthtree[P, R, V]) RemoveFunc(point P, pred predicate.Predicate[V]) Option[V] {
+	at := t.toVec(point)
+	if !t.root.region.contains(at) {
+		return None[V]()
+	}
+	removed := t.remove(t.root, at, pred)
+	if removed.IsSome() {
+		t.len--
+	}
+	return removed
+}
+
+func (t *orthtree[P, R, V]) Query(region R) iter.Seq2[P, V] {
+	query := t.toBox(region)
+	var walk func(n *node[P, V], yield func(P, V) bool) bool
+	walk = func(n *node[P, V], yield func(P, V) bool) bool {
+		for _, e := range n.entries {
+			if query.contains(e.at) && !yield(e.point, e.value) {
+				return false
+			}
+		}
+		for _, child := range n.children {
+			if child.region.intersects(query) && !walk(child, yield) {
+				return false
+			}
+		}
+		return true
+	}
+	return func(yield func(P, V) bool) {
+		walk(t.root, yield)
+	}
+}
+
+// Nearest visits cells and entries best first, ordered by their distance to
+// point, so the first k entries popped are the k nearest.
+func (t *orthtree[P, R, V]) Nearest(point P, k int) []kv.Pair[P, V] {
+	var res []kv.Pair[P, V]
+	if k <= 0 {
+		return res
+	}
+	at := t.toVec(point)
+	queue := &candidates[P, V]{{node: t.root, distance: t.root.region.distance(at)}}
+	for queue.Len() > 0 && len(res) < k {
+		next := heap.Pop(queue).(candidate[P, V])
+		if next.node == nil {
+			res = append(res, kv.New(next.entry.point, next.entry.value))
+			continue
+		}
+		for _, e := range next.node.entries {
+			heap.Push(queue, candidate[P, V]{entry: e, distance: box{min: e.at, max: e.at}.distance(at)})
+		}
+		for _, child := range next.node.children {
+			heap.Push(queue, candidate[P, V]{node: child, distance: child.region.distance(at)})
+		}
+	}
+	return res
+}
+
+func (t *orthtree[P, R, V]) All() iter.Seq2[P, V] {
+	var walk func(n *node[P, V], yield func(P, V) bool) bool
+	walk = func(n *node[P, V], yield func(P, V) bool) bool {
+		for _, e := range n.entries {
+			if !yield(e.point, e.value) {
+				return false
+			}
+		}
+		for _, child := range n.children {
+			if !walk(child, yield) {
+				return false
+			}
+		}
+		return true
+	}
+	return func(yield func(P, V) bool) {
+		walk(t.root, yield)
+	}
+}
+
+func (t *orthtree[P, R, V]) Leaves() iter.Seq2[R, int] {
+	var walk func(n *node[P, V], yield func(R, int) bool) bool
+	walk = func(n *node[P, V], yield func(R, int) bool) bool {
+		if n.children == nil {
+			return yield(t.fromBox(n.region), len(n.entries))
+		}
+		for _, child := range n.children {
+			if !walk(child, yield) {
+				return false
+			}
+		}
+		return true
+	}
+	return func(yield func(R, int) bool) {
+		walk(t.root, yield)
+	}
+}
+
+// leaf returns the leaf cell responsible for at, or nil if at lies outside
+// the tree.
+func (t *orthtree[P, R, V]) leaf(at vec) *node[P, V] {
+	if !t.root.region.contains(at) {
+		return nil
+	}
+	n := t.root
+	for n.children != nil {
+		n = n.children[t.orthant(n, at)]
+	}
+	return n
+}
+
+// orthant returns the index of the child of n responsible for at. Bit i of
+// the index is set when at lies in the upper half along axis i.
+func (t *orthtree[P, R, V]) orthant(n *node[P, V], at vec) int {
+	center := n.region.center()
+	var index int
+	for axis := range t.dims {
+		if at[axis] >= center[axis] {
+			index |= 1 << axis
+		}
+	}
+	return index
+}
+
+func (t *orthtree[P, R, V]) insert(leaf *node[P, V], e entry[P, V]) {
+	leaf.entries = append(leaf.entries, e)
+	if len(leaf.entries) <= t.capacity || leaf.depth >= t.maxDepth {
+		return
+	}
+	t.subdivide(leaf)
+	for _, e := range leaf.entries {
+		t.insert(leaf.children[t.orthant(leaf, e.at)], e)
+	}
+	leaf.entries = nil
+}
+
+// subdivide gives n one child per orthant.
+func (t *orthtree[P, R, V]) subdivide(n *node[P, V]) {
+	center := n.region.center()
+	n.children = make([]*node[P, V], 1<<t.dims)
+	for index := range n.children {
+		region := n.region
+		for axis := range t.dims {
+			if index&(1<<axis) != 0 {
+				region.min[axis] = center[axis]
+			} else {
+				region.max[axis] = center[axis]
+			}
+		}
+		n.children[index] = &node[P, V]{region: region, depth: n.depth + 1}
+	}
+}
+
+// remove deletes a matching entry below n and merges the children of every
+// cell on the way back up that no longer holds more than capacity entries.
+func (t *orthtree[P, R, V]) remove(n *node[P, V], at vec, pred predicate.Predicate[V]) Option[V] {
+	if n.children == nil {
+		index := slices.IndexFunc(n.entries, func(e entry[P, V]) bool {
+			return e.at == at && pred(e.value)
+		})
+		if index < 0 {
+			return None[V]()
+		}
+		removed := n.entries[index].value
+		n.entries = slices.Delete(n.entries, index, index+1)
+		return Some(removed)
+	}
+	removed := t.remove(n.children[t.orthant(n, at)], at, pred)
+	if removed.IsSome() {
+		t.merge(n)
+	}
+	return removed
+}
+
+// merge folds the children of n back into n when they are all leaves that
+// together hold no more than capacity entries.
+func (t *orthtree[P, R, V]) merge(n *node[P, V]) {
+	var entries []entry[P, V]
+	for _, child := range n.children {
+		if child.children != nil {
+			return
+		}
+		entries = append(entries, child.entries...)
+	}
+	if len(entries) > t.capacity {
+		return
+	}
+	n.entries = entries
+	n.children = nil
+}
+
+// candidate is a cell or, when node is nil, an entry waiting to be visited
+// by Nearest.
+type candidate[P any, V any] struct {
+	node     *node[P, V]
+	entry    entry[P, V]
+	distance float64
+}
+
+// candidates is a [heap.Interface] ordering candidates by distance.
+type candidates[P any, V any] []candidate[P, V]
+
+func (c candidates[P, V]) Len() int {
+	return len(c)
+}
+
+func (c candidates[P, V]) Less(i, j int) bool {
+	return c[i].distance < c[j].distance
+}
+
+func (c candidates[P, V]) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+func (c *candidates[P, V]) Push(x any) {
+	*c = append(*c, x.(candidate[P, V]))
+}
+
+func (c *candidates[P, V]) Pop() any {
+	old := *c
+	last := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,423 @@
+package redblack
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/where"
+	searchtree "codeberg.org/yaadata/bina/internal/search_tree"
+)
+
+type redBlackTree[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	root    *node[K, V]
+	len     int
+}
+
+var _ collection.RedBlackTree[int, int] = (*redBlackTree[int, int])(nil)
+
+// New returns an empty red-black tree whose keys are ordered by fn.
+func New[K any, V any](fn func(a, b K) compare.Order) *redBlackTree[K, V] {
+	return &redBlackTree[K, V]{
+		compare: fn,
+		root:    nil,
+		len:     0,
+	}
+}
+
+func (t *redBlackTree[K, V]) Len() int {
+	return t.len
+}
+
+func (t *redBlackTree[K, V]) Contains(element K) bool {
+	return t.find(element) != nil
+}
+
+func (t *redBlackTree[K, V]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *redBlackTree[K, V]) Clear() {
+	t.root = nil
+	t.len = 0
+}
+
+func (t *redBlackTree[K, V]) Any(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *redBlackTree[K, V]) Every(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if !pred(kv.New(key, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *redBlackTree[K, V]) Count(pred predicate.Predicate[kv.Pair[K, V]]) int {
+	var count int
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *redBlackTree[K, V]) ForEach(fn func(pair kv.Pair[K, V])) {
+	for key, value := range t.All() {
+		fn(kv.New(key, value))
+	}
+}
+
+func (t *redBlackTree[K, V]) Delete(key K) Option[V] {
+	n := t.find(key)
+	if n == nil {
+		return None[V]()
+	}
+	value := n.value
+	t.remove(n)
+	t.len--
+	return Some(value)
+}
+
+func (t *redBlackTree[K, V]) Get(key K) Option[V] {
+	n := t.find(key)
+	if n == nil {
+		return None[V]()
+	}
+	return Some(n.value)
+}
+
+func (t *redBlackTree[K, V]) Put(key K, value V) {
+	var parent *node[K, V]
+	var order compare.Order
+	current := t.root
+	for current != nil {
+		parent = current
+		order = t.compare(key, current.key)
+		switch {
+		case order.IsLess():
+			current = current.left
+		case order.IsGreater():
+			current = current.right
+		default:
+			current.value = value
+			return
+		}
+	}
+	n := &node[K, V]{
+		key:    key,
+		value:  value,
+		color:  red,
+		parent: parent,
+	}
+	switch {
+	case parent == nil:
+		t.root = n
+	case order.IsLess():
+		parent.left = n
+	default:
+		parent.right = n
+	}
+	t.len++
+	t.insertFixup(n)
+}
+
+// Height returns the number of nodes on the longest root-to-leaf path.
+// It walks the whole tree, so it runs in O(n).
+func (t *redBlackTree[K, V]) Height() int {
+	return height(t.root)
+}
+
+func (t *redBlackTree[K, V]) Min() Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	n := minimum(t.root)
+	return Some(kv.New(n.key, n.value))
+}
+
+func (t *redBlackTree[K, V]) Max() Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	n := maximum(t.root)
+	return Some(kv.New(n.key, n.value))
+}
+
+func (t *redBlackTree[K, V]) Floor(key K) Option[kv.Pair[K, V]] {
+	var candidate *node[K, V]
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsEqual():
+			return Some(kv.New(current.key, current.value))
+		case order.IsLess():
+			current = current.left
+		default:
+			candidate = current
+			current = current.right
+		}
+	}
+	if candidate == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(candidate.key, candidate.value))
+}
+
+func (t *redBlackTree[K, V]) Ceiling(key K) Option[kv.Pair[K, V]] {
+	var candidate *node[K, V]
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsEqual():
+			return Some(kv.New(current.key, current.value))
+		case order.IsGreater():
+			current = current.right
+		default:
+			candidate = current
+			current = current.left
+		}
+	}
+	if candidate == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(candidate.key, candidate.value))
+}
+
+func (t *redBlackTree[K, V]) Range(opts ...where.WhereOption[K]) iter.Seq2[K, V] {
+	return t.walker().Range(t.compare, opts...)
+}
+
+func (t *redBlackTree[K, V]) All(opts ...collection.SearchTreeTraversalOption) iter.Seq2[K, V] {
+	return t.walker().All(opts...)
+}
+
+// walker describes the nodes of the tree to the shared traversals.
+func (t *redBlackTree[K, V]) walker() searchtree.Walker[K, V, *node[K, V]] {
+	return searchtree.Walker[K, V, *node[K, V]]{
+		Root: func() *node[K, V] {
+			return t.root
+		},
+		Left: func(n *node[K, V]) *node[K, V] {
+			return n.left
+		},
+		Right: func(n *node[K, V]) *node[K, V] {
+			return n.right
+		},
+		Entry: func(n *node[K, V]) (K, V) {
+			return n.key, n.value
+		},
+	}
+}
+
+func (t *redBlackTree[K, V]) find(key K) *node[K, V] {
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsLess():
+			current = current.left
+		case order.IsGreater():
+			current = current.right
+		default:
+			return current
+		}
+	}
+	return nil
+}
+
+// insertFixup restores the red-black properties after n was attached as a red leaf.
+func (t *redBlackTree[K, V]) insertFixup(n *node[K, V]) {
+	for isRed(n.parent) {
+		// A red parent is never the root, so the grandparent exists
+		parent := n.parent
+		grandparent := parent.parent
+		if parent == grandparent.left {
+			uncle := grandparent.right
+			if isRed(uncle) {
+				parent.color = black
+				uncle.color = black
+				grandparent.color = red
+				n = grandparent
+				continue
+			}
+			if n == parent.right {
+				n = parent
+				t.rotateLeft(n)
+				parent = n.parent
+			}
+			parent.color = black
+			grandparent.color = red
+			t.rotateRight(grandparent)
+		} else {
+			uncle := grandparent.left
+			if isRed(uncle) {
+				parent.color = black
+				uncle.color = black
+				grandparent.color = red
+				n = grandparent
+				continue
+			}
+			if n == parent.left {
+				n = parent
+				t.rotateRight(n)
+				parent = n.parent
+			}
+			parent.color = black
+			grandparent.color = red
+			t.rotateLeft(grandparent)
+		}
+	}
+	t.root.color = black
+}
+
+// remove unlinks n from the tree and rebalances if a black node was lost.
+func (t *redBlackTree[K, V]) remove(n *node[K, V]) {
+	removedColor := n.color
+	var child, parent *node[K, V]
+	switch {
+	case n.left == nil:
+		child, parent = n.right, n.parent
+		t.transplant(n, n.right)
+	case n.right == nil:
+		child, parent = n.left, n.parent
+		t.transplant(n, n.left)
+	default:
+		// Replace n with its in-order successor
+		successor := minimum(n.right)
+		removedColor = successor.color
+		child = successor.right
+		if successor.parent == n {
+			parent = successor
+		} else {
+			parent = successor.parent
+			t.transplant(successor, successor.right)
+			successor.right = n.right
+			successor.right.parent = successor
+		}
+		t.transplant(n, successor)
+		successor.left = n.left
+		successor.left.parent = successor
+		successor.color = n.color
+	}
+	if removedColor == black {
+		t.deleteFixup(child, parent)
+	}
+}
+
+// deleteFixup restores the red-black properties after a black node was removed
+// above n. The parent is passed separately because n may be nil.
+func (t *redBlackTree[K, V]) deleteFixup(n *node[K, V], parent *node[K, V]) {
+	for n != t.root && !isRed(n) {
+		if n == parent.left {
+			sibling := parent.right
+			if isRed(sibling) {
+				sibling.color = black
+				parent.color = red
+				t.rotateLeft(parent)
+				sibling = parent.right
+			}
+			if !isRed(sibling.left) && !isRed(sibling.right) {
+				sibling.color = red
+				n, parent = parent, parent.parent
+				continue
+			}
+			if !isRed(sibling.right) {
+				sibling.left.color = black
+				sibling.color = red
+				t.rotateRight(sibling)
+				sibling = parent.right
+			}
+			sibling.color = parent.color
+			parent.color = black
+			sibling.right.color = black
+			t.rotateLeft(parent)
+		} else {
+			sibling := parent.left
+			if isRed(sibling) {
+				sibling.color = black
+				parent.color = red
+				t.rotateRight(parent)
+				sibling = parent.left
+			}
+			if !isRed(sibling.left) && !isRed(sibling.right) {
+				sibling.color = red
+				n, parent = parent, parent.parent
+				continue
+			}
+			if !isRed(sibling.left) {
+				sibling.right.color = black
+				sibling.color = red
+				t.rotateLeft(sibling)
+				sibling = parent.left
+			}
+			sibling.color = parent.color
+			parent.color = black
+			sibling.left.color = black
+			t.rotateRight(parent)
+		}
+		n = t.root
+	}
+	if n != nil {
+		n.color = black
+	}
+}
+
+// transplant replaces the subtree rooted at u with the subtree rooted at v.
+func (t *redBlackTree[K, V]) transplant(u, v *node[K, V]) {
+	switch {
+	case u.parent == nil:
+		t.root = v
+	case u == u.parent.left:
+		u.parent.left = v
+	default:
+		u.parent.right = v
+	}
+	if v != nil {
+		v.parent = u.parent
+	}
+}
+
+func (t *redBlackTree[K, V]) rotateLeft(n *node[K, V]) {
+	pivot := n.right
+	n.right = pivot.left
+	if pivot.left != nil {
+		pivot.left.parent = n
+	}
+	t.transplant(n, pivot)
+	pivot.left = n
+	n.parent = pivot
+}
+
+func (t *redBlackTree[K, V]) rotateRight(n *node[K, V]) {
+	pivot := n.left
+	n.left = pivot.right
+	if pivot.right != nil {
+		pivot.right.parent = n
+	}
+	t.transplant(n, pivot)
+	pivot.right = n
+	n.parent = pivot
+}
+
+func height[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(height(n.left), height(n.right))
+}
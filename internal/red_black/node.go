@@ -0,0 +1,36 @@
+package redblack
+
+type color bool
+
+const (
+	red   color = false
+	black color = true
+)
+
+// node is a red-black tree node. Missing children are nil and count as black.
+type node[K any, V any] struct {
+	key    K
+	value  V
+	color  color
+	left   *node[K, V]
+	right  *node[K, V]
+	parent *node[K, V]
+}
+
+func isRed[K any, V any](n *node[K, V]) bool {
+	return n != nil && n.color == red
+}
+
+func minimum[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func maximum[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package redblack
+
+import (
+	"fmt"
+)
+
+// Validate checks, in order: the root is black, keys are in search order,
+// parent links are consistent, no red node has a red child, every path from a
+// node to its leaves holds the same number of black nodes, and the node count
+// matches Len.
+func (t *redBlackTree[K, V]) Validate() error {
+	if t.root == nil {
+		if t.len != 0 {
+			return fmt.Errorf("red-black: empty tree reports length %d", t.len)
+		}
+		return nil
+	}
+	if t.root.parent != nil {
+		return fmt.Errorf("red-black: root %v has a parent", t.root.key)
+	}
+	if isRed(t.root) {
+		return fmt.Errorf("red-black: root %v is red", t.root.key)
+	}
+	_, size, err := t.validate(t.root, nil, nil)
+	if err != nil {
+		return err
+	}
+	if size != t.len {
+		return fmt.Errorf("red-black: found %d nodes but length is %d", size, t.len)
+	}
+	return nil
+}
+
+// validate checks the subtree rooted at n, whose keys must lie strictly
+// between the keys of lower and upper when those are set. It returns the black
+// height and node count of the subtree.
+func (t *redBlackTree[K, V]) validate(n, lower, upper *node[K, V]) (int, int, error) {
+	if n == nil {
+		return 1, 0, nil
+	}
+	if lower != nil && !t.compare(n.key, lower.key).IsGreater() {
+		return 0, 0, fmt.Errorf("red-black: key %v is not greater than %v", n.key, lower.key)
+	}
+	if upper != nil && !t.compare(n.key, upper.key).IsLess() {
+		return 0, 0, fmt.Errorf("red-black: key %v is not less than %v", n.key, upper.key)
+	}
+	for _, child := range []*node[K, V]{n.left, n.right} {
+		if child == nil {
+			continue
+		}
+		if child.parent != n {
+			return 0, 0, fmt.Errorf("red-black: child %v does not link back to parent %v", child.key, n.key)
+		}
+		if isRed(n) && isRed(child) {
+			return 0, 0, fmt.Errorf("red-black: red node %v has red child %v", n.key, child.key)
+		}
+	}
+	leftHeight, leftSize, err := t.validate(n.left, lower, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	rightHeight, rightSize, err := t.validate(n.right, n, upper)
+	if err != nil {
+		return 0, 0, err
+	}
+	if leftHeight != rightHeight {
+		return 0, 0, fmt.Errorf("red-black: black height differs below %v: %d on the left, %d on the right", n.key, leftHeight, rightHeight)
+	}
+	if n.color == black {
+		leftHeight++
+	}
+	return leftHeight, leftSize + rightSize + 1, nil
+}
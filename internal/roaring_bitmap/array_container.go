@@ -0,0 +1,156 @@
+package roaringbitmap
+
+import (
+	"iter"
+	"slices"
+)
+
+// arrayContainer stores a sorted list of values and is used for sparse chunks.
+type arrayContainer struct {
+	content []uint16
+}
+
+var _ container = (*arrayContainer)(nil)
+
+func newArrayContainer(values ...uint16) *arrayContainer {
+	return &arrayContainer{content: values}
+}
+
+func (a *arrayContainer) add(value uint16) (container, bool) {
+	index, found := slices.BinarySearch(a.content, value)
+	if found {
+		return a, false
+	}
+	if len(a.content) >= arrayMaxSize {
+		res := a.toBitmap()
+		res.set(value)
+		return res, true
+	}
+	a.content = slices.Insert(a.content, index, value)
+	return a, true
+}
+
+func (a *arrayContainer) remove(value uint16) (container, bool) {
+	index, found := slices.BinarySearch(a.content, value)
+	if !found {
+		return a, false
+	}
+	a.content = slices.Delete(a.content, index, index+1)
+	return a, true
+}
+
+func (a *arrayContainer) contains(value uint16) bool {
+	_, found := slices.BinarySearch(a.content, value)
+	return found
+}
+
+func (a *arrayContainer) cardinality() int {
+	return len(a.content)
+}
+
+func (a *arrayContainer) rank(value uint16) int {
+	index, found := slices.BinarySearch(a.content, value)
+	if found {
+		return index + 1
+	}
+	return index
+}
+
+func (a *arrayContainer) selectAt(index int) uint16 {
+	return a.content[index]
+}
+
+func (a *arrayContainer) minimum() uint16 {
+	return a.content[0]
+}
+
+func (a *arrayContainer) maximum() uint16 {
+	return a.content[len(a.content)-1]
+}
+
+func (a *arrayContainer) values() iter.Seq[uint16] {
+	return slices.Values(a.content)
+}
+
+func (a *arrayContainer) clone() container {
+	return newArrayContainer(slices.Clone(a.content)...)
+}
+
+func (a *arrayContainer) toBitmap() *bitmapContainer {
+	res := newBitmapContainer()
+	for _, value := range a.content {
+		res.set(value)
+	}
+	return res
+}
+
+func (a *arrayContainer) runOptimize() container {
+	runs := 0
+	for i, value := range a.content {
+		if i == 0 || a.content[i-1]+1 != value {
+			runs++
+		}
+	}
+	if runContainerSize(runs) >= a.sizeInBytes() {
+		return a
+	}
+	return runContainerFrom(a)
+}
+
+func (a *arrayContainer) sizeInBytes() int {
+	return 2 * len(a.content)
+}
+
+// filter keeps the values whose membership in other matches keep.
+func (a *arrayContainer) filter(other container, keep bool) container {
+	res := make([]uint16, 0, len(a.content))
+	for _, value := range a.content {
+		if other.contains(value) == keep {
+			res = append(res, value)
+		}
+	}
+	return newArrayContainer(res...)
+}
+
+func (a *arrayContainer) union(other *arrayContainer) container {
+	res := make([]uint16, 0, len(a.content)+len(other.content))
+	i, j := 0, 0
+	for i < len(a.content) && j < len(other.content) {
+		switch {
+		case a.content[i] < other.content[j]:
+			res = append(res, a.content[i])
+			i++
+		case a.content[i] > other.content[j]:
+			res = append(res, other.content[j])
+			j++
+		default:
+			res = append(res, a.content[i])
+			i++
+			j++
+		}
+	}
+	res = append(res, a.content[i:]...)
+	res = append(res, other.content[j:]...)
+	return newArrayContainer(res...)
+}
+
+func (a *arrayContainer) symmetricDifference(other *arrayContainer) container {
+	res := make([]uint16, 0, len(a.content)+len(other.content))
+	i, j := 0, 0
+	for i < len(a.content) && j < len(other.content) {
+		switch {
+		case a.content[i] < other.content[j]:
+			res = append(res, a.content[i])
+			i++
+		case a.content[i] > other.content[j]:
+			res = append(res, other.content[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	res = append(res, a.content[i:]...)
+	res = append(res, other.content[j:]...)
+	return newArrayContainer(res...)
+}
@@ -0,0 +1,176 @@
+package roaringbitmap
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// bitmapContainer stores one bit per possible value and is used for dense chunks.
+type bitmapContainer struct {
+	words []uint64
+	card  int
+}
+
+var _ container = (*bitmapContainer)(nil)
+
+func newBitmapContainer() *bitmapContainer {
+	return &bitmapContainer{
+		words: make([]uint64, bitmapWords),
+		card:  0,
+	}
+}
+
+func (b *bitmapContainer) add(value uint16) (container, bool) {
+	if b.contains(value) {
+		return b, false
+	}
+	b.set(value)
+	return b, true
+}
+
+func (b *bitmapContainer) remove(value uint16) (container, bool) {
+	if !b.contains(value) {
+		return b, false
+	}
+	b.words[value/64] &^= 1 << (value % 64)
+	b.card--
+	return b.normalize(), true
+}
+
+func (b *bitmapContainer) contains(value uint16) bool {
+	return b.words[value/64]&(1<<(value%64)) != 0
+}
+
+func (b *bitmapContainer) cardinality() int {
+	return b.card
+}
+
+func (b *bitmapContainer) rank(value uint16) int {
+	var res int
+	word := int(value / 64)
+	for _, w := range b.words[:word] {
+		res += bits.OnesCount64(w)
+	}
+	mask := uint64(1)<<(value%64+1) - 1
+	return res + bits.OnesCount64(b.words[word]&mask)
+}
+
+func (b *bitmapContainer) selectAt(index int) uint16 {
+	for i, w := range b.words {
+		count := bits.OnesCount64(w)
+		if index >= count {
+			index -= count
+			continue
+		}
+		for range index {
+			w &= w - 1
+		}
+		return uint16(i*64 + bits.TrailingZeros64(w))
+	}
+	return 0
+}
+
+func (b *bitmapContainer) minimum() uint16 {
+	for i, w := range b.words {
+		if w != 0 {
+			return uint16(i*64 + bits.TrailingZeros64(w))
+		}
+	}
+	return 0
+}
+
+func (b *bitmapContainer) maximum() uint16 {
+	for i := len(b.words) - 1; i >= 0; i-- {
+		if w := b.words[i]; w != 0 {
+			return uint16(i*64 + 63 - bits.LeadingZeros64(w))
+		}
+	}
+	return 0
+}
+
+func (b *bitmapContainer) values() iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				if !yield(uint16(i*64 + bits.TrailingZeros64(w))) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+func (b *bitmapContainer) clone() container {
+	words := make([]uint64, bitmapWords)
+	copy(words, b.words)
+	return &bitmapContainer{
+		words: words,
+		card:  b.card,
+	}
+}
+
+func (b *bitmapContainer) toBitmap() *bitmapContainer {
+	return b
+}
+
+func (b *bitmapContainer) runOptimize() container {
+	var runs int
+	var carry uint64
+	for _, w := range b.words {
+		runs += bits.OnesCount64(w &^ (w<<1 | carry))
+		carry = w >> 63
+	}
+	if runContainerSize(runs) >= b.sizeInBytes() {
+		return b
+	}
+	return runContainerFrom(b)
+}
+
+func (b *bitmapContainer) sizeInBytes() int {
+	return bitmapSizeInBytes
+}
+
+// set marks value as present without checking whether it already was.
+func (b *bitmapContainer) set(value uint16) {
+	b.words[value/64] |= 1 << (value % 64)
+	b.card++
+}
+
+// setRange marks every value in [start, last] as present.
+func (b *bitmapContainer) setRange(start, last uint16) {
+	for value := int(start); value <= int(last); {
+		word := value / 64
+		offset := value % 64
+		end := min(int(last), word*64+63)
+		width := end - value + 1
+		mask := ^uint64(0)
+		if width < 64 {
+			mask = (uint64(1)<<width - 1) << offset
+		}
+		b.words[word] |= mask
+		value = end + 1
+	}
+	b.recount()
+}
+
+// recount recomputes the cardinality after bulk word operations.
+func (b *bitmapContainer) recount() *bitmapContainer {
+	b.card = 0
+	for _, w := range b.words {
+		b.card += bits.OnesCount64(w)
+	}
+	return b
+}
+
+// normalize converts the container to an array when it is sparse enough.
+func (b *bitmapContainer) normalize() container {
+	if b.card > arrayMaxSize {
+		return b
+	}
+	res := make([]uint16, 0, b.card)
+	for value := range b.values() {
+		res = append(res, value)
+	}
+	return newArrayContainer(res...)
+}
@@ -0,0 +1,119 @@
+package roaringbitmap
+
+import (
+	"iter"
+)
+
+const (
+	// arrayMaxSize is the largest cardinality stored in an array container.
+	arrayMaxSize = 4096
+	// bitmapWords is the number of 64-bit words needed to cover a 16-bit chunk.
+	bitmapWords = 1 << 16 / 64
+	// bitmapSizeInBytes is the serialized size of a bitmap container.
+	bitmapSizeInBytes = bitmapWords * 8
+)
+
+// container stores the low 16 bits of every value sharing the same high 16 bits.
+// Mutating methods return the container that should replace the receiver, which
+// lets a container switch representation as its cardinality changes.
+type container interface {
+	add(value uint16) (container, bool)
+	remove(value uint16) (container, bool)
+	contains(value uint16) bool
+	cardinality() int
+	// rank returns the number of values less than or equal to value.
+	rank(value uint16) int
+	// selectAt returns the value at the given zero-based rank.
+	selectAt(index int) uint16
+	minimum() uint16
+	maximum() uint16
+	values() iter.Seq[uint16]
+	clone() container
+	toBitmap() *bitmapContainer
+	// runOptimize returns the smallest representation of this container.
+	runOptimize() container
+	// sizeInBytes returns the serialized size of the container payload.
+	sizeInBytes() int
+}
+
+// and returns the intersection of two containers.
+func and(a, b container) container {
+	if left, ok := a.(*arrayContainer); ok {
+		return left.filter(b, true)
+	}
+	if right, ok := b.(*arrayContainer); ok {
+		return right.filter(a, true)
+	}
+	left, leftIsRun := a.(*runContainer)
+	right, rightIsRun := b.(*runContainer)
+	if leftIsRun && rightIsRun {
+		return left.intersect(right)
+	}
+	res := a.toBitmap().clone().(*bitmapContainer)
+	other := b.toBitmap()
+	for i := range res.words {
+		res.words[i] &= other.words[i]
+	}
+	return res.recount().normalize()
+}
+
+// or returns the union of two containers.
+func or(a, b container) container {
+	left, leftIsArray := a.(*arrayContainer)
+	right, rightIsArray := b.(*arrayContainer)
+	if leftIsArray && rightIsArray && len(left.content)+len(right.content) <= arrayMaxSize {
+		return left.union(right)
+	}
+	leftRun, leftIsRun := a.(*runContainer)
+	rightRun, rightIsRun := b.(*runContainer)
+	if leftIsRun && rightIsRun {
+		return leftRun.union(rightRun)
+	}
+	res := a.toBitmap().clone().(*bitmapContainer)
+	other := b.toBitmap()
+	for i := range res.words {
+		res.words[i] |= other.words[i]
+	}
+	return res.recount().normalize()
+}
+
+// andNot returns the values of a that are not in b.
+func andNot(a, b container) container {
+	if left, ok := a.(*arrayContainer); ok {
+		return left.filter(b, false)
+	}
+	res := a.toBitmap().clone().(*bitmapContainer)
+	other := b.toBitmap()
+	for i := range res.words {
+		res.words[i] &^= other.words[i]
+	}
+	return res.recount().normalize()
+}
+
+// xor returns the values present in exactly one of the containers.
+func xor(a, b container) container {
+	left, leftIsArray := a.(*arrayContainer)
+	right, rightIsArray := b.(*arrayContainer)
+	if leftIsArray && rightIsArray && len(left.content)+len(right.content) <= arrayMaxSize {
+		return left.symmetricDifference(right)
+	}
+	res := a.toBitmap().clone().(*bitmapContainer)
+	other := b.toBitmap()
+	for i := range res.words {
+		res.words[i] ^= other.words[i]
+	}
+	return res.recount().normalize()
+}
+
+// isSubset reports whether every value of a is also in b.
+func isSubset(a, b container) bool {
+	if a.cardinality() > b.cardinality() {
+		return false
+	}
+	for value := range a.values() {
+		if !b.contains(value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,4 @@
+// Package roaringbitmap implements [collection.Bitmap] as a Roaring bitmap.
+package roaringbitmap
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,346 @@
+package roaringbitmap
+
+import (
+	"iter"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/predicate"
+)
+
+type roaringBitmap struct {
+	keys       []uint16
+	containers []container
+	len        int
+}
+
+var _ collection.Bitmap = (*roaringBitmap)(nil)
+
+func New() *roaringBitmap {
+	return &roaringBitmap{
+		keys:       make([]uint16, 0),
+		containers: make([]container, 0),
+		len:        0,
+	}
+}
+
+func split(value uint32) (uint16, uint16) {
+	return uint16(value >> 16), uint16(value)
+}
+
+func join(key, low uint16) uint32 {
+	return uint32(key)<<16 | uint32(low)
+}
+
+func (b *roaringBitmap) Len() int {
+	return b.len
+}
+
+func (b *roaringBitmap) Contains(element uint32) bool {
+	key, low := split(element)
+	index, found := slices.BinarySearch(b.keys, key)
+	return found && b.containers[index].contains(low)
+}
+
+func (b *roaringBitmap) IsEmpty() bool {
+	return b.len == 0
+}
+
+func (b *roaringBitmap) Clear() {
+	b.keys = make([]uint16, 0)
+	b.containers = make([]container, 0)
+	b.len = 0
+}
+
+func (b *roaringBitmap) Any(pred predicate.Predicate[uint32]) bool {
+	for element := range b.Values() {
+		if pred(element) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *roaringBitmap) Count(pred predicate.Predicate[uint32]) int {
+	var count int
+	for element := range b.Values() {
+		if pred(element) {
+			count++
+		}
+	}
+	return count
+}
+
+func (b *roaringBitmap) Every(pred predicate.Predicate[uint32]) bool {
+	for element := range b.Values() {
+		if !pred(element) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *roaringBitmap) ForEach(fn func(element uint32)) {
+	for element := range b.Values() {
+		fn(element)
+	}
+}
+
+func (b *roaringBitmap) Add(element uint32) bool {
+	key, low := split(element)
+	index, found := slices.BinarySearch(b.keys, key)
+	if !found {
+		b.keys = slices.Insert(b.keys, index, key)
+		b.containers = slices.Insert(b.containers, index, container(newArrayContainer(low)))
+		b.len++
+		return true
+	}
+	updated, added := b.containers[index].add(low)
+	b.containers[index] = updated
+	if added {
+		b.len++
+	}
+	return added
+}
+
+func (b *roaringBitmap) Values() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range b.containers {
+			key := b.keys[i]
+			for low := range c.values() {
+				if !yield(join(key, low)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *roaringBitmap) Extend(values ...uint32) {
+	for _, value := range values {
+		b.Add(value)
+	}
+}
+
+func (b *roaringBitmap) Difference(other collection.Set[uint32]) Option[collection.Set[uint32]] {
+	right := asRoaring(other)
+	res := New()
+	j := 0
+	for i, key := range b.keys {
+		for j < len(right.keys) && right.keys[j] < key {
+			j++
+		}
+		if j < len(right.keys) && right.keys[j] == key {
+			res.append(key, andNot(b.containers[i], right.containers[j]))
+			continue
+		}
+		res.append(key, b.containers[i].clone())
+	}
+	return someIfNotEmpty(res)
+}
+
+func (b *roaringBitmap) Intersect(other collection.Set[uint32]) Option[collection.Set[uint32]] {
+	right := asRoaring(other)
+	res := New()
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(right.keys) {
+		switch {
+		case b.keys[i] < right.keys[j]:
+			i++
+		case b.keys[i] > right.keys[j]:
+			j++
+		default:
+			res.append(b.keys[i], and(b.containers[i], right.containers[j]))
+			i++
+			j++
+		}
+	}
+	return someIfNotEmpty(res)
+}
+
+func (b *roaringBitmap) IsSubsetOf(other collection.Set[uint32]) bool {
+	if b.Len() > other.Len() {
+		return false
+	}
+	right, ok := other.(*roaringBitmap)
+	if !ok {
+		for element := range b.Values() {
+			if !other.Contains(element) {
+				return false
+			}
+		}
+		return true
+	}
+	j := 0
+	for i, key := range b.keys {
+		for j < len(right.keys) && right.keys[j] < key {
+			j++
+		}
+		if j == len(right.keys) || right.keys[j] != key {
+			return false
+		}
+		if !isSubset(b.containers[i], right.containers[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *roaringBitmap) IsSupersetOf(other collection.Set[uint32]) bool {
+	if right, ok := other.(*roaringBitmap); ok {
+		return right.IsSubsetOf(b)
+	}
+	if b.Len() < other.Len() {
+		return false
+	}
+	for element := range other.Values() {
+		if !b.Contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *roaringBitmap) Remove(element uint32) bool {
+	key, low := split(element)
+	index, found := slices.BinarySearch(b.keys, key)
+	if !found {
+		return false
+	}
+	updated, removed := b.containers[index].remove(low)
+	if !removed {
+		return false
+	}
+	b.len--
+	if updated.cardinality() == 0 {
+		b.keys = slices.Delete(b.keys, index, index+1)
+		b.containers = slices.Delete(b.containers, index, index+1)
+		return true
+	}
+	b.containers[index] = updated
+	return true
+}
+
+func (b *roaringBitmap) SymmetricDifference(other collection.Set[uint32]) Option[collection.Set[uint32]] {
+	right := asRoaring(other)
+	res := New()
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(right.keys) {
+		switch {
+		case j == len(right.keys) || (i < len(b.keys) && b.keys[i] < right.keys[j]):
+			res.append(b.keys[i], b.containers[i].clone())
+			i++
+		case i == len(b.keys) || b.keys[i] > right.keys[j]:
+			res.append(right.keys[j], right.containers[j].clone())
+			j++
+		default:
+			res.append(b.keys[i], xor(b.containers[i], right.containers[j]))
+			i++
+			j++
+		}
+	}
+	return someIfNotEmpty(res)
+}
+
+func (b *roaringBitmap) Union(other collection.Set[uint32]) collection.Set[uint32] {
+	right := asRoaring(other)
+	res := New()
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(right.keys) {
+		switch {
+		case j == len(right.keys) || (i < len(b.keys) && b.keys[i] < right.keys[j]):
+			res.append(b.keys[i], b.containers[i].clone())
+			i++
+		case i == len(b.keys) || b.keys[i] > right.keys[j]:
+			res.append(right.keys[j], right.containers[j].clone())
+			j++
+		default:
+			res.append(b.keys[i], or(b.containers[i], right.containers[j]))
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+func (b *roaringBitmap) Min() Option[uint32] {
+	if b.IsEmpty() {
+		return None[uint32]()
+	}
+	return Some(join(b.keys[0], b.containers[0].minimum()))
+}
+
+func (b *roaringBitmap) Max() Option[uint32] {
+	if b.IsEmpty() {
+		return None[uint32]()
+	}
+	last := len(b.keys) - 1
+	return Some(join(b.keys[last], b.containers[last].maximum()))
+}
+
+func (b *roaringBitmap) Rank(value uint32) int {
+	key, low := split(value)
+	var res int
+	for i, k := range b.keys {
+		if k > key {
+			break
+		}
+		if k < key {
+			res += b.containers[i].cardinality()
+			continue
+		}
+		res += b.containers[i].rank(low)
+	}
+	return res
+}
+
+func (b *roaringBitmap) Select(rank int) Option[uint32] {
+	if rank < 0 || rank >= b.len {
+		return None[uint32]()
+	}
+	for i, c := range b.containers {
+		if rank < c.cardinality() {
+			return Some(join(b.keys[i], c.selectAt(rank)))
+		}
+		rank -= c.cardinality()
+	}
+	return None[uint32]()
+}
+
+func (b *roaringBitmap) RunOptimize() {
+	for i, c := range b.containers {
+		b.containers[i] = c.runOptimize()
+	}
+}
+
+// append adds a container for a key greater than every existing key, skipping empty containers.
+func (b *roaringBitmap) append(key uint16, c container) {
+	if c.cardinality() == 0 {
+		return
+	}
+	b.keys = append(b.keys, key)
+	b.containers = append(b.containers, c)
+	b.len += c.cardinality()
+}
+
+// asRoaring returns other as a roaring bitmap, copying its values when it is another set type.
+func asRoaring(other collection.Set[uint32]) *roaringBitmap {
+	if res, ok := other.(*roaringBitmap); ok {
+		return res
+	}
+	res := New()
+	for value := range other.Values() {
+		res.Add(value)
+	}
+	return res
+}
+
+func someIfNotEmpty(res *roaringBitmap) Option[collection.Set[uint32]] {
+	if res.IsEmpty() {
+		return None[collection.Set[uint32]]()
+	}
+	var set collection.Set[uint32] = res
+	return Some(set)
+}
@@ -0,0 +1,232 @@
+package roaringbitmap
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// interval is an inclusive run of consecutive values.
+type interval struct {
+	start uint16
+	last  uint16
+}
+
+func (i interval) length() int {
+	return int(i.last) - int(i.start) + 1
+}
+
+// runContainer stores sorted, non-overlapping, non-adjacent runs of values.
+type runContainer struct {
+	runs []interval
+	card int
+}
+
+var _ container = (*runContainer)(nil)
+
+func newRunContainer(runs ...interval) *runContainer {
+	res := &runContainer{runs: runs}
+	for _, run := range runs {
+		res.card += run.length()
+	}
+	return res
+}
+
+// runContainerFrom builds a run container holding the values of c.
+func runContainerFrom(c container) *runContainer {
+	var runs []interval
+	for value := range c.values() {
+		if n := len(runs); n > 0 && int(runs[n-1].last)+1 == int(value) {
+			runs[n-1].last = value
+			continue
+		}
+		runs = append(runs, interval{start: value, last: value})
+	}
+	return newRunContainer(runs...)
+}
+
+// runContainerSize returns the serialized size of a run container with the given number of runs.
+func runContainerSize(runs int) int {
+	return 2 + 4*runs
+}
+
+// search returns the index of the last run starting at or before value, or -1.
+func (r *runContainer) search(value uint16) int {
+	return sort.Search(len(r.runs), func(i int) bool {
+		return r.runs[i].start > value
+	}) - 1
+}
+
+func (r *runContainer) add(value uint16) (container, bool) {
+	index := r.search(value)
+	if index >= 0 && value <= r.runs[index].last {
+		return r, false
+	}
+	extendsLeft := index >= 0 && int(r.runs[index].last)+1 == int(value)
+	extendsRight := index+1 < len(r.runs) && int(r.runs[index+1].start)-1 == int(value)
+	switch {
+	case extendsLeft && extendsRight:
+		r.runs[index].last = r.runs[index+1].last
+		r.runs = slices.Delete(r.runs, index+1, index+2)
+	case extendsLeft:
+		r.runs[index].last = value
+	case extendsRight:
+		r.runs[index+1].start = value
+	default:
+		r.runs = slices.Insert(r.runs, index+1, interval{start: value, last: value})
+	}
+	r.card++
+	return r.compact(), true
+}
+
+func (r *runContainer) remove(value uint16) (container, bool) {
+	index := r.search(value)
+	if index < 0 || value > r.runs[index].last {
+		return r, false
+	}
+	run := r.runs[index]
+	switch {
+	case run.start == run.last:
+		r.runs = slices.Delete(r.runs, index, index+1)
+	case run.start == value:
+		r.runs[index].start++
+	case run.last == value:
+		r.runs[index].last--
+	default:
+		r.runs[index].last = value - 1
+		r.runs = slices.Insert(r.runs, index+1, interval{start: value + 1, last: run.last})
+	}
+	r.card--
+	return r.compact(), true
+}
+
+func (r *runContainer) contains(value uint16) bool {
+	index := r.search(value)
+	return index >= 0 && value <= r.runs[index].last
+}
+
+func (r *runContainer) cardinality() int {
+	return r.card
+}
+
+func (r *runContainer) rank(value uint16) int {
+	var res int
+	for _, run := range r.runs {
+		if value < run.start {
+			break
+		}
+		if value <= run.last {
+			return res + int(value-run.start) + 1
+		}
+		res += run.length()
+	}
+	return res
+}
+
+func (r *runContainer) selectAt(index int) uint16 {
+	for _, run := range r.runs {
+		if index < run.length() {
+			return run.start + uint16(index)
+		}
+		index -= run.length()
+	}
+	return 0
+}
+
+func (r *runContainer) minimum() uint16 {
+	return r.runs[0].start
+}
+
+func (r *runContainer) maximum() uint16 {
+	return r.runs[len(r.runs)-1].last
+}
+
+func (r *runContainer) values() iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		for _, run := range r.runs {
+			for value := int(run.start); value <= int(run.last); value++ {
+				if !yield(uint16(value)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *runContainer) clone() container {
+	return &runContainer{
+		runs: slices.Clone(r.runs),
+		card: r.card,
+	}
+}
+
+func (r *runContainer) toBitmap() *bitmapContainer {
+	res := newBitmapContainer()
+	for _, run := range r.runs {
+		res.setRange(run.start, run.last)
+	}
+	return res
+}
+
+func (r *runContainer) runOptimize() container {
+	return r.compact()
+}
+
+func (r *runContainer) sizeInBytes() int {
+	return runContainerSize(len(r.runs))
+}
+
+// compact converts the container to an array or bitmap once runs stop paying off.
+func (r *runContainer) compact() container {
+	if r.card <= arrayMaxSize {
+		if 2*r.card < r.sizeInBytes() {
+			res := make([]uint16, 0, r.card)
+			for value := range r.values() {
+				res = append(res, value)
+			}
+			return newArrayContainer(res...)
+		}
+		return r
+	}
+	if bitmapSizeInBytes < r.sizeInBytes() {
+		return r.toBitmap()
+	}
+	return r
+}
+
+func (r *runContainer) intersect(other *runContainer) container {
+	var runs []interval
+	i, j := 0, 0
+	for i < len(r.runs) && j < len(other.runs) {
+		left, right := r.runs[i], other.runs[j]
+		start := max(left.start, right.start)
+		last := min(left.last, right.last)
+		if start <= last {
+			runs = append(runs, interval{start: start, last: last})
+		}
+		if left.last < right.last {
+			i++
+		} else {
+			j++
+		}
+	}
+	return newRunContainer(runs...).compact()
+}
+
+func (r *runContainer) union(other *runContainer) container {
+	merged := make([]interval, 0, len(r.runs)+len(other.runs))
+	merged = append(merged, r.runs...)
+	merged = append(merged, other.runs...)
+	slices.SortFunc(merged, func(a, b interval) int {
+		return int(a.start) - int(b.start)
+	})
+	runs := make([]interval, 0, len(merged))
+	for _, run := range merged {
+		if n := len(runs); n > 0 && int(run.start) <= int(runs[n-1].last)+1 {
+			runs[n-1].last = max(runs[n-1].last, run.last)
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return newRunContainer(runs...).compact()
+}
@@ -0,0 +1,240 @@
+package roaringbitmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// The constants below follow the portable Roaring serialization format
+// (https://github.com/RoaringBitmap/RoaringFormatSpec), which lets bitmaps
+// written here be read by other Roaring implementations and vice versa.
+const (
+	serialCookieNoRunContainer = 12346
+	serialCookie               = 12347
+	noOffsetThreshold          = 4
+)
+
+var errTruncated = errors.New("roaringbitmap: truncated data")
+
+func (b *roaringBitmap) hasRunContainers() bool {
+	for _, c := range b.containers {
+		if _, ok := c.(*runContainer); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *roaringBitmap) MarshalBinary() ([]byte, error) {
+	size := len(b.keys)
+	hasRuns := b.hasRunContainers()
+	var buf []byte
+	if hasRuns {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(serialCookie|(size-1)<<16))
+		flags := make([]byte, (size+7)/8)
+		for i, c := range b.containers {
+			if _, ok := c.(*runContainer); ok {
+				flags[i/8] |= 1 << (i % 8)
+			}
+		}
+		buf = append(buf, flags...)
+	} else {
+		buf = binary.LittleEndian.AppendUint32(buf, serialCookieNoRunContainer)
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(size))
+	}
+
+	for i, c := range b.containers {
+		buf = binary.LittleEndian.AppendUint16(buf, b.keys[i])
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(c.cardinality()-1))
+	}
+
+	if !hasRuns || size >= noOffsetThreshold {
+		offset := len(buf) + 4*size
+		for _, c := range b.containers {
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(offset))
+			offset += c.sizeInBytes()
+		}
+	}
+
+	for _, c := range b.containers {
+		switch c := c.(type) {
+		case *arrayContainer:
+			for _, value := range c.content {
+				buf = binary.LittleEndian.AppendUint16(buf, value)
+			}
+		case *bitmapContainer:
+			for _, word := range c.words {
+				buf = binary.LittleEndian.AppendUint64(buf, word)
+			}
+		case *runContainer:
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(len(c.runs)))
+			for _, run := range c.runs {
+				buf = binary.LittleEndian.AppendUint16(buf, run.start)
+				buf = binary.LittleEndian.AppendUint16(buf, run.last-run.start)
+			}
+		}
+	}
+	return buf, nil
+}
+
+func (b *roaringBitmap) UnmarshalBinary(data []byte) error {
+	r := reader{data: data}
+	cookie, err := r.uint32()
+	if err != nil {
+		return err
+	}
+
+	var size int
+	var runFlags []byte
+	switch {
+	case cookie == serialCookieNoRunContainer:
+		n, err := r.uint32()
+		if err != nil {
+			return err
+		}
+		size = int(n)
+	case cookie&0xFFFF == serialCookie:
+		size = int(cookie>>16) + 1
+		if runFlags, err = r.bytes((size + 7) / 8); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("roaringbitmap: unknown cookie %d", cookie)
+	}
+	if size > 1<<16 {
+		return fmt.Errorf("roaringbitmap: invalid container count %d", size)
+	}
+
+	keys := make([]uint16, size)
+	cards := make([]int, size)
+	for i := range size {
+		if keys[i], err = r.uint16(); err != nil {
+			return err
+		}
+		if i > 0 && keys[i] <= keys[i-1] {
+			return errors.New("roaringbitmap: container keys are not strictly increasing")
+		}
+		card, err := r.uint16()
+		if err != nil {
+			return err
+		}
+		cards[i] = int(card) + 1
+	}
+
+	if runFlags == nil || size >= noOffsetThreshold {
+		if _, err = r.bytes(4 * size); err != nil {
+			return err
+		}
+	}
+
+	containers := make([]container, size)
+	total := 0
+	for i := range size {
+		isRun := runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0
+		switch {
+		case isRun:
+			containers[i], err = r.runContainer()
+		case cards[i] <= arrayMaxSize:
+			containers[i], err = r.arrayContainer(cards[i])
+		default:
+			containers[i], err = r.bitmapContainer()
+		}
+		if err != nil {
+			return err
+		}
+		if containers[i].cardinality() != cards[i] {
+			return fmt.Errorf("roaringbitmap: container %d has cardinality %d, header says %d", i, containers[i].cardinality(), cards[i])
+		}
+		total += cards[i]
+	}
+
+	b.keys = keys
+	b.containers = containers
+	b.len = total
+	return nil
+}
+
+// reader decodes little-endian values from a byte slice.
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || len(r.data)-r.pos < n {
+		return nil, errTruncated
+	}
+	res := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return res, nil
+}
+
+func (r *reader) uint16() (uint16, error) {
+	buf, err := r.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(buf), nil
+}
+
+func (r *reader) uint32() (uint32, error) {
+	buf, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf), nil
+}
+
+func (r *reader) arrayContainer(card int) (container, error) {
+	buf, err := r.bytes(2 * card)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]uint16, card)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint16(buf[2*i:])
+		if i > 0 && values[i] <= values[i-1] {
+			return nil, errors.New("roaringbitmap: array container values are not strictly increasing")
+		}
+	}
+	return newArrayContainer(values...), nil
+}
+
+func (r *reader) bitmapContainer() (container, error) {
+	buf, err := r.bytes(bitmapSizeInBytes)
+	if err != nil {
+		return nil, err
+	}
+	res := newBitmapContainer()
+	for i := range res.words {
+		res.words[i] = binary.LittleEndian.Uint64(buf[8*i:])
+		res.card += bits.OnesCount64(res.words[i])
+	}
+	return res, nil
+}
+
+func (r *reader) runContainer() (container, error) {
+	count, err := r.uint16()
+	if err != nil {
+		return nil, err
+	}
+	buf, err := r.bytes(4 * int(count))
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]interval, count)
+	for i := range runs {
+		start := binary.LittleEndian.Uint16(buf[4*i:])
+		length := binary.LittleEndian.Uint16(buf[4*i+2:])
+		if int(start)+int(length) > 0xFFFF {
+			return nil, errors.New("roaringbitmap: run container overflows its chunk")
+		}
+		runs[i] = interval{start: start, last: start + length}
+		if i > 0 && int(runs[i].start) <= int(runs[i-1].last)+1 {
+			return nil, errors.New("roaringbitmap: run container runs overlap or touch")
+		}
+	}
+	return newRunContainer(runs...), nil
+}
@@ -0,0 +1,4 @@
+// Package rtree implements [collection.RTree].
+package rtree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
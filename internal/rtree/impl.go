@@ -0,0 +1,330 @@
+package rtree
+
+import (
+	"container/heap"
+	"iter"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+type rTree[V any] struct {
+	root       *node[V]
+	maxEntries int
+	minEntries int
+	len        int
+}
+
+var _ collection.RTree[int] = (*rTree[int])(nil)
+
+// New returns an R-tree whose nodes hold at most maxEntries entries or
+// children, bulk loaded with items using Sort-Tile-Recursive packing.
+// maxEntries is raised to 3 if smaller. Nodes split quadratically and, apart
+// from the root, keep at least 40% of maxEntries.
+func New[V any](maxEntries int, items []kv.Pair[spatial.Rect, V]) *rTree[V] {
+	maxEntries = max(maxEntries, 3)
+	t := &rTree[V]{
+		maxEntries: maxEntries,
+		minEntries: max(maxEntries*2/5, 1),
+	}
+	entries := make([]entry[V], 0, len(items))
+	for _, item := range items {
+		entries = append(entries, entry[V]{rect: item.Key(), value: item.Value()})
+	}
+	t.load(entries)
+	return t
+}
+
+func (t *rTree[V]) Len() int {
+	return t.len
+}
+
+func (t *rTree[V]) Contains(element spatial.Rect) bool {
+	leaf, _ := t.find(t.root, element, func(V) bool {
+		return true
+	})
+	return leaf != nil
+}
+
+func (t *rTree[V]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *rTree[V]) Clear() {
+	t.root = nil
+	t.len = 0
+}
+
+func (t *rTree[V]) Any(pred predicate.Predicate[kv.Pair[spatial.Rect, V]]) bool {
+	for rect, value := range t.All() {
+		if pred(kv.New(rect, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *rTree[V]) Count(pred predicate.Predicate[kv.Pair[spatial.Rect, V]]) int {
+	var count int
+	for rect, value := range t.All() {
+		if pred(kv.New(rect, value)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *rTree[V]) Every(pred predicate.Predicate[kv.Pair[spatial.Rect, V]]) bool {
+	for rect, value := range t.All() {
+		if !pred(kv.New(rect, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *rTree[V]) ForEach(fn func(pair kv.Pair[spatial.Rect, V])) {
+	for rect, value := range t.All() {
+		fn(kv.New(rect, value))
+	}
+}
+
+func (t *rTree[V]) Insert(rect spatial.Rect, value V) {
+	t.insert(entry[V]{rect: rect, value: value})
+	t.len++
+}
+
+func (t *rTree[V]) Delete(rect spatial.Rect) Option[V] {
+	return t.DeleteFunc(rect, func(V) bool {
+		return true
+	})
+}
+
+func (t *rTree[V]) DeleteFunc(rect spatial.Rect, pred predicate.Predicate[V]) Option[V] {
+	leaf, index := t.find(t.root, rect, pred)
+	if leaf == nil {
+		return None[V]()
+	}
+	removed := leaf.entries[index].value
+	leaf.entries = slices.Delete(leaf.entries, index, index+1)
+	t.len--
+	t.condense(leaf)
+	return Some(removed)
+}
+
+func (t *rTree[V]) Search(rect spatial.Rect) iter.Seq2[spatial.Rect, V] {
+	var walk func(n *node[V], yield func(spatial.Rect, V) bool) bool
+	walk = func(n *node[V], yield func(spatial.Rect, V) bool) bool {
+		for _, e := range n.entries {
+			if e.rect.Intersects(rect) && !yield(e.rect, e.value) {
+				return false
+			}
+		}
+		for _, child := range n.children {
+			if child.bounds.Intersects(rect) && !walk(child, yield) {
+				return false
+			}
+		}
+		return true
+	}
+	return func(yield func(spatial.Rect, V) bool) {
+		if t.root != nil {
+			walk(t.root, yield)
+		}
+	}
+}
+
+// Nearest visits nodes and entries best first, ordered by their distance to
+// point, so the first k entries popped are the k nearest.
+func (t *rTree[V]) Nearest(point spatial.Point, k int) []kv.Pair[spatial.Rect, V] {
+	var res []kv.Pair[spatial.Rect, V]
+	if t.root == nil || k <= 0 {
+		return res
+	}
+	queue := &candidates[V]{{node: t.root, distance: t.root.bounds.Distance(point)}}
+	for queue.Len() > 0 && len(res) < k {
+		next := heap.Pop(queue).(candidate[V])
+		if next.node == nil {
+			res = append(res, kv.New(next.entry.rect, next.entry.value))
+			continue
+		}
+		for _, e := range next.node.entries {
+			heap.Push(queue, candidate[V]{entry: e, distance: e.rect.Distance(point)})
+		}
+		for _, child := range next.node.children {
+			heap.Push(queue, candidate[V]{node: child, distance: child.bounds.Distance(point)})
+		}
+	}
+	return res
+}
+
+func (t *rTree[V]) All() iter.Seq2[spatial.Rect, V] {
+	return func(yield func(spatial.Rect, V) bool) {
+		if t.root == nil {
+			return
+		}
+		leafEntries(t.root, func(e entry[V]) bool {
+			return yield(e.rect, e.value)
+		})
+	}
+}
+
+func (t *rTree[V]) Height() int {
+	var height int
+	for n := t.root; n != nil; height++ {
+		if n.leaf {
+			n = nil
+		} else {
+			n = n.children[0]
+		}
+	}
+	return height
+}
+
+func (t *rTree[V]) Root() Option[collection.RTreeNode[V]] {
+	return someNode(t.root)
+}
+
+// find returns the leaf and index of an entry with exactly rect whose value
+// satisfies pred, or a nil leaf if there is none.
+func (t *rTree[V]) find(n *node[V], rect spatial.Rect, pred predicate.Predicate[V]) (*node[V], int) {
+	if n == nil || !n.bounds.ContainsRect(rect) {
+		return nil, 0
+	}
+	for index, e := range n.entries {
+		if e.rect == rect && pred(e.value) {
+			return n, index
+		}
+	}
+	for _, child := range n.children {
+		if leaf, index := t.find(child, rect, pred); leaf != nil {
+			return leaf, index
+		}
+	}
+	return nil, 0
+}
+
+func (t *rTree[V]) insert(e entry[V]) {
+	if t.root == nil {
+		t.root = &node[V]{leaf: true}
+	}
+	leaf := t.chooseLeaf(e.rect)
+	leaf.entries = append(leaf.entries, e)
+	t.adjust(leaf)
+}
+
+// chooseLeaf descends to the leaf whose bounds need the least enlargement to
+// cover rect, breaking ties by the smaller area.
+func (t *rTree[V]) chooseLeaf(rect spatial.Rect) *node[V] {
+	n := t.root
+	for !n.leaf {
+		best := n.children[0]
+		bestGrowth := enlargement(best.bounds, rect)
+		for _, child := range n.children[1:] {
+			growth := enlargement(child.bounds, rect)
+			if growth < bestGrowth || growth == bestGrowth && child.bounds.Area() < best.bounds.Area() {
+				best, bestGrowth = child, growth
+			}
+		}
+		n = best
+	}
+	return n
+}
+
+// adjust walks from n to the root, splitting overflowing nodes and
+// recomputing bounds on the way.
+func (t *rTree[V]) adjust(n *node[V]) {
+	for n != nil {
+		if n.size() > t.maxEntries {
+			sibling := t.split(n)
+			if n.parent == nil {
+				t.root = &node[V]{children: []*node[V]{n, sibling}}
+				t.root.adopt()
+			} else {
+				sibling.parent = n.parent
+				n.parent.children = append(n.parent.children, sibling)
+			}
+		}
+		n.recalc()
+		n = n.parent
+	}
+}
+
+// condense walks from leaf to the root after a removal. Nodes left with
+// fewer than the minimum number of entries are unlinked and their entries
+// inserted again.
+func (t *rTree[V]) condense(leaf *node[V]) {
+	var orphans []entry[V]
+	for n := leaf; n != t.root; {
+		parent := n.parent
+		if n.size() < t.minEntries {
+			for index, child := range parent.children {
+				if child == n {
+					parent.children = slices.Delete(parent.children, index, index+1)
+					break
+				}
+			}
+			leafEntries(n, func(e entry[V]) bool {
+				orphans = append(orphans, e)
+				return true
+			})
+		} else {
+			n.recalc()
+		}
+		n = parent
+	}
+	t.root.recalc()
+	for !t.root.leaf && len(t.root.children) == 1 {
+		t.root = t.root.children[0]
+		t.root.parent = nil
+	}
+	if t.root.size() == 0 {
+		t.root = nil
+	}
+	for _, e := range orphans {
+		t.insert(e)
+	}
+}
+
+func enlargement(bounds, rect spatial.Rect) float64 {
+	return bounds.Union(rect).Area() - bounds.Area()
+}
+
+// candidate is a node or, when node is nil, an entry waiting to be visited
+// by Nearest.
+type candidate[V any] struct {
+	node     *node[V]
+	entry    entry[V]
+	distance float64
+}
+
+// candidates is a [heap.Interface] ordering candidates by distance.
+type candidates[V any] []candidate[V]
+
+func (c candidates[V]) Len() int {
+	return len(c)
+}
+
+func (c candidates[V]) Less(i, j int) bool {
+	return c[i].distance < c[j].distance
+}
+
+func (c candidates[V]) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+func (c *candidates[V]) Push(x any) {
+	*c = append(*c, x.(candidate[V]))
+}
+
+func (c *candidates[V]) Pop() any {
+	old := *c
+	last := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return last
+}
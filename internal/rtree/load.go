@@ -0,0 +1,82 @@
+package rtree
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// load replaces the contents of the tree with entries, packing them bottom
+// up with Sort-Tile-Recursive so that nodes are full and overlap little.
+func (t *rTree[V]) load(entries []entry[V]) {
+	t.root = nil
+	t.len = len(entries)
+	if len(entries) == 0 {
+		return
+	}
+	var level []*node[V]
+	for _, group := range tile(entries, t.maxEntries, t.minEntries, func(e entry[V]) spatial.Rect {
+		return e.rect
+	}) {
+		leaf := &node[V]{entries: group, leaf: true}
+		leaf.recalc()
+		level = append(level, leaf)
+	}
+	for len(level) > 1 {
+		var parents []*node[V]
+		for _, group := range tile(level, t.maxEntries, t.minEntries, func(n *node[V]) spatial.Rect {
+			return n.bounds
+		}) {
+			parent := &node[V]{children: group}
+			parent.adopt()
+			parent.recalc()
+			parents = append(parents, parent)
+		}
+		level = parents
+	}
+	t.root = level[0]
+}
+
+// tile sorts items into vertical slices by the x coordinate of their
+// centers, sorts each slice by y and cuts it into groups of up to capacity.
+// Unless all items fit in one group, every group holds at least minimum.
+func tile[T any](items []T, capacity, minimum int, rect func(T) spatial.Rect) [][]T {
+	items = slices.Clone(items)
+	byAxis := func(axis func(spatial.Point) float64) func(a, b T) int {
+		return func(a, b T) int {
+			return cmp.Compare(axis(rect(a).Center()), axis(rect(b).Center()))
+		}
+	}
+	groups := int(math.Ceil(float64(len(items)) / float64(capacity)))
+	slice := capacity * int(math.Ceil(math.Sqrt(float64(groups))))
+	slices.SortFunc(items, byAxis(func(p spatial.Point) float64 {
+		return p.X
+	}))
+	verticals := slices.Collect(slices.Chunk(items, slice))
+	// A last slice too small to fill a group joins the one before it
+	if n := len(verticals); n > 1 && len(verticals[n-1]) < minimum {
+		verticals[n-2] = items[len(items)-len(verticals[n-2])-len(verticals[n-1]):]
+		verticals = verticals[:n-1]
+	}
+	var res [][]T
+	for _, vertical := range verticals {
+		slices.SortFunc(vertical, byAxis(func(p spatial.Point) float64 {
+			return p.Y
+		}))
+		chunks := slices.Collect(slices.Chunk(vertical, capacity))
+		// Every slice but the last holds a multiple of capacity items, so
+		// only the last group can come up short. Topping it up from the group
+		// before leaves that one with more than capacity-minimum items, which
+		// is at least minimum since minimum is at most 40% of capacity.
+		if n := len(chunks); n > 1 && len(chunks[n-1]) < minimum {
+			start := len(vertical) - len(chunks[n-2]) - len(chunks[n-1])
+			split := len(vertical) - minimum
+			chunks[n-2] = vertical[start:split:split]
+			chunks[n-1] = vertical[split:len(vertical):len(vertical)]
+		}
+		res = append(res, chunks...)
+	}
+	return res
+}
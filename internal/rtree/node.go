@@ -0,0 +1,124 @@
+package rtree
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+type entry[V any] struct {
+	rect  spatial.Rect
+	value V
+}
+
+// node is either a leaf holding entries or an inner node holding children.
+// Every leaf sits at the same depth.
+type node[V any] struct {
+	bounds   spatial.Rect
+	parent   *node[V]
+	children []*node[V]
+	entries  []entry[V]
+	leaf     bool
+}
+
+// compile time interface guard check
+var _ collection.RTreeNode[int] = (*node[int])(nil)
+
+func (n *node[V]) Bounds() spatial.Rect {
+	return n.bounds
+}
+
+func (n *node[V]) Children() iter.Seq[collection.RTreeNode[V]] {
+	return func(yield func(collection.RTreeNode[V]) bool) {
+		for _, child := range n.children {
+			if !yield(child) {
+				return
+			}
+		}
+	}
+}
+
+func (n *node[V]) Parent() Option[collection.RTreeNode[V]] {
+	return someNode(n.parent)
+}
+
+func (n *node[V]) IsLeaf() bool {
+	return n.leaf
+}
+
+func (n *node[V]) Values() []kv.Pair[spatial.Rect, V] {
+	res := make([]kv.Pair[spatial.Rect, V], 0, len(n.entries))
+	for _, e := range n.entries {
+		res = append(res, kv.New(e.rect, e.value))
+	}
+	return res
+}
+
+func someNode[V any](n *node[V]) Option[collection.RTreeNode[V]] {
+	if n == nil {
+		return None[collection.RTreeNode[V]]()
+	}
+	var res collection.RTreeNode[V] = n
+	return Some(res)
+}
+
+// size returns the number of entries or children held by n.
+func (n *node[V]) size() int {
+	if n.leaf {
+		return len(n.entries)
+	}
+	return len(n.children)
+}
+
+// rects returns the rectangles of the entries or children held by n.
+func (n *node[V]) rects() []spatial.Rect {
+	res := make([]spatial.Rect, 0, n.size())
+	for _, e := range n.entries {
+		res = append(res, e.rect)
+	}
+	for _, child := range n.children {
+		res = append(res, child.bounds)
+	}
+	return res
+}
+
+// recalc recomputes the bounds of n from its entries or children.
+func (n *node[V]) recalc() {
+	rects := n.rects()
+	if len(rects) == 0 {
+		n.bounds = spatial.Rect{}
+		return
+	}
+	n.bounds = rects[0]
+	for _, rect := range rects[1:] {
+		n.bounds = n.bounds.Union(rect)
+	}
+}
+
+// adopt makes n the parent of each of its children.
+func (n *node[V]) adopt() {
+	for _, child := range n.children {
+		child.parent = n
+	}
+}
+
+func leafEntries[V any](n *node[V], yield func(entry[V]) bool) bool {
+	if n.leaf {
+		for _, e := range n.entries {
+			if !yield(e) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, child := range n.children {
+		if !leafEntries(child, yield) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package rtree
+
+import (
+	"math"
+
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// split moves part of the contents of the overflowing node n into a new
+// sibling using Guttman's quadratic split and returns the sibling.
+func (t *rTree[V]) split(n *node[V]) *node[V] {
+	left, right := quadraticSplit(n.rects(), t.minEntries)
+	sibling := &node[V]{leaf: n.leaf}
+	if n.leaf {
+		entries := n.entries
+		n.entries = pick(entries, left)
+		sibling.entries = pick(entries, right)
+	} else {
+		children := n.children
+		n.children = pick(children, left)
+		sibling.children = pick(children, right)
+		sibling.adopt()
+	}
+	n.recalc()
+	sibling.recalc()
+	return sibling
+}
+
+// quadraticSplit partitions rects into two groups of indices, each holding
+// at least minimum rectangles. It seeds the groups with the pair that would
+// waste the most area if kept together, then repeatedly assigns the
+// rectangle with the strongest preference for one group.
+func quadraticSplit(rects []spatial.Rect, minimum int) ([]int, []int) {
+	seedA, seedB := 0, 1
+	worst := math.Inf(-1)
+	for i := range rects {
+		for j := i + 1; j < len(rects); j++ {
+			waste := rects[i].Union(rects[j]).Area() - rects[i].Area() - rects[j].Area()
+			if waste > worst {
+				seedA, seedB, worst = i, j, waste
+			}
+		}
+	}
+	groups := [2][]int{{seedA}, {seedB}}
+	bounds := [2]spatial.Rect{rects[seedA], rects[seedB]}
+	assigned := make([]bool, len(rects))
+	assigned[seedA], assigned[seedB] = true, true
+	for remaining := len(rects) - 2; remaining > 0; remaining-- {
+		// Hand everything left to a group that needs it to reach the minimum
+		for group := range groups {
+			if len(groups[group])+remaining == minimum {
+				for index := range rects {
+					if !assigned[index] {
+						groups[group] = append(groups[group], index)
+					}
+				}
+				return groups[0], groups[1]
+			}
+		}
+		next, preference := -1, -1.0
+		for index, rect := range rects {
+			if assigned[index] {
+				continue
+			}
+			diff := math.Abs(enlargement(bounds[0], rect) - enlargement(bounds[1], rect))
+			if diff > preference {
+				next, preference = index, diff
+			}
+		}
+		growthA := enlargement(bounds[0], rects[next])
+		growthB := enlargement(bounds[1], rects[next])
+		group := 0
+		switch {
+		case growthB < growthA:
+			group = 1
+		case growthB == growthA && bounds[1].Area() < bounds[0].Area():
+			group = 1
+		case growthB == growthA && bounds[1].Area() == bounds[0].Area() && len(groups[1]) < len(groups[0]):
+			group = 1
+		}
+		groups[group] = append(groups[group], next)
+		bounds[group] = bounds[group].Union(rects[next])
+		assigned[next] = true
+	}
+	return groups[0], groups[1]
+}
+
+func pick[T any](items []T, indices []int) []T {
+	res := make([]T, 0, len(indices))
+	for _, index := range indices {
+		res = append(res, items[index])
+	}
+	return res
+}
@@ -0,0 +1,5 @@
+// Package searchtree implements the traversals shared by the binary
+// [collection.SearchTree] implementations.
+package searchtree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
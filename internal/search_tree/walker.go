@@ -0,0 +1,102 @@
+package searchtree
+
+import (
+	"iter"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+// Walker traverses a binary search tree whose nodes have type N, nil marking
+// a missing child. Trees describe their nodes through functions rather than
+// methods, so that node types remain free to export their own Left and
+// Right. Root is read each time an iterator starts, not when it is created.
+type Walker[K any, V any, N comparable] struct {
+	Root  func() N
+	Left  func(n N) N
+	Right func(n N) N
+	Entry func(n N) (K, V)
+}
+
+// All returns an iterator over every entry in the order the traversal
+// options select, in-order by default.
+func (w Walker[K, V, N]) All(opts ...collection.SearchTreeTraversalOption) iter.Seq2[K, V] {
+	traversalCfg := collection.DefaultSearchTreeTraversalConfiguration()
+	for _, opt := range opts {
+		opt(traversalCfg)
+	}
+	return func(yield func(K, V) bool) {
+		switch traversalCfg.Strategy() {
+		case collection.SearchTreeStrategyInOrder:
+			w.inorder(w.Root(), yield)
+		case collection.SearchTreeStrategyPreOrder:
+			w.preorder(w.Root(), yield)
+		case collection.SearchTreeStrategyPostOrder:
+			w.postorder(w.Root(), yield)
+		}
+	}
+}
+
+// Range returns an iterator over the entries within the bounds in ascending
+// key order, with keys ordered by fn.
+func (w Walker[K, V, N]) Range(fn func(a, b K) compare.Order, opts ...where.WhereOption[K]) iter.Seq2[K, V] {
+	wh := where.Default[K]()
+	for _, opt := range opts {
+		opt(wh)
+	}
+	var nilNode N
+	var walk func(n N, yield func(K, V) bool) bool
+	walk = func(n N, yield func(K, V) bool) bool {
+		if n == nilNode {
+			return true
+		}
+		// Subtrees entirely outside the bounds are skipped
+		key, value := w.Entry(n)
+		admitsFrom := wh.AdmitsFrom(key, fn)
+		admitsTo := wh.AdmitsTo(key, fn)
+		if admitsFrom && !walk(w.Left(n), yield) {
+			return false
+		}
+		if admitsFrom && admitsTo && !yield(key, value) {
+			return false
+		}
+		return !admitsTo || walk(w.Right(n), yield)
+	}
+	return func(yield func(K, V) bool) {
+		walk(w.Root(), yield)
+	}
+}
+
+func (w Walker[K, V, N]) inorder(n N, yield func(K, V) bool) bool {
+	var nilNode N
+	if n == nilNode {
+		return true
+	}
+	key, value := w.Entry(n)
+	return w.inorder(w.Left(n), yield) &&
+		yield(key, value) &&
+		w.inorder(w.Right(n), yield)
+}
+
+func (w Walker[K, V, N]) preorder(n N, yield func(K, V) bool) bool {
+	var nilNode N
+	if n == nilNode {
+		return true
+	}
+	key, value := w.Entry(n)
+	return yield(key, value) &&
+		w.preorder(w.Left(n), yield) &&
+		w.preorder(w.Right(n), yield)
+}
+
+func (w Walker[K, V, N]) postorder(n N, yield func(K, V) bool) bool {
+	var nilNode N
+	if n == nilNode {
+		return true
+	}
+	key, value := w.Entry(n)
+	return w.postorder(w.Left(n), yield) &&
+		w.postorder(w.Right(n), yield) &&
+		yield(key, value)
+}
@@ -0,0 +1,4 @@
+// Package segmenttree implements [collection.SegmentTree].
+package segmenttree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
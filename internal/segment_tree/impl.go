@@ -0,0 +1,193 @@
+package segmenttree
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+// segmentTree is a recursive segment tree stored in an array. Node 1 covers
+// [0, len) and node i has children 2i and 2i+1 covering the two halves. An
+// update pending on a node has already been applied to the node itself but
+// not yet to its children.
+type segmentTree[T any, U any] struct {
+	combine  func(a, b T) T
+	identity T
+	apply    func(update U, value T, length int) T
+	compose  func(newer, older U) U
+	nodes    []T
+	lazy     []U
+	pending  []bool
+	len      int
+}
+
+var _ collection.SegmentTree[int, int] = (*segmentTree[int, int])(nil)
+
+// New returns a segment tree over items.
+//
+// combine must be associative with identity as its identity element.
+// apply(update, value, length) returns value, the aggregate of length
+// elements, after update has been applied to each of those elements. It must
+// distribute over combine. compose(newer, older) returns the single update
+// equivalent to applying older and then newer.
+func New[T any, U any](
+	items []T,
+	combine func(a, b T) T,
+	identity T,
+	apply func(update U, value T, length int) T,
+	compose func(newer, older U) U,
+) *segmentTree[T, U] {
+	t := &segmentTree[T, U]{
+		combine:  combine,
+		identity: identity,
+		apply:    apply,
+		compose:  compose,
+		nodes:    make([]T, 4*max(len(items), 1)),
+		lazy:     make([]U, 4*max(len(items), 1)),
+		pending:  make([]bool, 4*max(len(items), 1)),
+		len:      len(items),
+	}
+	if t.len > 0 {
+		t.build(1, 0, t.len, items)
+	}
+	return t
+}
+
+func (t *segmentTree[T, U]) Len() int {
+	return t.len
+}
+
+func (t *segmentTree[T, U]) Get(index int) Option[T] {
+	if index < 0 || index >= t.len {
+		return None[T]()
+	}
+	return Some(t.Query(where.From(index), where.To(index)))
+}
+
+func (t *segmentTree[T, U]) Set(index int, value T) bool {
+	if index < 0 || index >= t.len {
+		return false
+	}
+	t.set(1, 0, t.len, index, value)
+	return true
+}
+
+// Query combines the elements in range without modifying the tree. Pending
+// updates met on the way down are applied to the partial results instead of
+// being pushed to the children.
+func (t *segmentTree[T, U]) Query(opts ...where.WhereOption[int]) T {
+	start, end := where.Indices(t.len, opts...)
+	if start == end {
+		return t.identity
+	}
+	return t.query(1, 0, t.len, start, end)
+}
+
+func (t *segmentTree[T, U]) Apply(update U, opts ...where.WhereOption[int]) {
+	start, end := where.Indices(t.len, opts...)
+	if start == end {
+		return
+	}
+	t.update(1, 0, t.len, start, end, update)
+}
+
+func (t *segmentTree[T, U]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for index := range t.len {
+			if !yield(t.Get(index).Unwrap()) {
+				return
+			}
+		}
+	}
+}
+
+func (t *segmentTree[T, U]) build(node, lo, hi int, items []T) {
+	if hi-lo == 1 {
+		t.nodes[node] = items[lo]
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.build(2*node, lo, mid, items)
+	t.build(2*node+1, mid, hi, items)
+	t.nodes[node] = t.combine(t.nodes[2*node], t.nodes[2*node+1])
+}
+
+func (t *segmentTree[T, U]) set(node, lo, hi, index int, value T) {
+	if hi-lo == 1 {
+		t.nodes[node] = value
+		return
+	}
+	t.push(node, lo, hi)
+	mid := lo + (hi-lo)/2
+	if index < mid {
+		t.set(2*node, lo, mid, index, value)
+	} else {
+		t.set(2*node+1, mid, hi, index, value)
+	}
+	t.nodes[node] = t.combine(t.nodes[2*node], t.nodes[2*node+1])
+}
+
+func (t *segmentTree[T, U]) query(node, lo, hi, start, end int) T {
+	if start <= lo && hi <= end {
+		return t.nodes[node]
+	}
+	mid := lo + (hi-lo)/2
+	res := t.identity
+	if start < mid {
+		res = t.query(2*node, lo, mid, start, end)
+	}
+	if mid < end {
+		res = t.combine(res, t.query(2*node+1, mid, hi, start, end))
+	}
+	if t.pending[node] {
+		res = t.apply(t.lazy[node], res, min(hi, end)-max(lo, start))
+	}
+	return res
+}
+
+func (t *segmentTree[T, U]) update(node, lo, hi, start, end int, update U) {
+	if start <= lo && hi <= end {
+		t.applyNode(node, hi-lo, update)
+		return
+	}
+	t.push(node, lo, hi)
+	mid := lo + (hi-lo)/2
+	if start < mid {
+		t.update(2*node, lo, mid, start, end, update)
+	}
+	if mid < end {
+		t.update(2*node+1, mid, hi, start, end, update)
+	}
+	t.nodes[node] = t.combine(t.nodes[2*node], t.nodes[2*node+1])
+}
+
+// applyNode applies update to the node covering length elements and records
+// it as pending for the node's children.
+func (t *segmentTree[T, U]) applyNode(node, length int, update U) {
+	t.nodes[node] = t.apply(update, t.nodes[node], length)
+	if length == 1 {
+		return
+	}
+	if t.pending[node] {
+		t.lazy[node] = t.compose(update, t.lazy[node])
+	} else {
+		t.lazy[node] = update
+		t.pending[node] = true
+	}
+}
+
+// push hands the update pending on node down to its children.
+func (t *segmentTree[T, U]) push(node, lo, hi int) {
+	if !t.pending[node] {
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.applyNode(2*node, mid-lo, t.lazy[node])
+	t.applyNode(2*node+1, hi-mid, t.lazy[node])
+	var zero U
+	t.lazy[node] = zero
+	t.pending[node] = false
+}
@@ -0,0 +1,4 @@
+// Package splay implements [collection.SplayTree].
+package splay
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,316 @@
+package splay
+
+import (
+	"iter"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/where"
+	searchtree "codeberg.org/yaadata/bina/internal/search_tree"
+)
+
+type splayTree[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	root    *node[K, V]
+	len     int
+}
+
+var _ collection.SplayTree[int, int] = (*splayTree[int, int])(nil)
+
+// New returns an empty splay tree whose keys are ordered by fn.
+func New[K any, V any](fn func(a, b K) compare.Order) *splayTree[K, V] {
+	return &splayTree[K, V]{
+		compare: fn,
+		root:    nil,
+		len:     0,
+	}
+}
+
+func (t *splayTree[K, V]) Len() int {
+	return t.len
+}
+
+func (t *splayTree[K, V]) Contains(element K) bool {
+	return t.find(element) != nil
+}
+
+func (t *splayTree[K, V]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *splayTree[K, V]) Clear() {
+	t.root = nil
+	t.len = 0
+}
+
+func (t *splayTree[K, V]) Any(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *splayTree[K, V]) Every(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if !pred(kv.New(key, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *splayTree[K, V]) Count(pred predicate.Predicate[kv.Pair[K, V]]) int {
+	var count int
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *splayTree[K, V]) ForEach(fn func(pair kv.Pair[K, V])) {
+	for key, value := range t.All() {
+		fn(kv.New(key, value))
+	}
+}
+
+func (t *splayTree[K, V]) Delete(key K) Option[V] {
+	if t.root == nil {
+		return None[V]()
+	}
+	t.root = t.splay(t.root, key)
+	if !t.compare(key, t.root.key).IsEqual() {
+		return None[V]()
+	}
+	deleted := t.root
+	if deleted.left == nil {
+		t.root = deleted.right
+	} else {
+		// Every key on the left is smaller, so splaying for key brings the
+		// left maximum up with an empty right subtree
+		t.root = t.splay(deleted.left, key)
+		t.root.right = deleted.right
+	}
+	t.len--
+	return Some(deleted.value)
+}
+
+// Get returns the value for the given key and splays the key, or the last
+// node visited when it is missing, to the root.
+func (t *splayTree[K, V]) Get(key K) Option[V] {
+	if t.root == nil {
+		return None[V]()
+	}
+	t.root = t.splay(t.root, key)
+	if !t.compare(key, t.root.key).IsEqual() {
+		return None[V]()
+	}
+	return Some(t.root.value)
+}
+
+func (t *splayTree[K, V]) Put(key K, value V) {
+	if t.root == nil {
+		t.root = &node[K, V]{
+			key:   key,
+			value: value,
+		}
+		t.len++
+		return
+	}
+	t.root = t.splay(t.root, key)
+	order := t.compare(key, t.root.key)
+	if order.IsEqual() {
+		t.root.value = value
+		return
+	}
+	n := &node[K, V]{
+		key:   key,
+		value: value,
+	}
+	if order.IsLess() {
+		n.left = t.root.left
+		n.right = t.root
+		t.root.left = nil
+	} else {
+		n.right = t.root.right
+		n.left = t.root
+		t.root.right = nil
+	}
+	t.root = n
+	t.len++
+}
+
+// Height returns the height of the tree. Splay trees keep no balance
+// information, so this walks the whole tree in O(n).
+func (t *splayTree[K, V]) Height() int {
+	return height(t.root)
+}
+
+func (t *splayTree[K, V]) Min() Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	n := minimum(t.root)
+	return Some(kv.New(n.key, n.value))
+}
+
+func (t *splayTree[K, V]) Max() Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	n := maximum(t.root)
+	return Some(kv.New(n.key, n.value))
+}
+
+func (t *splayTree[K, V]) Floor(key K) Option[kv.Pair[K, V]] {
+	var candidate *node[K, V]
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsEqual():
+			return Some(kv.New(current.key, current.value))
+		case order.IsLess():
+			current = current.left
+		default:
+			candidate = current
+			current = current.right
+		}
+	}
+	if candidate == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(candidate.key, candidate.value))
+}
+
+func (t *splayTree[K, V]) Ceiling(key K) Option[kv.Pair[K, V]] {
+	var candidate *node[K, V]
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsEqual():
+			return Some(kv.New(current.key, current.value))
+		case order.IsGreater():
+			current = current.right
+		default:
+			candidate = current
+			current = current.left
+		}
+	}
+	if candidate == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(candidate.key, candidate.value))
+}
+
+func (t *splayTree[K, V]) Range(opts ...where.WhereOption[K]) iter.Seq2[K, V] {
+	return t.walker().Range(t.compare, opts...)
+}
+
+func (t *splayTree[K, V]) All(opts ...collection.SearchTreeTraversalOption) iter.Seq2[K, V] {
+	return t.walker().All(opts...)
+}
+
+// walker describes the nodes of the tree to the shared traversals.
+func (t *splayTree[K, V]) walker() searchtree.Walker[K, V, *node[K, V]] {
+	return searchtree.Walker[K, V, *node[K, V]]{
+		Root: func() *node[K, V] {
+			return t.root
+		},
+		Left: func(n *node[K, V]) *node[K, V] {
+			return n.left
+		},
+		Right: func(n *node[K, V]) *node[K, V] {
+			return n.right
+		},
+		Entry: func(n *node[K, V]) (K, V) {
+			return n.key, n.value
+		},
+	}
+}
+
+func (t *splayTree[K, V]) Peek(key K) Option[V] {
+	n := t.find(key)
+	if n == nil {
+		return None[V]()
+	}
+	return Some(n.value)
+}
+
+func (t *splayTree[K, V]) find(key K) *node[K, V] {
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsLess():
+			current = current.left
+		case order.IsGreater():
+			current = current.right
+		default:
+			return current
+		}
+	}
+	return nil
+}
+
+// splay performs a top-down splay of the subtree rooted at n and returns the
+// new root: the node holding key, or the last node on its search path.
+func (t *splayTree[K, V]) splay(n *node[K, V], key K) *node[K, V] {
+	var header node[K, V]
+	// left collects nodes smaller than key, right collects nodes larger than key
+	left, right := &header, &header
+	for {
+		order := t.compare(key, n.key)
+		if order.IsLess() {
+			if n.left == nil {
+				break
+			}
+			if t.compare(key, n.left.key).IsLess() {
+				// Zig-zig: rotate right before linking
+				child := n.left
+				n.left = child.right
+				child.right = n
+				n = child
+				if n.left == nil {
+					break
+				}
+			}
+			right.left = n
+			right = n
+			n = n.left
+		} else if order.IsGreater() {
+			if n.right == nil {
+				break
+			}
+			if t.compare(key, n.right.key).IsGreater() {
+				// Zag-zag: rotate left before linking
+				child := n.right
+				n.right = child.left
+				child.left = n
+				n = child
+				if n.right == nil {
+					break
+				}
+			}
+			left.right = n
+			left = n
+			n = n.right
+		} else {
+			break
+		}
+	}
+	// Reassemble the collected trees around the new root
+	left.right = n.left
+	right.left = n.right
+	n.left = header.right
+	n.right = header.left
+	return n
+}
@@ -0,0 +1,29 @@
+package splay
+
+type node[K any, V any] struct {
+	key   K
+	value V
+	left  *node[K, V]
+	right *node[K, V]
+}
+
+func minimum[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func maximum[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+func height[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(height(n.left), height(n.right))
+}
@@ -0,0 +1,129 @@
+package suffixarray
+
+import (
+	"slices"
+
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+// ranks maps each symbol of the texts to its position among the distinct
+// symbols of all the texts, starting at offset.
+func ranks[T any](fn func(a, b T) compare.Order, offset int, texts ...[]T) [][]int {
+	var alphabet []T
+	for _, text := range texts {
+		alphabet = append(alphabet, text...)
+	}
+	order := func(a, b T) int {
+		return fn(a, b).Int()
+	}
+	slices.SortFunc(alphabet, order)
+	alphabet = slices.CompactFunc(alphabet, func(a, b T) bool {
+		return fn(a, b).IsEqual()
+	})
+	res := make([][]int, len(texts))
+	for i, text := range texts {
+		res[i] = make([]int, len(text))
+		for j, symbol := range text {
+			rank, _ := slices.BinarySearchFunc(alphabet, symbol, order)
+			res[i][j] = rank + offset
+		}
+	}
+	return res
+}
+
+// build returns the suffix array of s, whose values lie in [0, k), by prefix
+// doubling. Each round sorts the suffixes by their first 2h symbols using
+// the ranks of the previous round as keys, with two stable counting sort
+// passes, so the whole construction runs in O(n log n).
+func build(s []int, k int) []int {
+	n := len(s)
+	sa := make([]int, n)
+	if n == 0 {
+		return sa
+	}
+	rank := slices.Clone(s)
+	next := make([]int, n)
+	byKey := make([]int, n)
+	for i := range sa {
+		byKey[i] = i
+	}
+	countingSort(sa, byKey, rank, k)
+	for h := 1; h < n; h <<= 1 {
+		// Order by the rank h symbols along, which is -1 past the end
+		var p int
+		for i := n - h; i < n; i++ {
+			byKey[p] = i
+			p++
+		}
+		for _, i := range sa {
+			if i >= h {
+				byKey[p] = i - h
+				p++
+			}
+		}
+		countingSort(sa, byKey, rank, max(k, n))
+		second := func(i int) int {
+			if i+h < n {
+				return rank[i+h]
+			}
+			return -1
+		}
+		classes := 1
+		next[sa[0]] = 0
+		for j := 1; j < n; j++ {
+			a, b := sa[j-1], sa[j]
+			if rank[a] != rank[b] || second(a) != second(b) {
+				classes++
+			}
+			next[b] = classes - 1
+		}
+		rank, next = next, rank
+		if classes == n {
+			break
+		}
+	}
+	return sa
+}
+
+// countingSort writes the positions in order into dst, stably sorted by
+// their key, which lies in [0, k).
+func countingSort(dst, order, key []int, k int) {
+	count := make([]int, k+1)
+	for _, i := range order {
+		count[key[i]+1]++
+	}
+	for c := 1; c <= k; c++ {
+		count[c] += count[c-1]
+	}
+	for _, i := range order {
+		dst[count[key[i]]] = i
+		count[key[i]]++
+	}
+}
+
+// kasai returns the LCP array of s given its suffix array in O(n).
+func kasai(s []int, sa []int) []int {
+	n := len(s)
+	lcp := make([]int, n)
+	position := make([]int, n)
+	for r, i := range sa {
+		position[i] = r
+	}
+	var h int
+	for i := range n {
+		r := position[i]
+		if r == 0 {
+			h = 0
+			continue
+		}
+		j := sa[r-1]
+		for i+h < n && j+h < n && s[i+h] == s[j+h] {
+			h++
+		}
+		lcp[r] = h
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
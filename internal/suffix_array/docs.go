@@ -0,0 +1,4 @@
+// Package suffixarray implements [collection.SuffixArray].
+package suffixarray
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,132 @@
+package suffixarray
+
+import (
+	"iter"
+	"slices"
+	"sort"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+)
+
+type suffixArray[T any] struct {
+	compare func(a, b T) compare.Order
+	text    []T
+	sa      []int
+	lcp     []int
+}
+
+var _ collection.SuffixArray[byte] = (*suffixArray[byte])(nil)
+
+// New returns the suffix array of text, whose symbols are ordered by fn.
+func New[T any](fn func(a, b T) compare.Order, text []T) *suffixArray[T] {
+	s := ranks(fn, 0, text)[0]
+	sa := build(s, len(text))
+	return &suffixArray[T]{
+		compare: fn,
+		text:    slices.Clone(text),
+		sa:      sa,
+		lcp:     kasai(s, sa),
+	}
+}
+
+func (a *suffixArray[T]) Len() int {
+	return len(a.text)
+}
+
+func (a *suffixArray[T]) Suffixes() []int {
+	return slices.Clone(a.sa)
+}
+
+func (a *suffixArray[T]) LCP() []int {
+	return slices.Clone(a.lcp)
+}
+
+func (a *suffixArray[T]) Contains(pattern []T) bool {
+	return a.Count(pattern) > 0
+}
+
+// Count and Lookup treat the empty pattern as occurring at every position
+// from 0 to Len, the end of the text included, which the suffix array alone
+// cannot answer: it has no entry for the empty suffix.
+func (a *suffixArray[T]) Count(pattern []T) int {
+	if len(pattern) == 0 {
+		return len(a.text) + 1
+	}
+	lo, hi := a.bounds(pattern)
+	return hi - lo
+}
+
+func (a *suffixArray[T]) Lookup(pattern []T) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if len(pattern) == 0 {
+			for position := range len(a.text) + 1 {
+				if !yield(position) {
+					return
+				}
+			}
+			return
+		}
+		lo, hi := a.bounds(pattern)
+		positions := slices.Clone(a.sa[lo:hi])
+		slices.Sort(positions)
+		for _, position := range positions {
+			if !yield(position) {
+				return
+			}
+		}
+	}
+}
+
+func (a *suffixArray[T]) LongestRepeatedSubstring() []T {
+	var start, length int
+	for r, l := range a.lcp {
+		if l > length {
+			start, length = a.sa[r], l
+		}
+	}
+	return slices.Clone(a.text[start : start+length])
+}
+
+// LongestCommonSubstring indexes the text and other joined by a separator
+// smaller than every symbol. The answer is the longest common prefix of two
+// suffixes adjacent in sorted order that start on opposite sides of the
+// separator.
+func (a *suffixArray[T]) LongestCommonSubstring(other []T) []T {
+	texts := ranks(a.compare, 1, a.text, other)
+	s := append(append(texts[0], 0), texts[1]...)
+	sa := build(s, len(s)+1)
+	lcp := kasai(s, sa)
+	n := len(a.text)
+	var start, length int
+	for r := 1; r < len(sa); r++ {
+		if (sa[r-1] < n) != (sa[r] < n) && lcp[r] > length {
+			start, length = min(sa[r-1], sa[r]), lcp[r]
+		}
+	}
+	return slices.Clone(a.text[start : start+length])
+}
+
+// bounds returns the range of ranks whose suffixes start with pattern.
+func (a *suffixArray[T]) bounds(pattern []T) (int, int) {
+	// prefix compares the first len(pattern) symbols of the suffix at i with pattern
+	prefix := func(i int) compare.Order {
+		suffix := a.text[i:]
+		for j, symbol := range pattern {
+			if j == len(suffix) {
+				return compare.OrderLess
+			}
+			if order := a.compare(suffix[j], symbol); !order.IsEqual() {
+				return order
+			}
+		}
+		return compare.OrderEqual
+	}
+	lo := sort.Search(len(a.sa), func(r int) bool {
+		return prefix(a.sa[r]).IsGreaterThanOrEqualTo()
+	})
+	hi := sort.Search(len(a.sa), func(r int) bool {
+		return prefix(a.sa[r]).IsGreater()
+	})
+	return lo, hi
+}
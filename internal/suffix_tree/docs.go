@@ -0,0 +1,4 @@
+// Package suffixtree implements [collection.SuffixTree].
+package suffixtree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,222 @@
+package suffixtree
+
+import (
+	"iter"
+	"slices"
+	"sort"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+)
+
+// suffixTree is a generalized suffix tree built with Ukkonen's algorithm over
+// the concatenation of its strings, each followed by a unique terminator.
+// Between appends the tree is implicit: the last remainder suffixes of the
+// current string end inside edges or at inner nodes rather than at leaves.
+type suffixTree[T comparable] struct {
+	text    []symbol[T]
+	starts  []int
+	root    *node[T]
+	leafEnd *int
+	len     int
+
+	activeNode   *node[T]
+	activeEdge   int
+	activeLength int
+	remainder    int
+}
+
+var _ collection.SuffixTree[int] = (*suffixTree[int])(nil)
+
+// New returns an empty suffix tree.
+func New[T comparable]() *suffixTree[T] {
+	t := &suffixTree[T]{
+		starts:  []int{0},
+		leafEnd: new(int),
+	}
+	t.root = t.newNode(0, new(int), -1)
+	t.activeNode = t.root
+	return t
+}
+
+func (t *suffixTree[T]) Len() int {
+	return t.len
+}
+
+func (t *suffixTree[T]) Strings() int {
+	return len(t.starts) - 1
+}
+
+func (t *suffixTree[T]) Append(value T) {
+	t.extend(symbol[T]{value: value})
+	t.len++
+}
+
+// Terminate adds the terminator of the current string. Since it matches
+// nothing else, every pending suffix becomes a leaf, after which the leaves
+// of the string stop growing.
+func (t *suffixTree[T]) Terminate() int {
+	index := t.Strings()
+	t.extend(symbol[T]{terminator: index + 1})
+	t.leafEnd = new(int)
+	t.starts = append(t.starts, len(t.text))
+	return index
+}
+
+func (t *suffixTree[T]) Contains(pattern []T) bool {
+	_, ok := t.find(pattern)
+	return ok
+}
+
+func (t *suffixTree[T]) Occurrences(pattern []T) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		locus, ok := t.find(pattern)
+		if !ok {
+			return
+		}
+		var positions []int
+		locus.leaves(func(position int) bool {
+			// Suffixes made of a lone terminator only match the empty pattern
+			if t.text[position].terminator == 0 {
+				positions = append(positions, position)
+			}
+			return true
+		})
+		// Pending suffixes have no leaf yet and are matched against the text
+		for position := len(t.text) - t.remainder; position < len(t.text); position++ {
+			if t.matchesAt(position, pattern) {
+				positions = append(positions, position)
+			}
+		}
+		slices.Sort(positions)
+		for _, position := range positions {
+			at := t.locate(position)
+			if !yield(at.Key(), at.Value()) {
+				return
+			}
+		}
+	}
+}
+
+func (t *suffixTree[T]) Root() collection.SuffixTreeNode[T] {
+	return t.root
+}
+
+func (t *suffixTree[T]) newNode(start int, end *int, suffix int) *node[T] {
+	return &node[T]{
+		tree:     t,
+		start:    start,
+		end:      end,
+		suffix:   suffix,
+		children: map[symbol[T]]*node[T]{},
+	}
+}
+
+// extend runs one phase of Ukkonen's algorithm, adding s to the text and
+// making every suffix that can no longer stay implicit explicit.
+func (t *suffixTree[T]) extend(s symbol[T]) {
+	t.text = append(t.text, s)
+	position := len(t.text) - 1
+	*t.leafEnd = position + 1
+	t.remainder++
+	var lastInner *node[T]
+	for t.remainder > 0 {
+		if t.activeLength == 0 {
+			t.activeEdge = position
+		}
+		next, ok := t.activeNode.children[t.text[t.activeEdge]]
+		if !ok {
+			leaf := t.newNode(position, t.leafEnd, position-t.remainder+1)
+			leaf.parent = t.activeNode
+			t.activeNode.children[s] = leaf
+			if lastInner != nil {
+				lastInner.link = t.activeNode
+				lastInner = nil
+			}
+		} else {
+			// Walk down when the active point lies beyond this edge
+			if t.activeLength >= next.length() {
+				t.activeEdge += next.length()
+				t.activeLength -= next.length()
+				t.activeNode = next
+				continue
+			}
+			// The suffix is already present, so it and every shorter one stays implicit
+			if t.text[next.start+t.activeLength] == s {
+				if lastInner != nil && t.activeNode != t.root {
+					lastInner.link = t.activeNode
+				}
+				t.activeLength++
+				return
+			}
+			end := next.start + t.activeLength
+			split := t.newNode(next.start, &end, -1)
+			split.parent = t.activeNode
+			t.activeNode.children[t.text[t.activeEdge]] = split
+			leaf := t.newNode(position, t.leafEnd, position-t.remainder+1)
+			leaf.parent = split
+			split.children[s] = leaf
+			next.start = end
+			next.parent = split
+			split.children[t.text[next.start]] = next
+			if lastInner != nil {
+				lastInner.link = split
+			}
+			lastInner = split
+		}
+		t.remainder--
+		if t.activeNode == t.root && t.activeLength > 0 {
+			t.activeLength--
+			t.activeEdge = position - t.remainder + 1
+		} else if t.activeNode != t.root {
+			t.activeNode = t.activeNode.link
+			if t.activeNode == nil {
+				t.activeNode = t.root
+			}
+		}
+	}
+}
+
+// find walks pattern down from the root and returns the highest node whose
+// path starts with pattern.
+func (t *suffixTree[T]) find(pattern []T) (*node[T], bool) {
+	current := t.root
+	for len(pattern) > 0 {
+		next, ok := current.children[symbol[T]{value: pattern[0]}]
+		if !ok {
+			return nil, false
+		}
+		for _, s := range t.text[next.start:*next.end] {
+			if len(pattern) == 0 {
+				break
+			}
+			if s != (symbol[T]{value: pattern[0]}) {
+				return nil, false
+			}
+			pattern = pattern[1:]
+		}
+		current = next
+	}
+	return current, true
+}
+
+func (t *suffixTree[T]) matchesAt(position int, pattern []T) bool {
+	if position+len(pattern) > len(t.text) {
+		return false
+	}
+	for i, value := range pattern {
+		if t.text[position+i] != (symbol[T]{value: value}) {
+			return false
+		}
+	}
+	return true
+}
+
+// locate converts a position in the concatenated text into a string index
+// and an offset within that string.
+func (t *suffixTree[T]) locate(position int) kv.Pair[int, int] {
+	index := sort.Search(len(t.starts), func(i int) bool {
+		return t.starts[i] > position
+	}) - 1
+	return kv.New(index, position-t.starts[index])
+}
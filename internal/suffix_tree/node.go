@@ -0,0 +1,94 @@
+package suffixtree
+
+import (
+	"iter"
+	"maps"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+)
+
+// symbol is a symbol of the concatenated text. Each string ends with a
+// terminator whose id is the string index plus one, making it unique.
+type symbol[T comparable] struct {
+	value      T
+	terminator int
+}
+
+// node is reached by the edge labelled text[start:*end]. The leaves of a
+// string share one end, which grows with every symbol appended to the
+// string until it is terminated.
+type node[T comparable] struct {
+	tree     *suffixTree[T]
+	start    int
+	end      *int
+	suffix   int
+	children map[symbol[T]]*node[T]
+	link     *node[T]
+	parent   *node[T]
+}
+
+// compile time interface guard check
+var _ collection.SuffixTreeNode[int] = (*node[int])(nil)
+
+func (n *node[T]) Children() iter.Seq[collection.SuffixTreeNode[T]] {
+	return func(yield func(collection.SuffixTreeNode[T]) bool) {
+		children := slices.SortedFunc(maps.Values(n.children), func(a, b *node[T]) int {
+			return a.start - b.start
+		})
+		for _, child := range children {
+			if !yield(child) {
+				return
+			}
+		}
+	}
+}
+
+func (n *node[T]) Parent() Option[collection.SuffixTreeNode[T]] {
+	if n.parent == nil {
+		return None[collection.SuffixTreeNode[T]]()
+	}
+	var res collection.SuffixTreeNode[T] = n.parent
+	return Some(res)
+}
+
+func (n *node[T]) Label() []T {
+	res := make([]T, 0, n.length())
+	for _, s := range n.tree.text[n.start:*n.end] {
+		if s.terminator == 0 {
+			res = append(res, s.value)
+		}
+	}
+	return res
+}
+
+func (n *node[T]) IsLeaf() bool {
+	return n.suffix >= 0
+}
+
+func (n *node[T]) Suffix() Option[kv.Pair[int, int]] {
+	if n.suffix < 0 {
+		return None[kv.Pair[int, int]]()
+	}
+	return Some(n.tree.locate(n.suffix))
+}
+
+func (n *node[T]) length() int {
+	return *n.end - n.start
+}
+
+// leaves yields the text position where each suffix below n starts.
+func (n *node[T]) leaves(yield func(int) bool) bool {
+	if n.suffix >= 0 {
+		return yield(n.suffix)
+	}
+	for _, child := range n.children {
+		if !child.leaves(yield) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,5 @@
+// Package treap implements a keyed treap satisfying [collection.SearchTree]
+// and an implicit treap satisfying [collection.ImplicitTreap].
+package treap
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,233 @@
+package treap
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/where"
+)
+
+type implicitTreap[T any] struct {
+	equal  func(a, b T) bool
+	random *rand.Rand
+	root   *implicitNode[T]
+}
+
+var _ collection.ImplicitTreap[int] = (*implicitTreap[int])(nil)
+
+// NewImplicit returns an empty implicit treap that compares elements with
+// equal. Node priorities are drawn from a generator seeded with seed.
+func NewImplicit[T any](equal func(a, b T) bool, seed uint64) *implicitTreap[T] {
+	return &implicitTreap[T]{
+		equal:  equal,
+		random: rand.New(rand.NewPCG(seed, seed)),
+		root:   nil,
+	}
+}
+
+func (t *implicitTreap[T]) Len() int {
+	return size(t.root)
+}
+
+func (t *implicitTreap[T]) Contains(element T) bool {
+	return t.FindIndex(func(item T) bool {
+		return t.equal(item, element)
+	}).IsSome()
+}
+
+func (t *implicitTreap[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+func (t *implicitTreap[T]) Clear() {
+	t.root = nil
+}
+
+func (t *implicitTreap[T]) Any(pred predicate.Predicate[T]) bool {
+	return t.FindIndex(pred).IsSome()
+}
+
+func (t *implicitTreap[T]) Count(pred predicate.Predicate[T]) int {
+	var count int
+	for value := range t.Values() {
+		if pred(value) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *implicitTreap[T]) Every(pred predicate.Predicate[T]) bool {
+	for value := range t.Values() {
+		if !pred(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *implicitTreap[T]) ForEach(fn func(T)) {
+	for value := range t.Values() {
+		fn(value)
+	}
+}
+
+func (t *implicitTreap[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		var index int
+		for value := range t.Values() {
+			if !yield(index, value) {
+				return
+			}
+			index++
+		}
+	}
+}
+
+func (t *implicitTreap[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		values(t.root, false, yield)
+	}
+}
+
+func (t *implicitTreap[T]) Find(pred predicate.Predicate[T]) Option[T] {
+	for value := range t.Values() {
+		if pred(value) {
+			return Some(value)
+		}
+	}
+	return None[T]()
+}
+
+func (t *implicitTreap[T]) FindIndex(pred predicate.Predicate[T]) Option[int] {
+	for index, value := range t.All() {
+		if pred(value) {
+			return Some(index)
+		}
+	}
+	return None[int]()
+}
+
+// Get returns the element at the given index in expected O(log n).
+func (t *implicitTreap[T]) Get(index int) Option[T] {
+	if index < 0 || index >= t.Len() {
+		return None[T]()
+	}
+	var flipped bool
+	current := t.root
+	for {
+		flipped = flipped != current.reversed
+		left, right := current.left, current.right
+		if flipped {
+			left, right = right, left
+		}
+		switch {
+		case index < size(left):
+			current = left
+		case index == size(left):
+			return Some(current.value)
+		default:
+			index -= size(left) + 1
+			current = right
+		}
+	}
+}
+
+func (t *implicitTreap[T]) Retain(pred predicate.Predicate[T]) {
+	var retained []T
+	for value := range t.Values() {
+		if pred(value) {
+			retained = append(retained, value)
+		}
+	}
+	t.rebuild(retained)
+}
+
+func (t *implicitTreap[T]) Sort(fn func(a, b T) compare.Order) {
+	sorted := slices.Collect(t.Values())
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		return fn(a, b).Int()
+	})
+	t.rebuild(sorted)
+}
+
+// Insert adds item at index, shifting later elements right. Index Len()
+// appends. Returns false if index is outside [0, Len()].
+func (t *implicitTreap[T]) Insert(index int, item T) bool {
+	if index < 0 || index > t.Len() {
+		return false
+	}
+	left, right := splitAt(t.root, index)
+	t.root = mergeImplicit(mergeImplicit(left, t.newNode(item)), right)
+	return true
+}
+
+func (t *implicitTreap[T]) RemoveAt(index int) Option[T] {
+	if index < 0 || index >= t.Len() {
+		return None[T]()
+	}
+	left, rest := splitAt(t.root, index)
+	removed, right := splitAt(rest, 1)
+	t.root = mergeImplicit(left, right)
+	return Some(removed.value)
+}
+
+func (t *implicitTreap[T]) Split(index int) collection.ImplicitTreap[T] {
+	index = min(max(index, 0), t.Len())
+	left, right := splitAt(t.root, index)
+	t.root = left
+	return &implicitTreap[T]{
+		equal:  t.equal,
+		random: rand.New(rand.NewPCG(t.random.Uint64(), t.random.Uint64())),
+		root:   right,
+	}
+}
+
+// Concat appends other in expected O(log n) when it is an implicit treap from
+// this package, and in O(m log n) otherwise.
+func (t *implicitTreap[T]) Concat(other collection.ImplicitTreap[T]) {
+	if other, ok := other.(*implicitTreap[T]); ok {
+		if other != t {
+			t.root = mergeImplicit(t.root, other.root)
+			other.root = nil
+		}
+		return
+	}
+	for value := range other.Values() {
+		t.Insert(t.Len(), value)
+	}
+	other.Clear()
+}
+
+func (t *implicitTreap[T]) Reverse(opts ...where.WhereOption[int]) {
+	start, end := where.Indices(t.Len(), opts...)
+	if end-start < 2 {
+		return
+	}
+	left, rest := splitAt(t.root, start)
+	middle, right := splitAt(rest, end-start)
+	middle.reversed = !middle.reversed
+	t.root = mergeImplicit(mergeImplicit(left, middle), right)
+}
+
+func (t *implicitTreap[T]) newNode(value T) *implicitNode[T] {
+	return &implicitNode[T]{
+		value:    value,
+		priority: t.random.Uint64(),
+		size:     1,
+	}
+}
+
+// rebuild replaces the contents of the treap with items in order.
+func (t *implicitTreap[T]) rebuild(items []T) {
+	t.root = nil
+	for _, item := range items {
+		t.root = mergeImplicit(t.root, t.newNode(item))
+	}
+}
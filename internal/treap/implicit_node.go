@@ -0,0 +1,93 @@
+package treap
+
+// implicitNode is ordered by position, which is derived from subtree sizes,
+// and by priority as a max-heap. A reversed node has a pending reversal of
+// its subtree that has not yet been pushed to its children.
+type implicitNode[T any] struct {
+	value    T
+	priority uint64
+	size     int
+	reversed bool
+	left     *implicitNode[T]
+	right    *implicitNode[T]
+}
+
+func size[T any](n *implicitNode[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *implicitNode[T]) update() {
+	n.size = 1 + size(n.left) + size(n.right)
+}
+
+// push applies a pending reversal to this node and hands it down to the children.
+func (n *implicitNode[T]) push() {
+	if !n.reversed {
+		return
+	}
+	n.left, n.right = n.right, n.left
+	if n.left != nil {
+		n.left.reversed = !n.left.reversed
+	}
+	if n.right != nil {
+		n.right.reversed = !n.right.reversed
+	}
+	n.reversed = false
+}
+
+// splitAt divides the subtree rooted at n into its first index elements and the rest.
+func splitAt[T any](n *implicitNode[T], index int) (*implicitNode[T], *implicitNode[T]) {
+	if n == nil {
+		return nil, nil
+	}
+	n.push()
+	if size(n.left) < index {
+		left, right := splitAt(n.right, index-size(n.left)-1)
+		n.right = left
+		n.update()
+		return n, right
+	}
+	left, right := splitAt(n.left, index)
+	n.left = right
+	n.update()
+	return left, n
+}
+
+// mergeImplicit joins two treaps, placing every element of left before every element of right.
+func mergeImplicit[T any](left, right *implicitNode[T]) *implicitNode[T] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority > right.priority:
+		left.push()
+		left.right = mergeImplicit(left.right, right)
+		left.update()
+		return left
+	default:
+		right.push()
+		right.left = mergeImplicit(left, right.left)
+		right.update()
+		return right
+	}
+}
+
+// values yields the subtree rooted at n in sequence order. Pending reversals
+// are tracked in flipped rather than pushed, so reading never mutates.
+func values[T any](n *implicitNode[T], flipped bool, yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	flipped = flipped != n.reversed
+	first, second := n.left, n.right
+	if flipped {
+		first, second = second, first
+	}
+	return values(first, flipped, yield) &&
+		yield(n.value) &&
+		values(second, flipped, yield)
+}
@@ -0,0 +1,276 @@
+package treap
+
+import (
+	"iter"
+	"math/rand/v2"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/predicate"
+	"codeberg.org/yaadata/bina/core/where"
+	searchtree "codeberg.org/yaadata/bina/internal/search_tree"
+)
+
+type keyedTreap[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	random  *rand.Rand
+	root    *keyedNode[K, V]
+	len     int
+}
+
+var _ collection.SearchTree[int, int] = (*keyedTreap[int, int])(nil)
+
+// NewKeyed returns an empty treap whose keys are ordered by fn. Node
+// priorities are drawn from a generator seeded with seed, so equal seeds and
+// equal operations produce identically shaped trees.
+func NewKeyed[K any, V any](fn func(a, b K) compare.Order, seed uint64) *keyedTreap[K, V] {
+	return &keyedTreap[K, V]{
+		compare: fn,
+		random:  rand.New(rand.NewPCG(seed, seed)),
+		root:    nil,
+		len:     0,
+	}
+}
+
+func (t *keyedTreap[K, V]) Len() int {
+	return t.len
+}
+
+func (t *keyedTreap[K, V]) Contains(element K) bool {
+	return t.find(element) != nil
+}
+
+func (t *keyedTreap[K, V]) IsEmpty() bool {
+	return t.len == 0
+}
+
+func (t *keyedTreap[K, V]) Clear() {
+	t.root = nil
+	t.len = 0
+}
+
+func (t *keyedTreap[K, V]) Any(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *keyedTreap[K, V]) Every(pred predicate.Predicate[kv.Pair[K, V]]) bool {
+	for key, value := range t.All() {
+		if !pred(kv.New(key, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *keyedTreap[K, V]) Count(pred predicate.Predicate[kv.Pair[K, V]]) int {
+	var count int
+	for key, value := range t.All() {
+		if pred(kv.New(key, value)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *keyedTreap[K, V]) ForEach(fn func(pair kv.Pair[K, V])) {
+	for key, value := range t.All() {
+		fn(kv.New(key, value))
+	}
+}
+
+func (t *keyedTreap[K, V]) Delete(key K) Option[V] {
+	var deleted Option[V]
+	t.root, deleted = t.delete(t.root, key)
+	if deleted.IsSome() {
+		t.len--
+	}
+	return deleted
+}
+
+func (t *keyedTreap[K, V]) Get(key K) Option[V] {
+	n := t.find(key)
+	if n == nil {
+		return None[V]()
+	}
+	return Some(n.value)
+}
+
+func (t *keyedTreap[K, V]) Put(key K, value V) {
+	if n := t.find(key); n != nil {
+		n.value = value
+		return
+	}
+	left, right := t.split(t.root, key)
+	n := &keyedNode[K, V]{
+		key:      key,
+		value:    value,
+		priority: t.random.Uint64(),
+	}
+	t.root = mergeKeyed(mergeKeyed(left, n), right)
+	t.len++
+}
+
+// Height returns the height of the tree, which is O(log n) in expectation.
+// It walks the whole tree, so it runs in O(n).
+func (t *keyedTreap[K, V]) Height() int {
+	return keyedHeight(t.root)
+}
+
+func (t *keyedTreap[K, V]) Min() Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	n := keyedMinimum(t.root)
+	return Some(kv.New(n.key, n.value))
+}
+
+func (t *keyedTreap[K, V]) Max() Option[kv.Pair[K, V]] {
+	if t.root == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	n := keyedMaximum(t.root)
+	return Some(kv.New(n.key, n.value))
+}
+
+func (t *keyedTreap[K, V]) Floor(key K) Option[kv.Pair[K, V]] {
+	var candidate *keyedNode[K, V]
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsEqual():
+			return Some(kv.New(current.key, current.value))
+		case order.IsLess():
+			current = current.left
+		default:
+			candidate = current
+			current = current.right
+		}
+	}
+	if candidate == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(candidate.key, candidate.value))
+}
+
+func (t *keyedTreap[K, V]) Ceiling(key K) Option[kv.Pair[K, V]] {
+	var candidate *keyedNode[K, V]
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsEqual():
+			return Some(kv.New(current.key, current.value))
+		case order.IsGreater():
+			current = current.right
+		default:
+			candidate = current
+			current = current.left
+		}
+	}
+	if candidate == nil {
+		return None[kv.Pair[K, V]]()
+	}
+	return Some(kv.New(candidate.key, candidate.value))
+}
+
+func (t *keyedTreap[K, V]) Range(opts ...where.WhereOption[K]) iter.Seq2[K, V] {
+	return t.walker().Range(t.compare, opts...)
+}
+
+func (t *keyedTreap[K, V]) All(opts ...collection.SearchTreeTraversalOption) iter.Seq2[K, V] {
+	return t.walker().All(opts...)
+}
+
+// walker describes the nodes of the tree to the shared traversals.
+func (t *keyedTreap[K, V]) walker() searchtree.Walker[K, V, *keyedNode[K, V]] {
+	return searchtree.Walker[K, V, *keyedNode[K, V]]{
+		Root: func() *keyedNode[K, V] {
+			return t.root
+		},
+		Left: func(n *keyedNode[K, V]) *keyedNode[K, V] {
+			return n.left
+		},
+		Right: func(n *keyedNode[K, V]) *keyedNode[K, V] {
+			return n.right
+		},
+		Entry: func(n *keyedNode[K, V]) (K, V) {
+			return n.key, n.value
+		},
+	}
+}
+
+func (t *keyedTreap[K, V]) find(key K) *keyedNode[K, V] {
+	current := t.root
+	for current != nil {
+		order := t.compare(key, current.key)
+		switch {
+		case order.IsLess():
+			current = current.left
+		case order.IsGreater():
+			current = current.right
+		default:
+			return current
+		}
+	}
+	return nil
+}
+
+// split divides the subtree rooted at n into the keys less than key and the
+// keys greater than or equal to key.
+func (t *keyedTreap[K, V]) split(n *keyedNode[K, V], key K) (*keyedNode[K, V], *keyedNode[K, V]) {
+	if n == nil {
+		return nil, nil
+	}
+	if t.compare(n.key, key).IsLess() {
+		left, right := t.split(n.right, key)
+		n.right = left
+		return n, right
+	}
+	left, right := t.split(n.left, key)
+	n.left = right
+	return left, n
+}
+
+// delete removes key from the subtree rooted at n and returns the new subtree
+// root along with the removed value.
+func (t *keyedTreap[K, V]) delete(n *keyedNode[K, V], key K) (*keyedNode[K, V], Option[V]) {
+	if n == nil {
+		return nil, None[V]()
+	}
+	var deleted Option[V]
+	order := t.compare(key, n.key)
+	switch {
+	case order.IsLess():
+		n.left, deleted = t.delete(n.left, key)
+	case order.IsGreater():
+		n.right, deleted = t.delete(n.right, key)
+	default:
+		return mergeKeyed(n.left, n.right), Some(n.value)
+	}
+	return n, deleted
+}
+
+// mergeKeyed joins two treaps where every key of left is less than every key of right.
+func mergeKeyed[K any, V any](left, right *keyedNode[K, V]) *keyedNode[K, V] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority > right.priority:
+		left.right = mergeKeyed(left.right, right)
+		return left
+	default:
+		right.left = mergeKeyed(left, right.left)
+		return right
+	}
+}
@@ -0,0 +1,31 @@
+package treap
+
+// keyedNode is ordered by key as a search tree and by priority as a max-heap.
+type keyedNode[K any, V any] struct {
+	key      K
+	value    V
+	priority uint64
+	left     *keyedNode[K, V]
+	right    *keyedNode[K, V]
+}
+
+func keyedMinimum[K any, V any](n *keyedNode[K, V]) *keyedNode[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func keyedMaximum[K any, V any](n *keyedNode[K, V]) *keyedNode[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+func keyedHeight[K any, V any](n *keyedNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(keyedHeight(n.left), keyedHeight(n.right))
+}
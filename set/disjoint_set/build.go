@@ -0,0 +1,71 @@
+package disjointset
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/hashable"
+	disjointset "codeberg.org/yaadata/bina/internal/disjoint_set"
+)
+
+// NewBuiltinBuilder returns a [Builder] for creating a [collection.DisjointSet] with comparable elements.
+func NewBuiltinBuilder[T comparable]() Builder[T, collection.DisjointSet[T], *builtinBuilder[T]] {
+	return &builtinBuilder[T]{
+		from:     None[[]T](),
+		capacity: None[int](),
+	}
+}
+
+type builtinBuilder[T comparable] struct {
+	from     Option[[]T]
+	capacity Option[int]
+}
+
+func (b *builtinBuilder[T]) From(items ...T) *builtinBuilder[T] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *builtinBuilder[T]) Capacity(cap int) *builtinBuilder[T] {
+	b.capacity = Some(cap)
+	return b
+}
+
+func (b *builtinBuilder[T]) Build() collection.DisjointSet[T] {
+	d := disjointset.DisjointSetFromBuiltin[T](b.capacity.UnwrapOrDefault())
+	for _, item := range b.from.UnwrapOrDefault() {
+		d.MakeSet(item)
+	}
+	return d
+}
+
+// NewHashableBuilder returns a [Builder] for creating a [collection.DisjointSet] with [hashable.Hashable] elements.
+func NewHashableBuilder[K comparable, T hashable.Hashable[K]]() Builder[T, collection.DisjointSet[T], *hashableBuilder[K, T]] {
+	return &hashableBuilder[K, T]{
+		from:     None[[]T](),
+		capacity: None[int](),
+	}
+}
+
+type hashableBuilder[K comparable, T hashable.Hashable[K]] struct {
+	from     Option[[]T]
+	capacity Option[int]
+}
+
+func (b *hashableBuilder[K, T]) From(items ...T) *hashableBuilder[K, T] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *hashableBuilder[K, T]) Capacity(cap int) *hashableBuilder[K, T] {
+	b.capacity = Some(cap)
+	return b
+}
+
+func (b *hashableBuilder[K, T]) Build() collection.DisjointSet[T] {
+	d := disjointset.DisjointSetFromHashable[K, T](b.capacity.UnwrapOrDefault())
+	for _, item := range b.from.UnwrapOrDefault() {
+		d.MakeSet(item)
+	}
+	return d
+}
@@ -0,0 +1,16 @@
+package disjointset
+
+import "codeberg.org/yaadata/bina/core/collection"
+
+// Builder defines the fluent interface for constructing disjoint sets.
+// Use [NewBuiltinBuilder] for comparable types or [NewHashableBuilder]
+// for types implementing the Hashable interface.
+type Builder[T any, Target collection.DisjointSet[T], Self Builder[T, Target, Self]] interface {
+	// Build constructs and returns the target disjoint set.
+	Build() Target
+	// Capacity sets the initial capacity hint for the underlying storage.
+	Capacity(cap int) Self
+	// From adds each of the given values as a set of its own.
+	// Duplicate values are ignored.
+	From(values ...T) Self
+}
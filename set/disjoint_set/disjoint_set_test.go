@@ -0,0 +1,253 @@
+package disjointset_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	disjointset "codeberg.org/yaadata/bina/set/disjoint_set"
+)
+
+type Record struct {
+	ID   int
+	Name string
+}
+
+func (r Record) Hash() int {
+	return r.ID
+}
+
+// groups returns the sorted members of every group, in iteration order.
+func groups[T int | string](d collection.DisjointSet[T]) [][]T {
+	var res [][]T
+	for group := range d.Groups() {
+		res = append(res, slices.Sorted(group.Values()))
+	}
+	return res
+}
+
+func TestDisjointSetFromBuiltin(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		d := disjointset.NewBuiltinBuilder[int]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, d.Len())
+		must.Eq(t, 0, d.SetCount())
+		must.True(t, d.IsEmpty())
+		must.True(t, d.Find(1).IsNone())
+	})
+
+	t.Run("Can build from items", func(t *testing.T) {
+		// ========= [A]rrange =========
+		d := disjointset.NewBuiltinBuilder[int]().
+			Capacity(4).
+			From(1, 2, 3, 2).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 3, d.Len())
+		must.Eq(t, 3, d.SetCount())
+		must.Eq(t, []int{1, 2, 3}, slices.Collect(d.Values()))
+		must.Eq(t, 2, d.Find(2).Unwrap())
+	})
+
+	t.Run("MakeSet works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		d := disjointset.NewBuiltinBuilder[string]().
+			Build()
+		// ========= [A]ct     =========
+		added := d.MakeSet("a")
+		duplicate := d.MakeSet("a")
+		// ========= [A]ssert  =========
+		must.True(t, added)
+		must.False(t, duplicate)
+		must.Eq(t, 1, d.SetCount())
+		must.Eq(t, 1, d.SetSize("a"))
+	})
+
+	t.Run("Union works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		d := disjointset.NewBuiltinBuilder[int]().
+			From(1, 2, 3, 4, 5, 6).
+			Build()
+		// ========= [A]ct     =========
+		merged := d.Union(1, 2)
+		d.Union(3, 4)
+		d.Union(2, 4)
+		again := d.Union(1, 3)
+
+		// SCENARIO: return values
+		t.Run("Union - result", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, merged)
+			must.False(t, again)
+		})
+		// SCENARIO: Find agrees within a set
+		t.Run("Find", func(t *testing.T) {
+			// ========= [A]rrange =========
+			representative := d.Find(1).Unwrap()
+			// ========= [A]ssert  =========
+			for _, element := range []int{2, 3, 4} {
+				must.Eq(t, representative, d.Find(element).Unwrap())
+			}
+			must.Eq(t, 5, d.Find(5).Unwrap())
+		})
+		// SCENARIO: Connected
+		t.Run("Connected", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, d.Connected(1, 4))
+			must.False(t, d.Connected(1, 5))
+			must.False(t, d.Connected(1, 7))
+		})
+		// SCENARIO: sizes and counts
+		t.Run("SetSize and SetCount", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 4, d.SetSize(3))
+			must.Eq(t, 1, d.SetSize(6))
+			must.Eq(t, 0, d.SetSize(7))
+			must.Eq(t, 3, d.SetCount())
+		})
+		// SCENARIO: Groups
+		t.Run("Groups", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, [][]int{{1, 2, 3, 4}, {5}, {6}}, groups(d))
+		})
+	})
+
+	t.Run("Union adds missing elements", func(t *testing.T) {
+		// ========= [A]rrange =========
+		d := disjointset.NewBuiltinBuilder[string]().
+			Build()
+		// ========= [A]ct     =========
+		merged := d.Union("x", "y")
+		d.Union("z", "z")
+		// ========= [A]ssert  =========
+		must.True(t, merged)
+		must.Eq(t, 3, d.Len())
+		must.Eq(t, 2, d.SetCount())
+		must.Eq(t, [][]string{{"x", "y"}, {"z"}}, groups(d))
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		d := disjointset.NewBuiltinBuilder[int]().
+			From(1, 2, 3, 4).
+			Build()
+		d.Union(1, 2)
+		even := func(element int) bool {
+			return element%2 == 0
+		}
+
+		// SCENARIO: Contains
+		t.Run("Contains", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, d.Contains(3))
+			must.False(t, d.Contains(5))
+		})
+		// SCENARIO: Aggregate
+		t.Run("Aggregate", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var sum int
+			d.ForEach(func(element int) {
+				sum += element
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 10, sum)
+			must.True(t, d.Any(even))
+			must.False(t, d.Every(even))
+			must.Eq(t, 2, d.Count(even))
+		})
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]ct     =========
+			d.Clear()
+			d.MakeSet(9)
+			// ========= [A]ssert  =========
+			must.Eq(t, 1, d.Len())
+			must.Eq(t, 1, d.SetCount())
+			must.False(t, d.Contains(1))
+			must.Eq(t, 9, d.Find(9).Unwrap())
+		})
+	})
+
+	t.Run("Random unions stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		const size = 500
+		rng := rand.New(rand.NewPCG(3, 9))
+		d := disjointset.NewBuiltinBuilder[int]().Build()
+		// label is a brute-force partition: elements share a label when connected.
+		label := make([]int, size)
+		for element := range label {
+			label[element] = element
+			d.MakeSet(element)
+		}
+		// ========= [A]ct     =========
+		for range 400 {
+			a, b := rng.IntN(size), rng.IntN(size)
+			from, to := label[a], label[b]
+			must.Eq(t, from != to, d.Union(a, b))
+			for element := range label {
+				if label[element] == from {
+					label[element] = to
+				}
+			}
+		}
+		// ========= [A]ssert  =========
+		counts := map[int]int{}
+		for _, l := range label {
+			counts[l]++
+		}
+		must.Eq(t, len(counts), d.SetCount())
+		for range 2_000 {
+			a, b := rng.IntN(size), rng.IntN(size)
+			must.Eq(t, label[a] == label[b], d.Connected(a, b))
+			must.Eq(t, counts[label[a]], d.SetSize(a))
+		}
+		var total int
+		for group := range d.Groups() {
+			total += group.Len()
+		}
+		must.Eq(t, size, total)
+	})
+}
+
+func TestDisjointSetFromHashable(t *testing.T) {
+	t.Run("Union works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		d := disjointset.NewHashableBuilder[int, Record]().
+			From(Record{1, "ada"}, Record{2, "grace"}, Record{3, "alan"}).
+			Build()
+		// ========= [A]ct     =========
+		// Records with the same ID are the same element, whatever their name.
+		merged := d.Union(Record{1, "Ada"}, Record{3, "Alan"})
+		// ========= [A]ssert  =========
+		must.True(t, merged)
+		must.Eq(t, 3, d.Len())
+		must.Eq(t, 2, d.SetCount())
+		must.True(t, d.Connected(Record{1, "ada"}, Record{3, "alan"}))
+		must.Eq(t, 2, d.SetSize(Record{3, ""}))
+	})
+
+	t.Run("Groups works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		d := disjointset.NewHashableBuilder[int, Record]().
+			From(Record{1, "a"}, Record{2, "b"}, Record{3, "c"}, Record{4, "d"}).
+			Build()
+		d.Union(Record{2, "b"}, Record{4, "d"})
+		// ========= [A]ct     =========
+		var actual [][]int
+		for group := range d.Groups() {
+			var ids []int
+			for record := range group.Values() {
+				ids = append(ids, record.ID)
+			}
+			slices.Sort(ids)
+			actual = append(actual, ids)
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, [][]int{{1}, {2, 4}, {3}}, actual)
+	})
+}
@@ -0,0 +1,14 @@
+// Package disjointset implements builders for [collection.DisjointSet].
+//
+// Grouping duplicate records is a matter of one Union per duplicate pair:
+//
+//	records := disjointset.NewBuiltinBuilder[string]().From(ids...).Build()
+//	for _, pair := range duplicates {
+//		records.Union(pair.Key(), pair.Value())
+//	}
+//	for group := range records.Groups() {
+//		fmt.Println(group.Len())
+//	}
+package disjointset
+
+import _ "codeberg.org/yaadata/bina/core/collection"
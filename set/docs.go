@@ -2,6 +2,8 @@
 // Provides builders for initiating
 // - Hash Sets (see [collection.Set])
 // - Ordered Hash Sets (see [collection.OrderedSet])
+// - Roaring Bitmaps (see [collection.Bitmap])
+// - Disjoint Sets (see [collection.DisjointSet])
 package set
 
 import _ "codeberg.org/yaadata/bina/core/collection"
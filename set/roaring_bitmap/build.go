@@ -0,0 +1,46 @@
+package roaringbitmap
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	roaringbitmap "codeberg.org/yaadata/bina/internal/roaring_bitmap"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.Bitmap].
+func NewBuilder() Builder[collection.Bitmap, *bitmapBuilder] {
+	return &bitmapBuilder{
+		from:        None[[]uint32](),
+		runOptimize: false,
+	}
+}
+
+type bitmapBuilder struct {
+	from        Option[[]uint32]
+	runOptimize bool
+}
+
+func (b *bitmapBuilder) From(items ...uint32) *bitmapBuilder {
+	b.from = Some(items)
+	return b
+}
+
+// Capacity is accepted for parity with other set builders. Roaring bitmaps
+// allocate containers lazily, so the hint is ignored.
+func (b *bitmapBuilder) Capacity(cap int) *bitmapBuilder {
+	return b
+}
+
+func (b *bitmapBuilder) RunOptimize() *bitmapBuilder {
+	b.runOptimize = true
+	return b
+}
+
+func (b *bitmapBuilder) Build() collection.Bitmap {
+	s := roaringbitmap.New()
+	s.Extend(b.from.UnwrapOrDefault()...)
+	if b.runOptimize {
+		s.RunOptimize()
+	}
+	return s
+}
@@ -0,0 +1,17 @@
+package roaringbitmap
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/set/builder"
+)
+
+// Builder defines the fluent interface for constructing roaring bitmaps.
+// Use [NewBuilder] to obtain one.
+type Builder[Target collection.Bitmap, Self Builder[Target, Self]] interface {
+	builder.BaseBuilder[uint32, Target, Self]
+
+	// RunOptimize converts containers to run-length encoding on Build
+	// wherever that is smaller.
+	// Returns Self for method chaining.
+	RunOptimize() Self
+}
@@ -0,0 +1,9 @@
+// Package roaringbitmap
+// Implements builders for [collection.Bitmap]
+//
+// Values are split into 16-bit chunks and each chunk is stored in whichever
+// container is smallest: a sorted array for sparse chunks, a bitmap for dense
+// chunks, or run-length encoding for long runs of consecutive values.
+package roaringbitmap
+
+import _ "codeberg.org/yaadata/bina/core/collection"
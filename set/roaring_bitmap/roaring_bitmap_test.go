@@ -0,0 +1,491 @@
+package roaringbitmap_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	hashset "codeberg.org/yaadata/bina/set/hashset"
+	roaringbitmap "codeberg.org/yaadata/bina/set/roaring_bitmap"
+)
+
+func TestRoaringBitmap(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		set := roaringbitmap.NewBuilder().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, set.Len())
+	})
+
+	t.Run("Can build with capacity", func(t *testing.T) {
+		// ========= [A]rrange =========
+		set := roaringbitmap.NewBuilder().
+			Capacity(10).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, set.Len())
+	})
+
+	t.Run("Can build from items with duplicates", func(t *testing.T) {
+		// ========= [A]rrange =========
+		set := roaringbitmap.NewBuilder().
+			From(1, 2, 2, 3, 3, 3).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 3, set.Len())
+		must.Eq(t, []uint32{1, 2, 3}, slices.Collect(set.Values()))
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		set := roaringbitmap.NewBuilder().
+			From(1, 70_000, 4_000_000_000).
+			Build()
+
+		// SCENARIO: Len
+		t.Run("Len", func(t *testing.T) {
+			// ========= [A]ct     =========
+			length := set.Len()
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, length)
+		})
+
+		// SCENARIO: Contains
+		t.Run("Contains - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := set.Contains(70_001)
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+		t.Run("Contains - true", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := set.Contains(4_000_000_000)
+			// ========= [A]ssert  =========
+			must.True(t, actual)
+		})
+
+		// SCENARIO: IsEmpty
+		t.Run("IsEmpty - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := set.IsEmpty()
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().From(1, 2, 3).Build()
+			// ========= [A]ct     =========
+			set.Clear()
+			// ========= [A]ssert  =========
+			must.Eq(t, 0, set.Len())
+			must.True(t, set.IsEmpty())
+		})
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		set := roaringbitmap.NewBuilder().
+			From(1, 2, 3, 4, 5).
+			Build()
+
+		// SCENARIO: Any
+		t.Run("Any - False", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := set.Any(func(item uint32) bool {
+				return item > 10
+			})
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+		t.Run("Any - True", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := set.Any(func(item uint32) bool {
+				return item > 3
+			})
+			// ========= [A]ssert  =========
+			must.True(t, actual)
+		})
+
+		// SCENARIO: Count
+		t.Run("Count", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := set.Count(func(item uint32) bool {
+				return item%2 == 0
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, actual)
+		})
+
+		// SCENARIO: Every
+		t.Run("Every - True", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := set.Every(func(item uint32) bool {
+				return item < 10
+			})
+			// ========= [A]ssert  =========
+			must.True(t, actual)
+		})
+		t.Run("Every - False", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := set.Every(func(item uint32) bool {
+				return item > 3
+			})
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: ForEach
+		t.Run("ForEach", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var sum uint32
+			set.ForEach(func(item uint32) {
+				sum += item
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 15, sum)
+		})
+	})
+
+	t.Run("Set methods work", func(t *testing.T) {
+		// SCENARIO: Add
+		t.Run("Add", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().From(1).Build()
+			// ========= [A]ct     =========
+			added := set.Add(2)
+			duplicate := set.Add(1)
+			// ========= [A]ssert  =========
+			must.True(t, added)
+			must.False(t, duplicate)
+			must.Eq(t, 2, set.Len())
+		})
+
+		// SCENARIO: Remove
+		t.Run("Remove", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().From(1, 2, 100_000).Build()
+			// ========= [A]ct     =========
+			removed := set.Remove(100_000)
+			missing := set.Remove(100_000)
+			// ========= [A]ssert  =========
+			must.True(t, removed)
+			must.False(t, missing)
+			must.Eq(t, []uint32{1, 2}, slices.Collect(set.Values()))
+		})
+
+		// SCENARIO: Values are ascending across chunks
+		t.Run("Values", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().From(500_000, 3, 65_536, 65_535).Build()
+			// ========= [A]ct     =========
+			actual := slices.Collect(set.Values())
+			// ========= [A]ssert  =========
+			must.Eq(t, []uint32{3, 65_535, 65_536, 500_000}, actual)
+		})
+
+		// SCENARIO: Union
+		t.Run("Union", func(t *testing.T) {
+			// ========= [A]rrange =========
+			left := roaringbitmap.NewBuilder().From(1, 2, 70_000).Build()
+			right := roaringbitmap.NewBuilder().From(2, 3, 140_000).Build()
+			// ========= [A]ct     =========
+			actual := left.Union(right)
+			// ========= [A]ssert  =========
+			must.Eq(t, []uint32{1, 2, 3, 70_000, 140_000}, slices.Collect(actual.Values()))
+		})
+
+		// SCENARIO: Intersect
+		t.Run("Intersect", func(t *testing.T) {
+			// ========= [A]rrange =========
+			left := roaringbitmap.NewBuilder().From(1, 2, 70_000).Build()
+			right := roaringbitmap.NewBuilder().From(2, 3, 70_000).Build()
+			// ========= [A]ct     =========
+			actual := left.Intersect(right)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsSome())
+			must.Eq(t, []uint32{2, 70_000}, slices.Collect(actual.Unwrap().Values()))
+		})
+		t.Run("Intersect - empty", func(t *testing.T) {
+			// ========= [A]rrange =========
+			left := roaringbitmap.NewBuilder().From(1, 2).Build()
+			right := roaringbitmap.NewBuilder().From(3, 4).Build()
+			// ========= [A]ct     =========
+			actual := left.Intersect(right)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsNone())
+		})
+
+		// SCENARIO: Difference
+		t.Run("Difference", func(t *testing.T) {
+			// ========= [A]rrange =========
+			left := roaringbitmap.NewBuilder().From(1, 2, 70_000).Build()
+			right := roaringbitmap.NewBuilder().From(2, 3).Build()
+			// ========= [A]ct     =========
+			actual := left.Difference(right)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsSome())
+			must.Eq(t, []uint32{1, 70_000}, slices.Collect(actual.Unwrap().Values()))
+		})
+
+		// SCENARIO: SymmetricDifference
+		t.Run("SymmetricDifference", func(t *testing.T) {
+			// ========= [A]rrange =========
+			left := roaringbitmap.NewBuilder().From(1, 2, 70_000).Build()
+			right := roaringbitmap.NewBuilder().From(2, 3).Build()
+			// ========= [A]ct     =========
+			actual := left.SymmetricDifference(right)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsSome())
+			must.Eq(t, []uint32{1, 3, 70_000}, slices.Collect(actual.Unwrap().Values()))
+		})
+
+		// SCENARIO: Set algebra with another set implementation
+		t.Run("Union - hash set", func(t *testing.T) {
+			// ========= [A]rrange =========
+			left := roaringbitmap.NewBuilder().From(1, 2).Build()
+			right := hashset.NewBuiltinBuilder[uint32]().From(2, 90_000).Build()
+			// ========= [A]ct     =========
+			actual := left.Union(right)
+			// ========= [A]ssert  =========
+			must.Eq(t, []uint32{1, 2, 90_000}, slices.Collect(actual.Values()))
+		})
+
+		// SCENARIO: IsSubsetOf / IsSupersetOf
+		t.Run("IsSubsetOf", func(t *testing.T) {
+			// ========= [A]rrange =========
+			small := roaringbitmap.NewBuilder().From(1, 70_000).Build()
+			large := roaringbitmap.NewBuilder().From(1, 2, 70_000).Build()
+			// ========= [A]ssert  =========
+			must.True(t, small.IsSubsetOf(large))
+			must.False(t, large.IsSubsetOf(small))
+			must.True(t, large.IsSupersetOf(small))
+			must.False(t, small.IsSupersetOf(large))
+		})
+	})
+
+	t.Run("Containers change representation", func(t *testing.T) {
+		// SCENARIO: array grows into a bitmap and shrinks back
+		t.Run("Dense chunk", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().Build()
+			// ========= [A]ct     =========
+			for i := uint32(0); i < 10_000; i += 2 {
+				set.Add(i)
+			}
+			for i := uint32(0); i < 2_000; i += 2 {
+				set.Remove(i)
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, 4_000, set.Len())
+			must.False(t, set.Contains(1_998))
+			must.True(t, set.Contains(2_000))
+			must.Eq(t, 2_000, set.Min().Unwrap())
+			must.Eq(t, 9_998, set.Max().Unwrap())
+		})
+
+		// SCENARIO: run containers keep working after mutation
+		t.Run("Run optimized chunk", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().Build()
+			for i := uint32(100); i < 20_000; i++ {
+				set.Add(i)
+			}
+			set.RunOptimize()
+			// ========= [A]ct     =========
+			set.Remove(500)
+			set.Add(20_000)
+			set.Add(50)
+			// ========= [A]ssert  =========
+			must.Eq(t, 19_901, set.Len())
+			must.False(t, set.Contains(500))
+			must.True(t, set.Contains(501))
+			must.Eq(t, 50, set.Min().Unwrap())
+			must.Eq(t, 20_000, set.Max().Unwrap())
+		})
+
+		// SCENARIO: operations between every pair of container kinds
+		t.Run("Mixed container algebra", func(t *testing.T) {
+			// ========= [A]rrange =========
+			newValues := func(from, to, step uint32) []uint32 {
+				var res []uint32
+				for i := from; i < to; i += step {
+					res = append(res, i)
+				}
+				return res
+			}
+			sparse := newValues(0, 60_000, 50)
+			dense := newValues(0, 60_000, 3)
+			run := newValues(10_000, 40_000, 1)
+			kinds := [][]uint32{sparse, dense, run}
+			for _, left := range kinds {
+				for _, right := range kinds {
+					l := roaringbitmap.NewBuilder().From(left...).RunOptimize().Build()
+					r := roaringbitmap.NewBuilder().From(right...).RunOptimize().Build()
+					expectedLeft := hashset.NewBuiltinBuilder[uint32]().From(left...).Build()
+					expectedRight := hashset.NewBuiltinBuilder[uint32]().From(right...).Build()
+					// ========= [A]ct     =========
+					union := l.Union(r)
+					intersection := l.Intersect(r)
+					difference := l.Difference(r)
+					symmetric := l.SymmetricDifference(r)
+					// ========= [A]ssert  =========
+					must.Eq(t, expectedLeft.Union(expectedRight).Len(), union.Len())
+					must.Eq(t, expectedLeft.Intersect(expectedRight).Unwrap().Len(), intersection.Unwrap().Len())
+					must.Eq(t, expectedLeft.Difference(expectedRight).IsNone(), difference.IsNone())
+					must.Eq(t, expectedLeft.SymmetricDifference(expectedRight).IsNone(), symmetric.IsNone())
+					if difference.IsSome() {
+						must.Eq(t, expectedLeft.Difference(expectedRight).Unwrap().Len(), difference.Unwrap().Len())
+					}
+					if symmetric.IsSome() {
+						must.Eq(t, expectedLeft.SymmetricDifference(expectedRight).Unwrap().Len(), symmetric.Unwrap().Len())
+					}
+					must.True(t, slices.IsSorted(slices.Collect(union.Values())))
+				}
+			}
+		})
+	})
+
+	t.Run("Bitmap methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		set := roaringbitmap.NewBuilder().
+			From(5, 10, 70_000, 70_001).
+			Build()
+
+		// SCENARIO: Min / Max
+		t.Run("Min and Max", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 5, set.Min().Unwrap())
+			must.Eq(t, 70_001, set.Max().Unwrap())
+		})
+		t.Run("Min and Max - empty", func(t *testing.T) {
+			// ========= [A]rrange =========
+			empty := roaringbitmap.NewBuilder().Build()
+			// ========= [A]ssert  =========
+			must.True(t, empty.Min().IsNone())
+			must.True(t, empty.Max().IsNone())
+		})
+
+		// SCENARIO: Rank
+		t.Run("Rank", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 0, set.Rank(4))
+			must.Eq(t, 1, set.Rank(5))
+			must.Eq(t, 2, set.Rank(69_999))
+			must.Eq(t, 3, set.Rank(70_000))
+			must.Eq(t, 4, set.Rank(4_000_000_000))
+		})
+
+		// SCENARIO: Select
+		t.Run("Select", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 5, set.Select(0).Unwrap())
+			must.Eq(t, 70_000, set.Select(2).Unwrap())
+			must.True(t, set.Select(4).IsNone())
+			must.True(t, set.Select(-1).IsNone())
+		})
+
+		// SCENARIO: Rank and Select are inverse across container kinds
+		t.Run("Rank and Select on dense and run containers", func(t *testing.T) {
+			// ========= [A]rrange =========
+			dense := roaringbitmap.NewBuilder().Build()
+			for i := uint32(0); i < 30_000; i += 3 {
+				dense.Add(i)
+			}
+			runs := roaringbitmap.NewBuilder().Build()
+			for i := uint32(1_000); i < 9_000; i++ {
+				runs.Add(i)
+			}
+			runs.RunOptimize()
+			// ========= [A]ssert  =========
+			must.Eq(t, 3_000, dense.Select(1_000).Unwrap())
+			must.Eq(t, 1_001, dense.Rank(3_000))
+			must.Eq(t, 1_500, runs.Select(500).Unwrap())
+			must.Eq(t, 501, runs.Rank(1_500))
+		})
+	})
+
+	t.Run("Serialization works", func(t *testing.T) {
+		// SCENARIO: portable format without run containers
+		t.Run("MarshalBinary - array container", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().From(1, 2, 3).Build()
+			// ========= [A]ct     =========
+			actual, err := set.MarshalBinary()
+			// ========= [A]ssert  =========
+			must.NoError(t, err)
+			must.Eq(t, []byte{
+				0x3A, 0x30, 0x00, 0x00, // cookie
+				0x01, 0x00, 0x00, 0x00, // container count
+				0x00, 0x00, 0x02, 0x00, // key, cardinality - 1
+				0x10, 0x00, 0x00, 0x00, // offset
+				0x01, 0x00, 0x02, 0x00, 0x03, 0x00,
+			}, actual)
+		})
+
+		// SCENARIO: portable format with run containers
+		t.Run("MarshalBinary - run container", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().
+				From(1, 2, 3, 4, 5, 6, 7, 8, 9, 10).
+				RunOptimize().
+				Build()
+			// ========= [A]ct     =========
+			actual, err := set.MarshalBinary()
+			// ========= [A]ssert  =========
+			must.NoError(t, err)
+			must.Eq(t, []byte{
+				0x3B, 0x30, 0x00, 0x00, // cookie with container count - 1
+				0x01,                   // run flags
+				0x00, 0x00, 0x09, 0x00, // key, cardinality - 1
+				0x01, 0x00, // number of runs
+				0x01, 0x00, 0x09, 0x00, // start, length - 1
+			}, actual)
+		})
+
+		// SCENARIO: round trip across all container kinds
+		t.Run("Round trip", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().From(7, 1_000_000, 4_000_000_000).Build()
+			for i := uint32(65_536); i < 65_536+10_000; i += 2 {
+				set.Add(i)
+			}
+			for i := uint32(200_000); i < 230_000; i++ {
+				set.Add(i)
+			}
+			set.RunOptimize()
+			data, err := set.MarshalBinary()
+			must.NoError(t, err)
+			// ========= [A]ct     =========
+			decoded := roaringbitmap.NewBuilder().Build()
+			err = decoded.UnmarshalBinary(data)
+			// ========= [A]ssert  =========
+			must.NoError(t, err)
+			must.Eq(t, set.Len(), decoded.Len())
+			must.Eq(t, slices.Collect(set.Values()), slices.Collect(decoded.Values()))
+		})
+
+		// SCENARIO: malformed input
+		t.Run("UnmarshalBinary - truncated", func(t *testing.T) {
+			// ========= [A]rrange =========
+			set := roaringbitmap.NewBuilder().From(1, 2, 3).Build()
+			data, _ := set.MarshalBinary()
+			decoded := roaringbitmap.NewBuilder().Build()
+			// ========= [A]ct     =========
+			err := decoded.UnmarshalBinary(data[:len(data)-1])
+			// ========= [A]ssert  =========
+			must.Error(t, err)
+		})
+		t.Run("UnmarshalBinary - unknown cookie", func(t *testing.T) {
+			// ========= [A]rrange =========
+			decoded := roaringbitmap.NewBuilder().Build()
+			// ========= [A]ct     =========
+			err := decoded.UnmarshalBinary([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+			// ========= [A]ssert  =========
+			must.Error(t, err)
+		})
+	})
+}
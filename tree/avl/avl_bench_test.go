@@ -0,0 +1,76 @@
+package avl_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/where"
+	"codeberg.org/yaadata/bina/internal/btree"
+	"codeberg.org/yaadata/bina/tree/avl"
+)
+
+const benchmarkSize = 10_000
+
+func benchmarkKeys() []int {
+	rng := rand.New(rand.NewPCG(1, 2))
+	return rng.Perm(benchmarkSize)
+}
+
+func benchmarkTrees() map[string]func() collection.SearchTree[int, int] {
+	return map[string]func() collection.SearchTree[int, int]{
+		"AVL": func() collection.SearchTree[int, int] {
+			return avl.NewBuiltinBuilder[int, int]().Build()
+		},
+		"BTree": func() collection.SearchTree[int, int] {
+			return btree.NewBuiltinImpl[int, int](5)
+		},
+	}
+}
+
+func BenchmarkPut(b *testing.B) {
+	keys := benchmarkKeys()
+	for name, newTree := range benchmarkTrees() {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				tree := newTree()
+				for _, key := range keys {
+					tree.Put(key, key)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	keys := benchmarkKeys()
+	for name, newTree := range benchmarkTrees() {
+		tree := newTree()
+		for _, key := range keys {
+			tree.Put(key, key)
+		}
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				for _, key := range keys {
+					tree.Get(key)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRange(b *testing.B) {
+	keys := benchmarkKeys()
+	for name, newTree := range benchmarkTrees() {
+		tree := newTree()
+		for _, key := range keys {
+			tree.Put(key, key)
+		}
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				for range tree.Range(where.From(benchmarkSize/4), where.To(benchmarkSize/2)) {
+				}
+			}
+		})
+	}
+}
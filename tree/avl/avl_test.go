@@ -0,0 +1,405 @@
+package avl_test
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	. "codeberg.org/yaadata/opt"
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/where"
+	"codeberg.org/yaadata/bina/tree/avl"
+)
+
+func keys[K any, V any](seq iter.Seq2[K, V]) []K {
+	var res []K
+	for key := range seq {
+		res = append(res, key)
+	}
+	return res
+}
+
+func newTree(items ...int) collection.AVLTree[int, int] {
+	pairs := make([]kv.Pair[int, int], 0, len(items))
+	for _, item := range items {
+		pairs = append(pairs, kv.New(item, item*10))
+	}
+	return avl.NewBuiltinBuilder[int, int]().
+		From(pairs...).
+		Build()
+}
+
+// assertBalanced walks every node and checks the stored heights and balance factors.
+func assertBalanced[K any, V any](t *testing.T, tree collection.AVLTree[K, V]) {
+	t.Helper()
+	var walk func(n Option[collection.AVLTreeNode[K, V]]) int
+	walk = func(n Option[collection.AVLTreeNode[K, V]]) int {
+		if n.IsNone() {
+			return 0
+		}
+		node := n.Unwrap()
+		left := walk(node.Left())
+		right := walk(node.Right())
+		must.Eq(t, 1+max(left, right), node.Height())
+		must.Eq(t, right-left, node.BalanceFactor())
+		must.Between(t, -1, node.BalanceFactor(), 1)
+		return node.Height()
+	}
+	must.Eq(t, tree.Height(), walk(tree.Root()))
+}
+
+func TestAVLTreeBuiltin(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := avl.NewBuiltinBuilder[int, int]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, 0, tree.Height())
+		assertBalanced(t, tree)
+	})
+
+	t.Run("Can build from pairs", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(5, 3, 8, 1)
+		// ========= [A]ssert  =========
+		must.Eq(t, 4, tree.Len())
+		must.Eq(t, []int{1, 3, 5, 8}, keys(tree.All()))
+		assertBalanced(t, tree)
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(1, 2, 3)
+
+		// SCENARIO: Contains
+		t.Run("Contains - true", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Contains(2)
+			// ========= [A]ssert  =========
+			must.True(t, actual)
+		})
+		t.Run("Contains - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Contains(4)
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: IsEmpty
+		t.Run("IsEmpty - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.IsEmpty()
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Clear()
+			// ========= [A]ssert  =========
+			must.True(t, tree.IsEmpty())
+			must.True(t, tree.Min().IsNone())
+		})
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(1, 2, 3, 4, 5)
+
+		// SCENARIO: Any
+		t.Run("Any", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.Any(func(pair kv.Pair[int, int]) bool {
+				return pair.Value() == 40
+			}))
+			must.False(t, tree.Any(func(pair kv.Pair[int, int]) bool {
+				return pair.Key() > 5
+			}))
+		})
+
+		// SCENARIO: Count
+		t.Run("Count", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Count(func(pair kv.Pair[int, int]) bool {
+				return pair.Key()%2 == 0
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, actual)
+		})
+
+		// SCENARIO: Every
+		t.Run("Every", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.Every(func(pair kv.Pair[int, int]) bool {
+				return pair.Value() == pair.Key()*10
+			}))
+			must.False(t, tree.Every(func(pair kv.Pair[int, int]) bool {
+				return pair.Key() < 5
+			}))
+		})
+
+		// SCENARIO: ForEach
+		t.Run("ForEach", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var visited []int
+			tree.ForEach(func(pair kv.Pair[int, int]) {
+				visited = append(visited, pair.Key())
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5}, visited)
+		})
+	})
+
+	t.Run("Search tree methods work", func(t *testing.T) {
+		// SCENARIO: Put / Get
+		t.Run("Put - update existing key", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Put(2, -1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, tree.Len())
+			must.Eq(t, -1, tree.Get(2).Unwrap())
+		})
+		t.Run("Get - missing key", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			actual := tree.Get(10)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsNone())
+		})
+
+		// SCENARIO: Delete
+		t.Run("Delete", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			deleted := tree.Delete(4)
+			missing := tree.Delete(4)
+			// ========= [A]ssert  =========
+			must.Eq(t, 40, deleted.Unwrap())
+			must.True(t, missing.IsNone())
+			must.Eq(t, []int{1, 2, 3, 5, 6, 7, 8, 9, 10}, keys(tree.All()))
+			assertBalanced(t, tree)
+		})
+
+		// SCENARIO: Height is exact for sorted input
+		t.Run("Height", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := avl.NewBuiltinBuilder[int, int]().Build()
+			// ========= [A]ct     =========
+			for i := range 1023 {
+				tree.Put(i, i)
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, 10, tree.Height())
+			assertBalanced(t, tree)
+		})
+
+		// SCENARIO: Min / Max
+		t.Run("Min and Max", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(7, 3, 9, 1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 1, tree.Min().Unwrap().Key())
+			must.Eq(t, 9, tree.Max().Unwrap().Key())
+		})
+
+		// SCENARIO: Floor / Ceiling
+		t.Run("Floor", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(10, 20, 30, 40, 50, 60)
+			// ========= [A]ssert  =========
+			must.Eq(t, 30, tree.Floor(30).Unwrap().Key())
+			must.Eq(t, 30, tree.Floor(39).Unwrap().Key())
+			must.Eq(t, 60, tree.Floor(100).Unwrap().Key())
+			must.True(t, tree.Floor(5).IsNone())
+		})
+		t.Run("Ceiling", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(10, 20, 30, 40, 50, 60)
+			// ========= [A]ssert  =========
+			must.Eq(t, 30, tree.Ceiling(30).Unwrap().Key())
+			must.Eq(t, 40, tree.Ceiling(31).Unwrap().Key())
+			must.Eq(t, 10, tree.Ceiling(0).Unwrap().Key())
+			must.True(t, tree.Ceiling(61).IsNone())
+		})
+
+		// SCENARIO: Range
+		t.Run("Range - inclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			actual := keys(tree.Range(where.From(3), where.To(7)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{3, 4, 5, 6, 7}, actual)
+		})
+		t.Run("Range - exclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			actual := keys(tree.Range(where.FromExclusive(3), where.ToExclusive(7)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{4, 5, 6}, actual)
+		})
+		t.Run("Range - early break", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			var visited []int
+			for key := range tree.Range(where.From(2)) {
+				if key > 4 {
+					break
+				}
+				visited = append(visited, key)
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 3, 4}, visited)
+		})
+
+		// SCENARIO: All
+		t.Run("All - traversal strategies", func(t *testing.T) {
+			// ========= [A]rrange =========
+			// Inserting 1..7 in order settles into a perfect tree rooted at 4
+			tree := newTree(1, 2, 3, 4, 5, 6, 7)
+			// ========= [A]ct     =========
+			inOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyInOrder)))
+			preOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPreOrder)))
+			postOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPostOrder)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5, 6, 7}, inOrder)
+			must.Eq(t, []int{4, 2, 1, 3, 6, 5, 7}, preOrder)
+			must.Eq(t, []int{1, 3, 2, 5, 7, 6, 4}, postOrder)
+		})
+	})
+
+	t.Run("Node methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(1, 2, 3, 4, 5, 6, 7)
+
+		// SCENARIO: Root
+		t.Run("Root", func(t *testing.T) {
+			// ========= [A]ct     =========
+			root := tree.Root().Unwrap()
+			// ========= [A]ssert  =========
+			must.Eq(t, 4, root.Key())
+			must.Eq(t, 40, root.Value())
+			must.Eq(t, 3, root.Height())
+			must.Eq(t, 0, root.BalanceFactor())
+		})
+		t.Run("Root - empty tree", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, newTree().Root().IsNone())
+		})
+
+		// SCENARIO: GetNode
+		t.Run("GetNode", func(t *testing.T) {
+			// ========= [A]ct     =========
+			n := tree.GetNode(6).Unwrap()
+			// ========= [A]ssert  =========
+			must.Eq(t, 5, n.Left().Unwrap().Key())
+			must.Eq(t, 7, n.Right().Unwrap().Key())
+			must.Eq(t, 2, n.Height())
+		})
+		t.Run("GetNode - missing key", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.GetNode(8).IsNone())
+		})
+
+		// SCENARIO: Children
+		t.Run("Children", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var children []int
+			for child := range tree.GetNode(2).Unwrap().Children() {
+				children = append(children, child.Key())
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 3}, children)
+		})
+		t.Run("Children - leaf", func(t *testing.T) {
+			// ========= [A]ct     =========
+			leaf := tree.GetNode(1).Unwrap()
+			// ========= [A]ssert  =========
+			must.True(t, leaf.Left().IsNone())
+			must.True(t, leaf.Right().IsNone())
+			must.Eq(t, 1, leaf.Height())
+			for range leaf.Children() {
+				t.Fatal("leaf should have no children")
+			}
+		})
+
+		// SCENARIO: BalanceFactor
+		t.Run("BalanceFactor - right heavy", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 3, 4)
+			// ========= [A]ct     =========
+			actual := tree.Root().Unwrap().BalanceFactor()
+			// ========= [A]ssert  =========
+			must.Eq(t, 1, actual)
+		})
+	})
+
+	t.Run("Random operations keep the tree valid", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(3, 5))
+		tree := newTree()
+		expected := map[int]bool{}
+		// ========= [A]ct     =========
+		for i := range 5_000 {
+			key := rng.IntN(500)
+			if rng.IntN(3) == 0 {
+				tree.Delete(key)
+				delete(expected, key)
+			} else {
+				tree.Put(key, key)
+				expected[key] = true
+			}
+			if i%250 == 0 {
+				assertBalanced(t, tree)
+			}
+		}
+		// ========= [A]ssert  =========
+		var sorted []int
+		for key := range expected {
+			sorted = append(sorted, key)
+		}
+		slices.Sort(sorted)
+		assertBalanced(t, tree)
+		must.Eq(t, sorted, keys(tree.All()))
+	})
+}
+
+func TestAVLTreeComparator(t *testing.T) {
+	// ========= [A]rrange =========
+	descending := func(a, b string) compare.Order {
+		return compare.Builtin(b, a)
+	}
+	tree := avl.NewComparatorBuilder[string, int](descending).
+		From(kv.New("a", 1), kv.New("c", 3), kv.New("b", 2), kv.New("d", 4)).
+		Build()
+
+	// SCENARIO: keys follow the comparator
+	t.Run("All", func(t *testing.T) {
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"d", "c", "b", "a"}, keys(tree.All()))
+		assertBalanced(t, tree)
+	})
+
+	// SCENARIO: bounds follow the comparator
+	t.Run("Range", func(t *testing.T) {
+		// ========= [A]ct     =========
+		actual := keys(tree.Range(where.From("c"), where.ToExclusive("a")))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"c", "b"}, actual)
+	})
+}
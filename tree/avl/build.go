@@ -0,0 +1,43 @@
+package avl
+
+import (
+	"cmp"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/internal/avl"
+)
+
+// NewBuiltinBuilder returns a [Builder] for creating a [collection.AVLTree] with ordered keys.
+func NewBuiltinBuilder[K cmp.Ordered, V any]() Builder[K, V, collection.AVLTree[K, V], *comparatorBuilder[K, V]] {
+	return NewComparatorBuilder[K, V](compare.Builtin[K])
+}
+
+// NewComparatorBuilder returns a [Builder] for creating a [collection.AVLTree] whose keys are ordered by fn.
+func NewComparatorBuilder[K any, V any](fn func(a, b K) compare.Order) Builder[K, V, collection.AVLTree[K, V], *comparatorBuilder[K, V]] {
+	return &comparatorBuilder[K, V]{
+		compare: fn,
+		from:    None[[]kv.Pair[K, V]](),
+	}
+}
+
+type comparatorBuilder[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	from    Option[[]kv.Pair[K, V]]
+}
+
+func (b *comparatorBuilder[K, V]) From(items ...kv.Pair[K, V]) *comparatorBuilder[K, V] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *comparatorBuilder[K, V]) Build() collection.AVLTree[K, V] {
+	resp := avl.New[K, V](b.compare)
+	for _, pair := range b.from.UnwrapOrDefault() {
+		resp.Put(pair.Key(), pair.Value())
+	}
+	return resp
+}
@@ -0,0 +1,11 @@
+package avl
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/tree/builder"
+)
+
+// Builder is a [builder.BaseBuilder] for [collection.AVLTree] implementations.
+type Builder[K any, V any, Target collection.AVLTree[K, V], Self Builder[K, V, Target, Self]] interface {
+	builder.BaseBuilder[K, V, Target, Self]
+}
@@ -0,0 +1,422 @@
+package bplustree_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/where"
+	bplustree "codeberg.org/yaadata/bina/tree/bplus_tree"
+)
+
+func keys[K any, V any](seq func(yield func(K, V) bool)) []K {
+	var res []K
+	for key := range seq {
+		res = append(res, key)
+	}
+	return res
+}
+
+func newTree(order int, items ...int) collection.BPlusTree[int, string] {
+	pairs := make([]kv.Pair[int, string], 0, len(items))
+	for _, item := range items {
+		pairs = append(pairs, kv.New(item, strings.Repeat("v", item%5+1)))
+	}
+	return bplustree.NewBuiltinBuilder[int, string]().
+		Order(order).
+		From(pairs...).
+		Build()
+}
+
+func TestBPlusTreeBuiltin(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := bplustree.NewBuiltinBuilder[int, string]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, 0, tree.Height())
+		must.Eq(t, 5, tree.Order())
+	})
+
+	t.Run("Can build from pairs", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(2, 5, 3, 8, 1)
+		// ========= [A]ssert  =========
+		must.Eq(t, 4, tree.Len())
+		must.Eq(t, []int{1, 3, 5, 8}, keys(tree.All()))
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(2, 1, 2, 3)
+
+		// SCENARIO: Contains
+		t.Run("Contains - true", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Contains(2)
+			// ========= [A]ssert  =========
+			must.True(t, actual)
+		})
+		t.Run("Contains - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Contains(4)
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: IsEmpty
+		t.Run("IsEmpty - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.IsEmpty()
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Clear()
+			// ========= [A]ssert  =========
+			must.True(t, tree.IsEmpty())
+			must.Eq(t, 0, tree.Height())
+			must.True(t, tree.Min().IsNone())
+		})
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(2, 1, 2, 3, 4, 5)
+
+		// SCENARIO: Any
+		t.Run("Any", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.Any(func(pair kv.Pair[int, string]) bool {
+				return pair.Key() == 4
+			}))
+			must.False(t, tree.Any(func(pair kv.Pair[int, string]) bool {
+				return pair.Key() > 5
+			}))
+		})
+
+		// SCENARIO: Count
+		t.Run("Count", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Count(func(pair kv.Pair[int, string]) bool {
+				return pair.Key()%2 == 1
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, actual)
+		})
+
+		// SCENARIO: Every
+		t.Run("Every", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.Every(func(pair kv.Pair[int, string]) bool {
+				return pair.Key() > 0
+			}))
+			must.False(t, tree.Every(func(pair kv.Pair[int, string]) bool {
+				return pair.Key() > 1
+			}))
+		})
+
+		// SCENARIO: ForEach
+		t.Run("ForEach", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var visited []int
+			tree.ForEach(func(pair kv.Pair[int, string]) {
+				visited = append(visited, pair.Key())
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5}, visited)
+		})
+	})
+
+	t.Run("Search tree methods work", func(t *testing.T) {
+		// SCENARIO: Put / Get
+		t.Run("Put - update existing key", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Put(2, "updated")
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, tree.Len())
+			must.Eq(t, "updated", tree.Get(2).Unwrap())
+		})
+		t.Run("Get - missing key", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3)
+			// ========= [A]ct     =========
+			actual := tree.Get(10)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsNone())
+		})
+
+		// SCENARIO: Delete
+		t.Run("Delete", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			deleted := tree.Delete(4)
+			missing := tree.Delete(4)
+			// ========= [A]ssert  =========
+			must.Eq(t, "vvvvv", deleted.Unwrap())
+			must.True(t, missing.IsNone())
+			must.Eq(t, []int{1, 2, 3, 5, 6, 7, 8, 9, 10}, keys(tree.All()))
+		})
+		t.Run("Delete - until empty", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3, 4, 5, 6, 7, 8)
+			// ========= [A]ct     =========
+			for i := 1; i <= 8; i++ {
+				tree.Delete(i)
+			}
+			// ========= [A]ssert  =========
+			must.True(t, tree.IsEmpty())
+			must.Eq(t, 0, tree.Height())
+			must.Eq(t, 0, len(keys(tree.All())))
+		})
+
+		// SCENARIO: Height
+		t.Run("Height grows with splits", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Put(4, "v")
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, tree.Height())
+		})
+
+		// SCENARIO: Min / Max
+		t.Run("Min and Max", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 7, 3, 9, 1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 1, tree.Min().Unwrap().Key())
+			must.Eq(t, 9, tree.Max().Unwrap().Key())
+		})
+
+		// SCENARIO: Floor / Ceiling
+		t.Run("Floor", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 10, 20, 30, 40, 50, 60)
+			// ========= [A]ssert  =========
+			must.Eq(t, 30, tree.Floor(30).Unwrap().Key())
+			must.Eq(t, 30, tree.Floor(39).Unwrap().Key())
+			must.Eq(t, 60, tree.Floor(100).Unwrap().Key())
+			must.True(t, tree.Floor(5).IsNone())
+		})
+		t.Run("Ceiling", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 10, 20, 30, 40, 50, 60)
+			// ========= [A]ssert  =========
+			must.Eq(t, 30, tree.Ceiling(30).Unwrap().Key())
+			must.Eq(t, 40, tree.Ceiling(31).Unwrap().Key())
+			must.Eq(t, 10, tree.Ceiling(0).Unwrap().Key())
+			must.True(t, tree.Ceiling(61).IsNone())
+		})
+
+		// SCENARIO: Range
+		t.Run("Range - inclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			actual := keys(tree.Range(where.From(3), where.To(7)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{3, 4, 5, 6, 7}, actual)
+		})
+		t.Run("Range - exclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			actual := keys(tree.Range(where.FromExclusive(3), where.ToExclusive(7)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{4, 5, 6}, actual)
+		})
+		t.Run("Range - open ended", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{8, 9, 10}, keys(tree.Range(where.From(8))))
+			must.Eq(t, []int{1, 2}, keys(tree.Range(where.To(2))))
+			must.Eq(t, 10, len(keys(tree.Range())))
+		})
+		t.Run("Range - early break", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			var visited []int
+			for key := range tree.Range(where.From(2)) {
+				if key > 4 {
+					break
+				}
+				visited = append(visited, key)
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 3, 4}, visited)
+		})
+
+		// SCENARIO: All
+		t.Run("All - only in-order is supported", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(2, 5, 1, 4, 2, 3)
+			// ========= [A]ssert  =========
+			// The keys span several levels, yet pre-order and post-order
+			// still yield the leaves in key order
+			must.Greater(t, 1, tree.Height())
+			for _, strategy := range []collection.SearchTreeStrategy{
+				collection.SearchTreeStrategyInOrder,
+				collection.SearchTreeStrategyPreOrder,
+				collection.SearchTreeStrategyPostOrder,
+			} {
+				must.Eq(t, []int{1, 2, 3, 4, 5}, keys(tree.All(collection.WithSearchTreeStrategy(strategy))))
+			}
+		})
+	})
+
+	t.Run("Cursor works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(2, 10, 20, 30, 40, 50, 60, 70)
+
+		// SCENARIO: Next walks forward across leaves
+		t.Run("Next", func(t *testing.T) {
+			// ========= [A]rrange =========
+			cursor := tree.Cursor()
+			// ========= [A]ct     =========
+			var visited []int
+			for pair := cursor.Next(); pair.IsSome(); pair = cursor.Next() {
+				visited = append(visited, pair.Unwrap().Key())
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{10, 20, 30, 40, 50, 60, 70}, visited)
+			must.True(t, cursor.Current().IsNone())
+		})
+
+		// SCENARIO: Prev walks backward across leaves
+		t.Run("Prev", func(t *testing.T) {
+			// ========= [A]rrange =========
+			cursor := tree.Cursor()
+			// ========= [A]ct     =========
+			var visited []int
+			for pair := cursor.Prev(); pair.IsSome(); pair = cursor.Prev() {
+				visited = append(visited, pair.Unwrap().Key())
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{70, 60, 50, 40, 30, 20, 10}, visited)
+		})
+
+		// SCENARIO: Seek
+		t.Run("Seek", func(t *testing.T) {
+			// ========= [A]rrange =========
+			cursor := tree.Cursor()
+			// ========= [A]ct     =========
+			exact := cursor.Seek(40)
+			// ========= [A]ssert  =========
+			must.Eq(t, 40, exact.Unwrap().Key())
+			must.Eq(t, 50, cursor.Next().Unwrap().Key())
+			must.Eq(t, 40, cursor.Prev().Unwrap().Key())
+			must.Eq(t, 30, cursor.Prev().Unwrap().Key())
+		})
+		t.Run("Seek - between keys", func(t *testing.T) {
+			// ========= [A]rrange =========
+			cursor := tree.Cursor()
+			// ========= [A]ct     =========
+			actual := cursor.Seek(41)
+			// ========= [A]ssert  =========
+			must.Eq(t, 50, actual.Unwrap().Key())
+		})
+		t.Run("Seek - past the end", func(t *testing.T) {
+			// ========= [A]rrange =========
+			cursor := tree.Cursor()
+			// ========= [A]ct     =========
+			actual := cursor.Seek(71)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsNone())
+			must.True(t, cursor.Current().IsNone())
+		})
+
+		// SCENARIO: First / Last
+		t.Run("First and Last", func(t *testing.T) {
+			// ========= [A]rrange =========
+			cursor := tree.Cursor()
+			// ========= [A]ssert  =========
+			must.Eq(t, 10, cursor.First().Unwrap().Key())
+			must.Eq(t, 70, cursor.Last().Unwrap().Key())
+			must.True(t, cursor.Next().IsNone())
+		})
+		t.Run("First - empty tree", func(t *testing.T) {
+			// ========= [A]rrange =========
+			cursor := newTree(2).Cursor()
+			// ========= [A]ssert  =========
+			must.True(t, cursor.First().IsNone())
+			must.True(t, cursor.Last().IsNone())
+			must.True(t, cursor.Next().IsNone())
+		})
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(7, 11))
+		tree := newTree(2)
+		expected := map[int]bool{}
+		// ========= [A]ct     =========
+		for range 5_000 {
+			key := rng.IntN(500)
+			if rng.IntN(3) == 0 {
+				tree.Delete(key)
+				delete(expected, key)
+			} else {
+				tree.Put(key, "v")
+				expected[key] = true
+			}
+		}
+		// ========= [A]ssert  =========
+		var sorted []int
+		for key := range expected {
+			sorted = append(sorted, key)
+		}
+		slices.Sort(sorted)
+		must.Eq(t, len(sorted), tree.Len())
+		must.Eq(t, sorted, keys(tree.All()))
+		var backwards []int
+		cursor := tree.Cursor()
+		for pair := cursor.Last(); pair.IsSome(); pair = cursor.Prev() {
+			backwards = append(backwards, pair.Unwrap().Key())
+		}
+		slices.Reverse(backwards)
+		must.Eq(t, sorted, backwards)
+	})
+}
+
+func TestBPlusTreeComparator(t *testing.T) {
+	// ========= [A]rrange =========
+	descending := func(a, b string) compare.Order {
+		return compare.Builtin(b, a)
+	}
+	tree := bplustree.NewComparatorBuilder[string, int](descending).
+		Order(2).
+		From(kv.New("a", 1), kv.New("c", 3), kv.New("b", 2), kv.New("d", 4)).
+		Build()
+
+	// SCENARIO: keys follow the comparator
+	t.Run("All", func(t *testing.T) {
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"d", "c", "b", "a"}, keys(tree.All()))
+	})
+
+	// SCENARIO: bounds follow the comparator
+	t.Run("Range", func(t *testing.T) {
+		// ========= [A]ct     =========
+		actual := keys(tree.Range(where.From("c"), where.ToExclusive("a")))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"c", "b"}, actual)
+	})
+}
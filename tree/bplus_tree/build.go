@@ -0,0 +1,52 @@
+package bplustree
+
+import (
+	"cmp"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	bplustree "codeberg.org/yaadata/bina/internal/bplus_tree"
+)
+
+// NewBuiltinBuilder returns a [Builder] for creating a [collection.BPlusTree] with ordered keys.
+func NewBuiltinBuilder[K cmp.Ordered, V any]() Builder[K, V, collection.BPlusTree[K, V], *comparatorBuilder[K, V]] {
+	return NewComparatorBuilder[K, V](compare.Builtin[K])
+}
+
+// NewComparatorBuilder returns a [Builder] for creating a [collection.BPlusTree] whose keys are ordered by fn.
+func NewComparatorBuilder[K any, V any](fn func(a, b K) compare.Order) Builder[K, V, collection.BPlusTree[K, V], *comparatorBuilder[K, V]] {
+	return &comparatorBuilder[K, V]{
+		compare: fn,
+		order:   None[int](),
+		from:    None[[]kv.Pair[K, V]](),
+	}
+}
+
+type comparatorBuilder[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	order   Option[int]
+	from    Option[[]kv.Pair[K, V]]
+}
+
+func (b *comparatorBuilder[K, V]) From(items ...kv.Pair[K, V]) *comparatorBuilder[K, V] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *comparatorBuilder[K, V]) Order(order int) *comparatorBuilder[K, V] {
+	b.order = Some(order)
+	return b
+}
+
+func (b *comparatorBuilder[K, V]) Build() collection.BPlusTree[K, V] {
+	resp := bplustree.New[K, V](b.order.UnwrapOrElse(func() int {
+		return 5
+	}), b.compare)
+	for _, pair := range b.from.UnwrapOrDefault() {
+		resp.Put(pair.Key(), pair.Value())
+	}
+	return resp
+}
@@ -0,0 +1,13 @@
+package bplustree
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/tree/builder"
+)
+
+// Builder is a [builder.BaseBuilder] for [collection.BPlusTree] implementations.
+type Builder[K any, V any, Target collection.BPlusTree[K, V], Self Builder[K, V, Target, Self]] interface {
+	builder.BaseBuilder[K, V, Target, Self]
+	// Order sets the branching factor of the tree. Default is 5.
+	Order(order int) Self
+}
@@ -0,0 +1,100 @@
+package btree_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/tree/btree"
+)
+
+// assertKeys checks that tree holds exactly expected, in ascending order,
+// each mapped to ten times its key.
+func assertKeys(t *testing.T, tree collection.BTree[int, int], expected []int) {
+	t.Helper()
+	slices.Sort(expected)
+	must.Eq(t, len(expected), tree.Len())
+	var actual []int
+	for key, value := range tree.All() {
+		must.Eq(t, key*10, value)
+		actual = append(actual, key)
+	}
+	must.True(t, slices.Equal(expected, actual))
+}
+
+func TestBTree(t *testing.T) {
+	// SCENARIO: ascending keys always split the last child, so the right half
+	// of every split lands one past the end of its parent's children
+	t.Run("Ascending inserts append children", func(t *testing.T) {
+		for _, order := range []int{2, 3, 4, 5} {
+			// ========= [A]rrange =========
+			tree := btree.NewBuiltinBuilder[int, int]().
+				Order(order).
+				Build()
+			var expected []int
+			// ========= [A]ct     =========
+			for key := range 200 {
+				tree.Put(key, key*10)
+				expected = append(expected, key)
+			}
+			// ========= [A]ssert  =========
+			assertKeys(t, tree, expected)
+			must.Eq(t, 1990, tree.Get(199).Unwrap())
+			must.Eq(t, 199, tree.Max().Unwrap().Key())
+		}
+	})
+
+	// SCENARIO: deleting from the right rebalances the last child, which
+	// borrows from or merges with its left sibling before going back at the end
+	t.Run("Descending deletes rebalance the last child", func(t *testing.T) {
+		for _, order := range []int{2, 3, 4, 5} {
+			// ========= [A]rrange =========
+			tree := btree.NewBuiltinBuilder[int, int]().
+				Order(order).
+				Build()
+			var expected []int
+			for key := range 200 {
+				tree.Put(key, key*10)
+				expected = append(expected, key)
+			}
+			for key := 199; key >= 0; key-- {
+				// ========= [A]ct     =========
+				removed := tree.Delete(key)
+				expected = expected[:key]
+				// ========= [A]ssert  =========
+				must.Eq(t, key*10, removed.Unwrap())
+				assertKeys(t, tree, expected)
+			}
+			must.True(t, tree.IsEmpty())
+		}
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(3, 9))
+		tree := btree.NewBuiltinBuilder[int, int]().
+			Order(3).
+			Build()
+		present := make(map[int]bool)
+		// ========= [A]ct     =========
+		for range 2_000 {
+			key := rng.IntN(300)
+			if rng.IntN(3) == 0 {
+				must.Eq(t, present[key], tree.Delete(key).IsSome())
+				delete(present, key)
+			} else {
+				tree.Put(key, key*10)
+				present[key] = true
+			}
+		}
+		// ========= [A]ssert  =========
+		var expected []int
+		for key := range present {
+			expected = append(expected, key)
+		}
+		assertKeys(t, tree, expected)
+	})
+}
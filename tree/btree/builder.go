@@ -8,4 +8,6 @@ import (
 // Builder is a [builder.BaseBuilder] for [collection.BTree] implementations.
 type Builder[K any, V any, Target collection.BTree[K, V], Self Builder[K, V, Target, Self]] interface {
 	builder.BaseBuilder[K, V, Target, Self]
+	// Order sets the branching factor of the tree. Default is 5.
+	Order(order int) Self
 }
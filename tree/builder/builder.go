@@ -10,8 +10,6 @@ import (
 type BaseBuilder[K any, V any, Target collection.SearchTree[K, V], Self BaseBuilder[K, V, Target, Self]] interface {
 	// Build constructs and returns the target search tree.
 	Build() Target
-	// Order sets the branching factor of the tree. Default is 5.
-	Order(order int) Self
 	// From initializes the tree with the given key-value pairs.
 	From(pairs ...kv.Pair[K, V]) Self
 }
@@ -0,0 +1,74 @@
+package fenwick
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/numeric"
+	"codeberg.org/yaadata/bina/internal/fenwick"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.FenwickTree].
+func NewBuilder[T numeric.Number]() Builder[T, collection.FenwickTree[T], *builder[T]] {
+	return &builder[T]{
+		from: None[[]T](),
+		size: None[int](),
+	}
+}
+
+type builder[T numeric.Number] struct {
+	from Option[[]T]
+	size Option[int]
+}
+
+func (b *builder[T]) From(items ...T) *builder[T] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *builder[T]) Size(size int) *builder[T] {
+	b.size = Some(size)
+	return b
+}
+
+func (b *builder[T]) Build() collection.FenwickTree[T] {
+	return fenwick.New(b.from.UnwrapOrElse(func() []T {
+		return make([]T, max(b.size.UnwrapOrDefault(), 0))
+	}))
+}
+
+// New2DBuilder returns a [Builder2D] for creating a [collection.FenwickTree2D].
+func New2DBuilder[T numeric.Number]() Builder2D[T, collection.FenwickTree2D[T], *builder2D[T]] {
+	return &builder2D[T]{
+		from: None[[][]T](),
+		size: None[kv.Pair[int, int]](),
+	}
+}
+
+type builder2D[T numeric.Number] struct {
+	from Option[[][]T]
+	size Option[kv.Pair[int, int]]
+}
+
+func (b *builder2D[T]) From(rows ...[]T) *builder2D[T] {
+	b.from = Some(rows)
+	return b
+}
+
+func (b *builder2D[T]) Size(rows, cols int) *builder2D[T] {
+	b.size = Some(kv.New(rows, cols))
+	return b
+}
+
+func (b *builder2D[T]) Build() collection.FenwickTree2D[T] {
+	grid := b.from.UnwrapOrDefault()
+	size := b.size.UnwrapOrElse(func() kv.Pair[int, int] {
+		var cols int
+		for _, row := range grid {
+			cols = max(cols, len(row))
+		}
+		return kv.New(len(grid), cols)
+	})
+	return fenwick.New2D(size.Key(), size.Value(), grid)
+}
@@ -0,0 +1,30 @@
+package fenwick
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/numeric"
+)
+
+// Builder defines the fluent interface for constructing Fenwick trees.
+// Use [NewBuilder] to obtain one.
+type Builder[T numeric.Number, Target collection.FenwickTree[T], Self Builder[T, Target, Self]] interface {
+	// Build constructs and returns the target Fenwick tree.
+	Build() Target
+	// From initializes the tree with the given items.
+	From(items ...T) Self
+	// Size initializes the tree with size zeros. Ignored when From is set.
+	Size(size int) Self
+}
+
+// Builder2D defines the fluent interface for constructing two-dimensional Fenwick trees.
+// Use [New2DBuilder] to obtain one.
+type Builder2D[T numeric.Number, Target collection.FenwickTree2D[T], Self Builder2D[T, Target, Self]] interface {
+	// Build constructs and returns the target Fenwick tree.
+	Build() Target
+	// From initializes the grid with the given rows. Short rows are padded
+	// with zeros.
+	From(rows ...[]T) Self
+	// Size sets the dimensions of the grid. Defaults to the dimensions of
+	// the rows given to From.
+	Size(rows, cols int) Self
+}
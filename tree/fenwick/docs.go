@@ -0,0 +1,8 @@
+// Package fenwick implements [collection.FenwickTree] and [collection.FenwickTree2D].
+//
+// A Fenwick tree answers prefix and range sums with far smaller constant
+// factors than a segment tree, at the cost of supporting only sums and point
+// updates.
+package fenwick
+
+import _ "codeberg.org/yaadata/bina/core/collection"
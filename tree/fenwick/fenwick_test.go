@@ -0,0 +1,240 @@
+package fenwick_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/where"
+	"codeberg.org/yaadata/bina/tree/fenwick"
+)
+
+func TestFenwickTree(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := fenwick.NewBuilder[int]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, 0, tree.RangeSum())
+		must.True(t, tree.LowerBound(1).IsNone())
+	})
+
+	t.Run("Can build with size", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := fenwick.NewBuilder[float64]().
+			Size(3).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 3, tree.Len())
+		must.Eq(t, 0.0, tree.RangeSum())
+	})
+
+	t.Run("Sums work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := fenwick.NewBuilder[int]().
+			From(3, 1, 4, 1, 5, 9, 2, 6).
+			Build()
+
+		// SCENARIO: Get
+		t.Run("Get", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, tree.Get(0).Unwrap())
+			must.Eq(t, 9, tree.Get(5).Unwrap())
+			must.True(t, tree.Get(8).IsNone())
+		})
+
+		// SCENARIO: PrefixSum
+		t.Run("PrefixSum", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, tree.PrefixSum(0))
+			must.Eq(t, 14, tree.PrefixSum(4))
+			must.Eq(t, 31, tree.PrefixSum(7))
+		})
+		t.Run("PrefixSum - clamped", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 0, tree.PrefixSum(-1))
+			must.Eq(t, 31, tree.PrefixSum(100))
+		})
+
+		// SCENARIO: RangeSum
+		t.Run("RangeSum - inclusive bounds", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 1+4+1, tree.RangeSum(where.From(1), where.To(3)))
+		})
+		t.Run("RangeSum - exclusive bounds", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 4+1, tree.RangeSum(where.FromExclusive(1), where.ToExclusive(4)))
+		})
+		t.Run("RangeSum - open ended", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 31, tree.RangeSum())
+			must.Eq(t, 2+6, tree.RangeSum(where.From(6)))
+		})
+	})
+
+	t.Run("Add works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := fenwick.NewBuilder[int]().
+			From(1, 2, 3, 4).
+			Build()
+		// ========= [A]ct     =========
+		added := tree.Add(1, 10)
+		missing := tree.Add(4, 10)
+		// ========= [A]ssert  =========
+		must.True(t, added)
+		must.False(t, missing)
+		must.Eq(t, 12, tree.Get(1).Unwrap())
+		must.Eq(t, 20, tree.RangeSum())
+		must.Eq(t, 13, tree.PrefixSum(1))
+	})
+
+	t.Run("LowerBound works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		// Prefix sums are 2, 2, 5, 6, 10
+		tree := fenwick.NewBuilder[int]().
+			From(2, 0, 3, 1, 4).
+			Build()
+
+		// SCENARIO: exact prefix
+		t.Run("LowerBound - exact", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 0, tree.LowerBound(2).Unwrap())
+			must.Eq(t, 3, tree.LowerBound(6).Unwrap())
+		})
+		// SCENARIO: between prefixes
+		t.Run("LowerBound - between", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, tree.LowerBound(3).Unwrap())
+			must.Eq(t, 4, tree.LowerBound(7).Unwrap())
+		})
+		// SCENARIO: beyond the total
+		t.Run("LowerBound - exceeds total", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.LowerBound(11).IsNone())
+		})
+		// SCENARIO: weighted sampling picks each index in proportion to its weight
+		t.Run("LowerBound - sampling", func(t *testing.T) {
+			// ========= [A]rrange =========
+			counts := make([]int, tree.Len())
+			// ========= [A]ct     =========
+			for ticket := 1; ticket <= tree.RangeSum(); ticket++ {
+				counts[tree.LowerBound(ticket).Unwrap()]++
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 0, 3, 1, 4}, counts)
+		})
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(2, 7))
+		expected := make([]int, 100)
+		tree := fenwick.NewBuilder[int]().Size(len(expected)).Build()
+		// ========= [A]ct     =========
+		for range 2_000 {
+			index := rng.IntN(len(expected))
+			delta := rng.IntN(50)
+			tree.Add(index, delta)
+			expected[index] += delta
+		}
+		// ========= [A]ssert  =========
+		var total int
+		for index, value := range expected {
+			total += value
+			must.Eq(t, value, tree.Get(index).Unwrap())
+			must.Eq(t, total, tree.PrefixSum(index))
+			if value > 0 {
+				must.Eq(t, index, tree.LowerBound(total).Unwrap())
+			}
+		}
+	})
+}
+
+func TestFenwickTree2D(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := fenwick.New2DBuilder[int]().
+			Size(2, 3).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 2, tree.Rows())
+		must.Eq(t, 3, tree.Cols())
+		must.Eq(t, 0, tree.RangeSum(nil, nil))
+	})
+
+	t.Run("Can build from rows", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := fenwick.New2DBuilder[int]().
+			From(
+				[]int{1, 2},
+				[]int{3, 4, 5},
+			).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 2, tree.Rows())
+		must.Eq(t, 3, tree.Cols())
+		must.Eq(t, 0, tree.Get(0, 2).Unwrap())
+		must.Eq(t, 15, tree.RangeSum(nil, nil))
+	})
+
+	t.Run("Sums work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := fenwick.New2DBuilder[int]().
+			From(
+				[]int{1, 2, 3, 4},
+				[]int{5, 6, 7, 8},
+				[]int{9, 10, 11, 12},
+			).
+			Build()
+
+		// SCENARIO: Get
+		t.Run("Get", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 7, tree.Get(1, 2).Unwrap())
+			must.True(t, tree.Get(3, 0).IsNone())
+		})
+
+		// SCENARIO: PrefixSum
+		t.Run("PrefixSum", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 1+2+5+6, tree.PrefixSum(1, 1))
+			must.Eq(t, 78, tree.PrefixSum(10, 10))
+			must.Eq(t, 0, tree.PrefixSum(-1, 2))
+		})
+
+		// SCENARIO: RangeSum
+		t.Run("RangeSum", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.RangeSum(
+				[]where.WhereOption[int]{where.From(1)},
+				[]where.WhereOption[int]{where.From(1), where.ToExclusive(3)},
+			)
+			// ========= [A]ssert  =========
+			must.Eq(t, 6+7+10+11, actual)
+		})
+		t.Run("RangeSum - single dimension", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 5+6+7+8, tree.RangeSum([]where.WhereOption[int]{where.From(1), where.To(1)}, nil))
+			must.Eq(t, 4+8+12, tree.RangeSum(nil, []where.WhereOption[int]{where.From(3)}))
+		})
+	})
+
+	t.Run("Add works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := fenwick.New2DBuilder[int]().
+			Size(3, 3).
+			Build()
+		// ========= [A]ct     =========
+		added := tree.Add(1, 1, 5)
+		tree.Add(2, 0, 2)
+		missing := tree.Add(0, 3, 1)
+		// ========= [A]ssert  =========
+		must.True(t, added)
+		must.False(t, missing)
+		must.Eq(t, 5, tree.Get(1, 1).Unwrap())
+		must.Eq(t, 7, tree.RangeSum(nil, nil))
+		must.Eq(t, 5, tree.PrefixSum(1, 2))
+	})
+}
@@ -0,0 +1,44 @@
+package intervaltree
+
+import (
+	"cmp"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/interval"
+	"codeberg.org/yaadata/bina/core/kv"
+	intervaltree "codeberg.org/yaadata/bina/internal/interval_tree"
+)
+
+// NewBuiltinBuilder returns a [Builder] for creating a [collection.IntervalTree] with ordered end points.
+func NewBuiltinBuilder[K cmp.Ordered, V any]() Builder[K, V, collection.IntervalTree[K, V], *comparatorBuilder[K, V]] {
+	return NewComparatorBuilder[K, V](compare.Builtin[K])
+}
+
+// NewComparatorBuilder returns a [Builder] for creating a [collection.IntervalTree] whose end points are ordered by fn.
+func NewComparatorBuilder[K any, V any](fn func(a, b K) compare.Order) Builder[K, V, collection.IntervalTree[K, V], *comparatorBuilder[K, V]] {
+	return &comparatorBuilder[K, V]{
+		compare: fn,
+		from:    None[[]kv.Pair[interval.Interval[K], V]](),
+	}
+}
+
+type comparatorBuilder[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	from    Option[[]kv.Pair[interval.Interval[K], V]]
+}
+
+func (b *comparatorBuilder[K, V]) From(pairs ...kv.Pair[interval.Interval[K], V]) *comparatorBuilder[K, V] {
+	b.from = Some(pairs)
+	return b
+}
+
+func (b *comparatorBuilder[K, V]) Build() collection.IntervalTree[K, V] {
+	resp := intervaltree.New[K, V](b.compare)
+	for _, pair := range b.from.UnwrapOrDefault() {
+		resp.Insert(pair.Key(), pair.Value())
+	}
+	return resp
+}
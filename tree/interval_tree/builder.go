@@ -0,0 +1,16 @@
+package intervaltree
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/interval"
+	"codeberg.org/yaadata/bina/core/kv"
+)
+
+// Builder defines the fluent interface for constructing interval trees.
+// Use [NewBuiltinBuilder] or [NewComparatorBuilder] to obtain one.
+type Builder[K any, V any, Target collection.IntervalTree[K, V], Self Builder[K, V, Target, Self]] interface {
+	// Build constructs and returns the target interval tree.
+	Build() Target
+	// From initializes the tree with the given interval-payload pairs.
+	From(pairs ...kv.Pair[interval.Interval[K], V]) Self
+}
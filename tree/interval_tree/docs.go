@@ -0,0 +1,19 @@
+// Package intervaltree implements [collection.IntervalTree].
+//
+// Intervals are built with the [interval] package. [interval.New] gives the
+// usual half-open [start, end), while [interval.WithBounds] accepts any
+// combination of [where.Bound] end points:
+//
+//	tree := intervaltree.NewBuiltinBuilder[int, string]().Build()
+//	tree.Insert(interval.New(9, 12), "standup")
+//	tree.Insert(interval.Closed(11, 13), "lunch")
+//	for iv, name := range tree.Containing(11) {
+//		...
+//	}
+package intervaltree
+
+import (
+	_ "codeberg.org/yaadata/bina/core/collection"
+	_ "codeberg.org/yaadata/bina/core/interval"
+	_ "codeberg.org/yaadata/bina/core/where"
+)
@@ -0,0 +1,243 @@
+package intervaltree_test
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/interval"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/where"
+	intervaltree "codeberg.org/yaadata/bina/tree/interval_tree"
+)
+
+func values[K any, V any](seq iter.Seq2[K, V]) []V {
+	var res []V
+	for _, value := range seq {
+		res = append(res, value)
+	}
+	return res
+}
+
+func TestIntervalTreeBuiltin(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := intervaltree.NewBuiltinBuilder[int, string]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.True(t, tree.IsEmpty())
+		must.SliceEmpty(t, values(tree.Containing(1)))
+	})
+
+	t.Run("Can build from pairs", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := intervaltree.NewBuiltinBuilder[int, string]().
+			From(
+				kv.New(interval.New(5, 8), "c"),
+				kv.New(interval.New(1, 3), "a"),
+				kv.New(interval.New(1, 6), "b"),
+			).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 3, tree.Len())
+		must.Eq(t, []string{"a", "b", "c"}, values(tree.All()))
+	})
+
+	t.Run("Insert, Get and Delete work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := intervaltree.NewBuiltinBuilder[int, string]().
+			Build()
+		tree.Insert(interval.New(1, 5), "first")
+		tree.Insert(interval.Closed(1, 5), "closed")
+
+		// SCENARIO: bounds distinguish intervals
+		t.Run("Get - bounds", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, tree.Len())
+			must.Eq(t, "first", tree.Get(interval.New(1, 5)).Unwrap())
+			must.Eq(t, "closed", tree.Get(interval.Closed(1, 5)).Unwrap())
+			must.True(t, tree.Get(interval.Open(1, 5)).IsNone())
+		})
+		// SCENARIO: identical interval replaces payload
+		t.Run("Insert - replace", func(t *testing.T) {
+			// ========= [A]ct     =========
+			tree.Insert(interval.New(1, 5), "second")
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, tree.Len())
+			must.Eq(t, "second", tree.Get(interval.New(1, 5)).Unwrap())
+		})
+		// SCENARIO: Delete
+		t.Run("Delete", func(t *testing.T) {
+			// ========= [A]ct     =========
+			deleted := tree.Delete(interval.Closed(1, 5))
+			missing := tree.Delete(interval.Closed(1, 5))
+			// ========= [A]ssert  =========
+			must.Eq(t, "closed", deleted.Unwrap())
+			must.True(t, missing.IsNone())
+			must.Eq(t, 1, tree.Len())
+			must.False(t, tree.Contains(interval.Closed(1, 5)))
+			must.True(t, tree.Contains(interval.New(1, 5)))
+		})
+	})
+
+	t.Run("Overlapping works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := intervaltree.NewBuiltinBuilder[int, string]().
+			From(
+				kv.New(interval.New(0, 10), "morning"),
+				kv.New(interval.New(10, 20), "afternoon"),
+				kv.New(interval.Closed(20, 30), "evening"),
+				kv.New(interval.Open(30, 40), "night"),
+				kv.New(interval.New(5, 25), "long"),
+			).
+			Build()
+
+		// SCENARIO: half-open intervals touching end to start do not overlap
+		t.Run("Overlapping - adjacent", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"long", "afternoon"}, values(tree.Overlapping(interval.New(10, 11))))
+			must.Eq(t, []string{"morning"}, values(tree.Overlapping(interval.New(-5, 1))))
+		})
+		// SCENARIO: closed and open end points
+		t.Run("Overlapping - bounds", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"evening"}, values(tree.Overlapping(interval.Point(30))))
+			must.SliceEmpty(t, values(tree.Overlapping(interval.Point(40))))
+			must.Eq(t, []string{"evening", "night"}, values(tree.Overlapping(interval.WithBounds(30, where.BoundInclusive, 31, where.BoundExclusive))))
+			must.Eq(t, []string{"night"}, values(tree.Overlapping(interval.WithBounds(30, where.BoundExclusive, 31, where.BoundExclusive))))
+		})
+		// SCENARIO: wide query
+		t.Run("Overlapping - all", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"morning", "long", "afternoon", "evening", "night"}, values(tree.Overlapping(interval.New(-100, 100))))
+		})
+		// SCENARIO: empty query
+		t.Run("Overlapping - empty query", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.SliceEmpty(t, values(tree.Overlapping(interval.New(15, 15))))
+			must.SliceEmpty(t, values(tree.Overlapping(interval.New(15, 5))))
+		})
+		// SCENARIO: early termination
+		t.Run("Overlapping - break", func(t *testing.T) {
+			// ========= [A]rrange =========
+			var seen []string
+			// ========= [A]ct     =========
+			for _, name := range tree.Overlapping(interval.New(0, 100)) {
+				seen = append(seen, name)
+				if len(seen) == 2 {
+					break
+				}
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"morning", "long"}, seen)
+		})
+	})
+
+	t.Run("Containing works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		// Firewall rules over port ranges
+		tree := intervaltree.NewBuiltinBuilder[int, string]().
+			From(
+				kv.New(interval.Closed(0, 1023), "system"),
+				kv.New(interval.Closed(1024, 49151), "registered"),
+				kv.New(interval.Closed(8000, 8999), "dev"),
+				kv.New(interval.Closed(443, 443), "https"),
+			).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"system", "https"}, values(tree.Containing(443)))
+		must.Eq(t, []string{"system"}, values(tree.Containing(1023)))
+		must.Eq(t, []string{"registered", "dev"}, values(tree.Containing(8080)))
+		must.SliceEmpty(t, values(tree.Containing(50000)))
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := intervaltree.NewBuiltinBuilder[int, int]().
+			From(
+				kv.New(interval.New(0, 2), 1),
+				kv.New(interval.New(1, 4), 2),
+				kv.New(interval.New(3, 9), 3),
+			).
+			Build()
+		isLong := func(pair kv.Pair[interval.Interval[int], int]) bool {
+			return pair.Key().End().Key()-pair.Key().Start().Key() > 2
+		}
+		// ========= [A]ssert  =========
+		must.True(t, tree.Any(isLong))
+		must.False(t, tree.Every(isLong))
+		must.Eq(t, 2, tree.Count(isLong))
+		var total int
+		tree.ForEach(func(pair kv.Pair[interval.Interval[int], int]) {
+			total += pair.Value()
+		})
+		must.Eq(t, 6, total)
+		tree.Clear()
+		must.True(t, tree.IsEmpty())
+	})
+}
+
+func TestIntervalTreeComparator(t *testing.T) {
+	// ========= [A]rrange =========
+	// Case-insensitive alphabetical ranges
+	tree := intervaltree.NewComparatorBuilder[string, int](func(a, b string) compare.Order {
+		return compare.Builtin(strings.ToLower(a), strings.ToLower(b))
+	}).
+		From(
+			kv.New(interval.New("A", "M"), 1),
+			kv.New(interval.New("m", "z"), 2),
+		).
+		Build()
+	// ========= [A]ssert  =========
+	must.Eq(t, []int{1}, values(tree.Containing("cat")))
+	must.Eq(t, []int{2}, values(tree.Containing("Mouse")))
+	must.Eq(t, 1, tree.Get(interval.New("a", "m")).Unwrap())
+}
+
+func TestIntervalTreeRandomOperations(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(3, 4))
+	bounds := []where.Bound{where.BoundInclusive, where.BoundExclusive}
+	randomInterval := func() interval.Interval[int] {
+		start := rng.IntN(100)
+		return interval.WithBounds(start, bounds[rng.IntN(2)], start+rng.IntN(20), bounds[rng.IntN(2)])
+	}
+	tree := intervaltree.NewBuiltinBuilder[int, int]().Build()
+	expected := map[interval.Interval[int]]int{}
+	// ========= [A]ct     =========
+	for step := range 3_000 {
+		iv := randomInterval()
+		switch rng.IntN(3) {
+		case 0, 1:
+			tree.Insert(iv, step)
+			expected[iv] = step
+		default:
+			if stored, ok := expected[iv]; ok {
+				must.Eq(t, stored, tree.Delete(iv).Unwrap())
+				delete(expected, iv)
+			} else {
+				must.True(t, tree.Delete(iv).IsNone())
+			}
+		}
+		// ========= [A]ssert  =========
+		query := randomInterval()
+		var want []int
+		for stored, value := range expected {
+			if stored.Overlaps(query, compare.Builtin[int]) {
+				want = append(want, value)
+			}
+		}
+		got := values(tree.Overlapping(query))
+		slices.Sort(want)
+		slices.Sort(got)
+		must.Eq(t, want, got)
+	}
+	// ========= [A]ssert  =========
+	must.Eq(t, len(expected), tree.Len())
+}
@@ -0,0 +1,30 @@
+package kdtree
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/internal/kdtree"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.KDTree] over points in space.
+func NewBuilder[P any](space Space[P]) Builder[P, collection.KDTree[P], *builder[P]] {
+	return &builder[P]{
+		space: space,
+		from:  None[[]P](),
+	}
+}
+
+type builder[P any] struct {
+	space Space[P]
+	from  Option[[]P]
+}
+
+func (b *builder[P]) From(points ...P) *builder[P] {
+	b.from = Some(points)
+	return b
+}
+
+func (b *builder[P]) Build() collection.KDTree[P] {
+	return kdtree.New(b.from.UnwrapOrDefault(), b.space.Dimensions, b.space.Coordinate, b.space.Distance)
+}
@@ -0,0 +1,14 @@
+package kdtree
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// Builder defines the fluent interface for constructing K-D trees.
+// Use [NewBuilder] to obtain one.
+type Builder[P any, Target collection.KDTree[P], Self Builder[P, Target, Self]] interface {
+	// Build constructs and returns the target K-D tree, balanced over the initial points.
+	Build() Target
+	// From initializes the tree with the given points.
+	From(points ...P) Self
+}
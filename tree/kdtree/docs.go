@@ -0,0 +1,17 @@
+// Package kdtree implements [collection.KDTree].
+//
+// A [Space] tells the tree how many dimensions a point type has, how to read
+// each coordinate and how to measure distance. [Euclidean] covers plain
+// vectors:
+//
+//	tree := kdtree.NewBuilder(kdtree.Euclidean[float64](3)).
+//		From(points...).
+//		Build()
+//	closest := tree.Nearest([]float64{0, 0, 0}, 5)
+//
+// Building from points produces a balanced tree. Insert and Delete keep the
+// tree valid but do not rebalance it, so a tree that has seen many updates
+// may be rebuilt from its points to restore query performance.
+package kdtree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,222 @@
+package kdtree_test
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/tree/kdtree"
+)
+
+func sorted(points [][]int) [][]int {
+	return slices.SortedFunc(slices.Values(points), slices.Compare[[]int])
+}
+
+func TestKDTree(t *testing.T) {
+	grid := [][]int{
+		{2, 3}, {5, 4}, {9, 6}, {4, 7}, {8, 1}, {7, 2},
+	}
+
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := kdtree.NewBuilder(kdtree.Euclidean[float64](3)).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.True(t, tree.IsEmpty())
+		must.SliceEmpty(t, tree.Nearest([]float64{0, 0, 0}, 3))
+	})
+
+	t.Run("Can build balanced from points", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(1, 1))
+		points := make([][]int, 1_000)
+		for i := range points {
+			points[i] = []int{rng.IntN(100), rng.IntN(100)}
+		}
+		// ========= [A]ct     =========
+		tree := kdtree.NewBuilder(kdtree.Euclidean[int](2)).
+			From(points...).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 1_000, tree.Len())
+		must.Eq(t, sorted(points), sorted(slices.Collect(tree.All())))
+		// Duplicate coordinates may push a few points one level lower
+		must.LessEq(t, bits.Len(1_000)+2, tree.Height())
+	})
+
+	t.Run("Queries work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := kdtree.NewBuilder(kdtree.Euclidean[int](2)).
+			From(grid...).
+			Build()
+
+		// SCENARIO: Nearest
+		t.Run("Nearest - single", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, [][]int{{8, 1}}, tree.Nearest([]int{9, 2}, 1))
+		})
+		t.Run("Nearest - ordered by distance", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, [][]int{{5, 4}, {4, 7}, {2, 3}}, tree.Nearest([]int{4, 5}, 3))
+		})
+		t.Run("Nearest - k exceeds size", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Len(t, 6, tree.Nearest([]int{0, 0}, 10))
+			must.SliceEmpty(t, tree.Nearest([]int{0, 0}, 0))
+		})
+
+		// SCENARIO: WithinRadius
+		t.Run("WithinRadius", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := slices.Collect(tree.WithinRadius([]int{7, 2}, math.Sqrt2))
+			// ========= [A]ssert  =========
+			must.Eq(t, [][]int{{7, 2}, {8, 1}}, sorted(actual))
+		})
+
+		// SCENARIO: RangeBox
+		t.Run("RangeBox - inclusive", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := slices.Collect(tree.RangeBox([]int{4, 2}, []int{8, 7}))
+			// ========= [A]ssert  =========
+			must.Eq(t, [][]int{{4, 7}, {5, 4}, {7, 2}}, sorted(actual))
+		})
+		t.Run("RangeBox - empty", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.SliceEmpty(t, slices.Collect(tree.RangeBox([]int{0, 8}, []int{10, 10})))
+		})
+	})
+
+	t.Run("Insert and Delete work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := kdtree.NewBuilder(kdtree.Euclidean[int](2)).
+			From(grid...).
+			Build()
+		// ========= [A]ct     =========
+		tree.Insert([]int{6, 6})
+		root := tree.Delete([]int{7, 2})
+		missing := tree.Delete([]int{1, 1})
+		// ========= [A]ssert  =========
+		must.True(t, root)
+		must.False(t, missing)
+		must.Eq(t, 6, tree.Len())
+		must.True(t, tree.Contains([]int{6, 6}))
+		must.False(t, tree.Contains([]int{7, 2}))
+		must.Eq(t, [][]int{{8, 1}}, tree.Nearest([]int{7, 2}, 1))
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := kdtree.NewBuilder(kdtree.Euclidean[int](2)).
+			From(grid...).
+			Build()
+		right := func(p []int) bool {
+			return p[0] > 5
+		}
+		// ========= [A]ssert  =========
+		must.True(t, tree.Any(right))
+		must.False(t, tree.Every(right))
+		must.Eq(t, 3, tree.Count(right))
+		var count int
+		tree.ForEach(func([]int) {
+			count++
+		})
+		must.Eq(t, 6, count)
+		tree.Clear()
+		must.True(t, tree.IsEmpty())
+	})
+}
+
+func TestKDTreeCustomSpace(t *testing.T) {
+	// ========= [A]rrange =========
+	type city struct {
+		name string
+		x, y float64
+	}
+	space := kdtree.Space[city]{
+		Dimensions: 2,
+		Coordinate: func(c city, axis int) float64 {
+			if axis == 0 {
+				return c.x
+			}
+			return c.y
+		},
+		// Chebyshev distance
+		Distance: func(a, b city) float64 {
+			return max(math.Abs(a.x-b.x), math.Abs(a.y-b.y))
+		},
+	}
+	tree := kdtree.NewBuilder(space).
+		From(
+			city{"north", 0, 10},
+			city{"south", 0, -10},
+			city{"east", 3, 3},
+		).
+		Build()
+	// ========= [A]ct     =========
+	nearest := tree.Nearest(city{x: 1, y: 1}, 1)
+	// ========= [A]ssert  =========
+	must.Eq(t, "east", nearest[0].name)
+	must.True(t, tree.Contains(city{x: 0, y: -10}))
+}
+
+func TestKDTreeRandomOperations(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(9, 9))
+	space := kdtree.Manhattan[int](3)
+	randomPoint := func() []int {
+		return []int{rng.IntN(20), rng.IntN(20), rng.IntN(20)}
+	}
+	var expected [][]int
+	for range 200 {
+		expected = append(expected, randomPoint())
+	}
+	tree := kdtree.NewBuilder(space).From(expected...).Build()
+	// ========= [A]ct     =========
+	for range 2_000 {
+		point := randomPoint()
+		switch rng.IntN(4) {
+		case 0:
+			tree.Insert(point)
+			expected = append(expected, point)
+		case 1:
+			at := slices.IndexFunc(expected, func(p []int) bool {
+				return slices.Equal(p, point)
+			})
+			must.Eq(t, at >= 0, tree.Delete(point))
+			if at >= 0 {
+				expected = slices.Delete(expected, at, at+1)
+			}
+		case 2:
+			// ========= [A]ssert  =========
+			radius := float64(rng.IntN(10))
+			var want [][]int
+			for _, p := range expected {
+				if space.Distance(p, point) <= radius {
+					want = append(want, p)
+				}
+			}
+			must.Eq(t, sorted(want), sorted(slices.Collect(tree.WithinRadius(point, radius))))
+		default:
+			// ========= [A]ssert  =========
+			k := 1 + rng.IntN(5)
+			distances := make([]float64, len(expected))
+			for i, p := range expected {
+				distances[i] = space.Distance(p, point)
+			}
+			slices.Sort(distances)
+			var actual []float64
+			for _, p := range tree.Nearest(point, k) {
+				actual = append(actual, space.Distance(p, point))
+			}
+			must.Eq(t, distances[:min(k, len(distances))], actual)
+		}
+	}
+	// ========= [A]ssert  =========
+	must.Eq(t, len(expected), tree.Len())
+	must.Eq(t, sorted(expected), sorted(slices.Collect(tree.All())))
+}
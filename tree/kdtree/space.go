@@ -0,0 +1,59 @@
+package kdtree
+
+import (
+	"math"
+
+	"codeberg.org/yaadata/bina/core/numeric"
+)
+
+// Space describes the geometry of the point type P stored in a
+// [collection.KDTree].
+type Space[P any] struct {
+	// Dimensions is the number of coordinates of every point.
+	Dimensions int
+	// Coordinate returns the coordinate of p along axis, for axis in
+	// [0, Dimensions).
+	Coordinate func(p P, axis int) float64
+	// Distance returns the distance between a and b. Searches skip subtrees
+	// using the difference along a single axis, so Distance must never be
+	// smaller than that difference. [Euclidean] and [Manhattan] distances
+	// qualify; squared Euclidean distance does not.
+	Distance func(a, b P) float64
+}
+
+// Euclidean returns a [Space] over vectors with the given number of
+// dimensions, measured by straight-line distance.
+func Euclidean[T numeric.Number](dimensions int) Space[[]T] {
+	return Space[[]T]{
+		Dimensions: dimensions,
+		Coordinate: coordinate[T],
+		Distance: func(a, b []T) float64 {
+			var sum float64
+			for axis := range dimensions {
+				diff := float64(a[axis]) - float64(b[axis])
+				sum += diff * diff
+			}
+			return math.Sqrt(sum)
+		},
+	}
+}
+
+// Manhattan returns a [Space] over vectors with the given number of
+// dimensions, measured by the sum of the differences along each axis.
+func Manhattan[T numeric.Number](dimensions int) Space[[]T] {
+	return Space[[]T]{
+		Dimensions: dimensions,
+		Coordinate: coordinate[T],
+		Distance: func(a, b []T) float64 {
+			var sum float64
+			for axis := range dimensions {
+				sum += math.Abs(float64(a[axis]) - float64(b[axis]))
+			}
+			return sum
+		},
+	}
+}
+
+func coordinate[T numeric.Number](p []T, axis int) float64 {
+	return float64(p[axis])
+}
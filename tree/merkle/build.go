@@ -0,0 +1,41 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/internal/merkle"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.MerkleTree].
+func NewBuilder() Builder[collection.MerkleTree, *builder] {
+	return &builder{
+		from:    None[[][]byte](),
+		newHash: None[func() hash.Hash](),
+	}
+}
+
+type builder struct {
+	from    Option[[][]byte]
+	newHash Option[func() hash.Hash]
+}
+
+func (b *builder) From(leaves ...[]byte) *builder {
+	b.from = Some(leaves)
+	return b
+}
+
+func (b *builder) Hash(newHash func() hash.Hash) *builder {
+	b.newHash = Some(newHash)
+	return b
+}
+
+func (b *builder) Build() collection.MerkleTree {
+	newHash := b.newHash.UnwrapOrElse(func() func() hash.Hash {
+		return sha256.New
+	})
+	return merkle.New(newHash, b.from.UnwrapOrDefault())
+}
@@ -0,0 +1,18 @@
+package merkle
+
+import (
+	"hash"
+
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// Builder defines the fluent interface for constructing Merkle trees.
+// Use [NewBuilder] to obtain one.
+type Builder[Target collection.MerkleTree, Self Builder[Target, Self]] interface {
+	// Build constructs and returns the target Merkle tree.
+	Build() Target
+	// From initializes the tree with the given leaves.
+	From(leaves ...[]byte) Self
+	// Hash sets the constructor of the hash function. Default is sha256.New.
+	Hash(newHash func() hash.Hash) Self
+}
@@ -0,0 +1,13 @@
+// Package merkle implements [collection.MerkleTree] with the hashing scheme of
+// RFC 6962, so roots and proofs match Certificate Transparency style logs
+// built on the same hash function.
+//
+// A client that trusts a root can check a single chunk without the rest of
+// the list:
+//
+//	tree := merkle.NewBuilder().From(chunks...).Build()
+//	proof := tree.Proof(3).Unwrap()
+//	ok := merkle.VerifyProof(sha256.New, tree.Root(), chunks[3], 3, tree.Len(), proof)
+package merkle
+
+import _ "codeberg.org/yaadata/bina/core/collection"
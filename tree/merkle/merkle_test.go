@@ -0,0 +1,193 @@
+package merkle_test
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/interval"
+	"codeberg.org/yaadata/bina/tree/merkle"
+)
+
+// leaves are the inputs of the RFC 6962 reference test vectors.
+var leaves = [][]byte{
+	{},
+	{0x00},
+	{0x10},
+	{0x20, 0x21},
+	{0x30, 0x31},
+	{0x40, 0x41, 0x42, 0x43},
+	{0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57},
+	{0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f},
+}
+
+// roots are the expected root hashes after each of the first n leaves.
+var roots = []string{
+	"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+	"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+	"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+	"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+	"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+	"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+	"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+	"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+}
+
+func chunks(n int) [][]byte {
+	res := make([][]byte, n)
+	for i := range res {
+		res[i] = fmt.Appendf(nil, "chunk-%d", i)
+	}
+	return res
+}
+
+func TestMerkleTree(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := merkle.NewBuilder().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, roots[0], hex.EncodeToString(tree.Root()))
+		must.True(t, tree.Proof(0).IsNone())
+	})
+
+	t.Run("Root matches RFC 6962 vectors", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := merkle.NewBuilder().
+			Build()
+		for size, leaf := range leaves {
+			// ========= [A]ct     =========
+			tree.Append(leaf)
+			// ========= [A]ssert  =========
+			must.Eq(t, roots[size+1], hex.EncodeToString(tree.Root()))
+		}
+		for size, root := range roots {
+			must.Eq(t, root, hex.EncodeToString(tree.RootAt(size).Unwrap()))
+		}
+		must.True(t, tree.RootAt(9).IsNone())
+	})
+
+	t.Run("Inclusion proofs work", func(t *testing.T) {
+		for size := 1; size <= 17; size++ {
+			// ========= [A]rrange =========
+			data := chunks(size)
+			tree := merkle.NewBuilder().
+				From(data...).
+				Build()
+			for index := range size {
+				// ========= [A]ct     =========
+				proof := tree.Proof(index).Unwrap()
+				// ========= [A]ssert  =========
+				must.True(t, merkle.VerifyProof(sha256.New, tree.Root(), data[index], index, size, proof))
+				must.False(t, merkle.VerifyProof(sha256.New, tree.Root(), []byte("forged"), index, size, proof))
+				if size > 1 {
+					must.False(t, merkle.VerifyProof(sha256.New, tree.Root(), data[index], (index+1)%size, size, proof))
+				}
+			}
+		}
+	})
+
+	t.Run("Consistency proofs work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		data := chunks(20)
+		tree := merkle.NewBuilder().
+			From(data...).
+			Build()
+		for oldSize := 1; oldSize <= tree.Len(); oldSize++ {
+			// ========= [A]ct     =========
+			proof := tree.ConsistencyProof(oldSize).Unwrap()
+			oldRoot := tree.RootAt(oldSize).Unwrap()
+			// ========= [A]ssert  =========
+			must.True(t, merkle.VerifyConsistency(sha256.New, oldSize, tree.Len(), oldRoot, tree.Root(), proof))
+			forked := merkle.NewBuilder().From(append(chunks(oldSize-1), []byte("fork"))...).Build()
+			must.False(t, merkle.VerifyConsistency(sha256.New, oldSize, tree.Len(), forked.Root(), tree.Root(), proof))
+		}
+		must.True(t, tree.ConsistencyProof(0).IsNone())
+		must.True(t, tree.ConsistencyProof(21).IsNone())
+	})
+
+	t.Run("Returned hashes do not alias the tree", func(t *testing.T) {
+		// ========= [A]rrange =========
+		// Eight leaves make the root and every proof element a stored
+		// complete subtree hash
+		data := chunks(8)
+		tree := merkle.NewBuilder().
+			From(data...).
+			Build()
+		expected := hex.EncodeToString(tree.Root())
+		// ========= [A]ct     =========
+		tree.Root()[0] ^= 0xff
+		tree.RootAt(8).Unwrap()[0] ^= 0xff
+		tree.LeafHash(3).Unwrap()[0] ^= 0xff
+		for _, h := range tree.Proof(3).Unwrap() {
+			h[0] ^= 0xff
+		}
+		for _, h := range tree.ConsistencyProof(4).Unwrap() {
+			h[0] ^= 0xff
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, expected, hex.EncodeToString(tree.Root()))
+		must.True(t, merkle.VerifyProof(sha256.New, tree.Root(), data[3], 3, 8, tree.Proof(3).Unwrap()))
+		must.True(t, merkle.VerifyConsistency(sha256.New, 4, 8, tree.RootAt(4).Unwrap(), tree.Root(), tree.ConsistencyProof(4).Unwrap()))
+	})
+
+	t.Run("Diff works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		data := chunks(13)
+		tree := merkle.NewBuilder().
+			From(data...).
+			Build()
+
+		// SCENARIO: identical trees
+		t.Run("Diff - identical", func(t *testing.T) {
+			// ========= [A]rrange =========
+			other := merkle.NewBuilder().From(data...).Build()
+			// ========= [A]ssert  =========
+			must.SliceEmpty(t, tree.Diff(other))
+		})
+		// SCENARIO: changed leaves are grouped into ranges
+		t.Run("Diff - changed", func(t *testing.T) {
+			// ========= [A]rrange =========
+			changed := chunks(13)
+			changed[2] = []byte("x")
+			changed[3] = []byte("y")
+			changed[9] = []byte("z")
+			other := merkle.NewBuilder().From(changed...).Build()
+			// ========= [A]ct     =========
+			actual := tree.Diff(other)
+			// ========= [A]ssert  =========
+			must.Eq(t, []interval.Interval[int]{interval.New(2, 4), interval.New(9, 10)}, actual)
+		})
+		// SCENARIO: different lengths
+		t.Run("Diff - appended", func(t *testing.T) {
+			// ========= [A]rrange =========
+			other := merkle.NewBuilder().From(chunks(16)...).Build()
+			// ========= [A]ct     =========
+			actual := tree.Diff(other)
+			// ========= [A]ssert  =========
+			must.Eq(t, []interval.Interval[int]{interval.New(13, 16)}, actual)
+			must.Eq(t, actual, other.Diff(tree))
+		})
+	})
+
+	t.Run("Custom hash works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		data := chunks(5)
+		tree := merkle.NewBuilder().
+			Hash(sha512.New).
+			From(data...).
+			Build()
+		// ========= [A]ct     =========
+		proof := tree.Proof(4).Unwrap()
+		// ========= [A]ssert  =========
+		must.Len(t, sha512.Size, tree.Root())
+		must.True(t, merkle.VerifyProof(sha512.New, tree.Root(), data[4], 4, 5, proof))
+		must.False(t, merkle.VerifyProof(sha256.New, tree.Root(), data[4], 4, 5, proof))
+	})
+}
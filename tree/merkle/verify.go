@@ -0,0 +1,22 @@
+package merkle
+
+import (
+	"hash"
+
+	"codeberg.org/yaadata/bina/internal/merkle"
+)
+
+// VerifyProof reports whether proof, as returned by
+// [collection.MerkleTree.Proof], shows that data is the leaf at index of a
+// tree with size leaves and the given root. newHash must match the hash
+// function the tree was built with.
+func VerifyProof(newHash func() hash.Hash, root, data []byte, index, size int, proof [][]byte) bool {
+	return merkle.VerifyInclusion(newHash, root, data, index, size, proof)
+}
+
+// VerifyConsistency reports whether proof, as returned by
+// [collection.MerkleTree.ConsistencyProof], shows that the tree with oldSize
+// leaves and oldRoot is a prefix of the tree with newSize leaves and newRoot.
+func VerifyConsistency(newHash func() hash.Hash, oldSize, newSize int, oldRoot, newRoot []byte, proof [][]byte) bool {
+	return merkle.VerifyConsistency(newHash, oldSize, newSize, oldRoot, newRoot, proof)
+}
@@ -0,0 +1,56 @@
+package octree
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+	"codeberg.org/yaadata/bina/internal/orthtree"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.Octree] covering bounds.
+func NewBuilder[V any](bounds spatial.Box) Builder[V, collection.Octree[V], *builder[V]] {
+	return &builder[V]{
+		bounds:   bounds,
+		from:     None[[]kv.Pair[spatial.Point3, V]](),
+		capacity: None[int](),
+		maxDepth: None[int](),
+	}
+}
+
+type builder[V any] struct {
+	bounds   spatial.Box
+	from     Option[[]kv.Pair[spatial.Point3, V]]
+	capacity Option[int]
+	maxDepth Option[int]
+}
+
+func (b *builder[V]) From(pairs ...kv.Pair[spatial.Point3, V]) *builder[V] {
+	b.from = Some(pairs)
+	return b
+}
+
+func (b *builder[V]) Capacity(capacity int) *builder[V] {
+	b.capacity = Some(capacity)
+	return b
+}
+
+func (b *builder[V]) MaxDepth(maxDepth int) *builder[V] {
+	b.maxDepth = Some(maxDepth)
+	return b
+}
+
+func (b *builder[V]) Build() collection.Octree[V] {
+	capacity := b.capacity.UnwrapOrElse(func() int {
+		return 8
+	})
+	maxDepth := b.maxDepth.UnwrapOrElse(func() int {
+		return 16
+	})
+	resp := orthtree.NewOctree[V](b.bounds, capacity, maxDepth)
+	for _, pair := range b.from.UnwrapOrDefault() {
+		resp.Insert(pair.Key(), pair.Value())
+	}
+	return resp
+}
@@ -0,0 +1,18 @@
+// Package octree implements [collection.Octree].
+//
+// An octree is the three-dimensional counterpart of a quad tree: it covers a
+// fixed box and splits any cell holding more than its capacity into eight
+// octants.
+//
+//	tree := octree.NewBuilder[int](spatial.NewBox(spatial.Point3{}, spatial.Point3{X: 64, Y: 64, Z: 64})).
+//		Build()
+//	tree.Insert(spatial.Point3{X: 1, Y: 2, Z: 3}, 42)
+//
+// [collection.Orthtree.Leaves] yields the region of every cell for drawing
+// the partitioning.
+package octree
+
+import (
+	_ "codeberg.org/yaadata/bina/core/collection"
+	_ "codeberg.org/yaadata/bina/core/spatial"
+)
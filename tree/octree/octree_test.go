@@ -0,0 +1,111 @@
+package octree_test
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+	"codeberg.org/yaadata/bina/tree/octree"
+)
+
+func TestOctree(t *testing.T) {
+	bounds := spatial.NewBox(spatial.Point3{}, spatial.Point3{X: 8, Y: 8, Z: 8})
+
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := octree.NewBuilder[string](bounds).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, bounds, tree.Bounds())
+	})
+
+	t.Run("Splits into octants", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := octree.NewBuilder[string](bounds).
+			Capacity(1).
+			Build()
+		// ========= [A]ct     =========
+		tree.Insert(spatial.Point3{X: 1, Y: 1, Z: 1}, "low")
+		tree.Insert(spatial.Point3{X: 7, Y: 7, Z: 7}, "high")
+		outside := tree.Insert(spatial.Point3{X: 9, Y: 1, Z: 1}, "outside")
+		// ========= [A]ssert  =========
+		must.False(t, outside)
+		var leaves []spatial.Box
+		for region, count := range tree.Leaves() {
+			must.LessEq(t, 1, count)
+			leaves = append(leaves, region)
+		}
+		must.Len(t, 8, leaves)
+		must.SliceContains(t, leaves, spatial.NewBox(spatial.Point3{X: 4, Y: 4, Z: 4}, spatial.Point3{X: 8, Y: 8, Z: 8}))
+	})
+
+	t.Run("Queries work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := octree.NewBuilder[string](bounds).
+			From(
+				kv.New(spatial.Point3{X: 1, Y: 1, Z: 1}, "a"),
+				kv.New(spatial.Point3{X: 2, Y: 2, Z: 6}, "b"),
+				kv.New(spatial.Point3{X: 6, Y: 6, Z: 6}, "c"),
+			).
+			Capacity(1).
+			Build()
+		// ========= [A]ct     =========
+		var found []string
+		for _, name := range tree.Query(spatial.NewBox(spatial.Point3{}, spatial.Point3{X: 3, Y: 3, Z: 8})) {
+			found = append(found, name)
+		}
+		nearest := tree.Nearest(spatial.Point3{X: 5, Y: 5, Z: 5}, 1)
+		// ========= [A]ssert  =========
+		slices.Sort(found)
+		must.Eq(t, []string{"a", "b"}, found)
+		must.Eq(t, "c", nearest[0].Value())
+	})
+
+	t.Run("Remove works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		point := spatial.Point3{X: 3, Y: 3, Z: 3}
+		tree := octree.NewBuilder[string](bounds).
+			From(kv.New(point, "voxel")).
+			Build()
+		// ========= [A]ct     =========
+		removed := tree.Remove(point)
+		// ========= [A]ssert  =========
+		must.Eq(t, "voxel", removed.Unwrap())
+		must.True(t, tree.IsEmpty())
+		must.True(t, tree.Remove(point).IsNone())
+	})
+}
+
+func TestOctreeRandomNearest(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(8, 1))
+	tree := octree.NewBuilder[int](spatial.NewBox(spatial.Point3{}, spatial.Point3{X: 8, Y: 8, Z: 8})).
+		Capacity(2).
+		Build()
+	var points []spatial.Point3
+	for i := range 500 {
+		point := spatial.Point3{X: rng.Float64() * 8, Y: rng.Float64() * 8, Z: rng.Float64() * 8}
+		tree.Insert(point, i)
+		points = append(points, point)
+	}
+	target := spatial.Point3{X: 4, Y: 1, Z: 7}
+	distance := func(p spatial.Point3) float64 {
+		return spatial.NewBox(p, p).Distance(target)
+	}
+	// ========= [A]ct     =========
+	nearest := tree.Nearest(target, 5)
+	// ========= [A]ssert  =========
+	slices.SortFunc(points, func(a, b spatial.Point3) int {
+		return cmp.Compare(distance(a), distance(b))
+	})
+	must.Len(t, 5, nearest)
+	for i, pair := range nearest {
+		must.Eq(t, distance(points[i]), distance(pair.Key()))
+	}
+}
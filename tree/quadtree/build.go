@@ -0,0 +1,56 @@
+package quadtree
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+	"codeberg.org/yaadata/bina/internal/orthtree"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.QuadTree] covering bounds.
+func NewBuilder[V any](bounds spatial.Rect) Builder[V, collection.QuadTree[V], *builder[V]] {
+	return &builder[V]{
+		bounds:   bounds,
+		from:     None[[]kv.Pair[spatial.Point, V]](),
+		capacity: None[int](),
+		maxDepth: None[int](),
+	}
+}
+
+type builder[V any] struct {
+	bounds   spatial.Rect
+	from     Option[[]kv.Pair[spatial.Point, V]]
+	capacity Option[int]
+	maxDepth Option[int]
+}
+
+func (b *builder[V]) From(pairs ...kv.Pair[spatial.Point, V]) *builder[V] {
+	b.from = Some(pairs)
+	return b
+}
+
+func (b *builder[V]) Capacity(capacity int) *builder[V] {
+	b.capacity = Some(capacity)
+	return b
+}
+
+func (b *builder[V]) MaxDepth(maxDepth int) *builder[V] {
+	b.maxDepth = Some(maxDepth)
+	return b
+}
+
+func (b *builder[V]) Build() collection.QuadTree[V] {
+	capacity := b.capacity.UnwrapOrElse(func() int {
+		return 8
+	})
+	maxDepth := b.maxDepth.UnwrapOrElse(func() int {
+		return 16
+	})
+	resp := orthtree.NewQuadTree[V](b.bounds, capacity, maxDepth)
+	for _, pair := range b.from.UnwrapOrDefault() {
+		resp.Insert(pair.Key(), pair.Value())
+	}
+	return resp
+}
@@ -0,0 +1,22 @@
+package quadtree
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// Builder defines the fluent interface for constructing quad trees.
+// Use [NewBuilder] to obtain one.
+type Builder[V any, Target collection.QuadTree[V], Self Builder[V, Target, Self]] interface {
+	// Build constructs and returns the target quad tree.
+	Build() Target
+	// From initializes the tree with the given point-payload pairs. Points
+	// outside the bounds are skipped.
+	From(pairs ...kv.Pair[spatial.Point, V]) Self
+	// Capacity sets how many points a cell holds before it splits. Default is 8.
+	Capacity(capacity int) Self
+	// MaxDepth sets how many times a cell may split. Cells at this depth
+	// hold any number of points. Default is 16.
+	MaxDepth(maxDepth int) Self
+}
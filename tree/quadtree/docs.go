@@ -0,0 +1,22 @@
+// Package quadtree implements [collection.QuadTree].
+//
+// A quad tree covers a fixed rectangle and splits any cell holding more than
+// its capacity into four quadrants, which suits broad-phase collision checks
+// over points that cluster:
+//
+//	tree := quadtree.NewBuilder[string](spatial.NewRect(0, 0, 1024, 1024)).
+//		Capacity(4).
+//		Build()
+//	tree.Insert(spatial.Point{X: 10, Y: 20}, "player")
+//	for point, name := range tree.Query(spatial.NewRect(0, 0, 64, 64)) {
+//		...
+//	}
+//
+// [collection.Orthtree.Leaves] yields the region of every cell for drawing
+// the partitioning.
+package quadtree
+
+import (
+	_ "codeberg.org/yaadata/bina/core/collection"
+	_ "codeberg.org/yaadata/bina/core/spatial"
+)
@@ -0,0 +1,236 @@
+package quadtree_test
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+	"codeberg.org/yaadata/bina/tree/quadtree"
+)
+
+func values[K any, V cmp.Ordered](seq iter.Seq2[K, V]) []V {
+	var res []V
+	for _, value := range seq {
+		res = append(res, value)
+	}
+	slices.Sort(res)
+	return res
+}
+
+func collect[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
+	res := map[K]V{}
+	for key, value := range seq {
+		res[key] = value
+	}
+	return res
+}
+
+func TestQuadTree(t *testing.T) {
+	bounds := spatial.NewRect(0, 0, 100, 100)
+
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := quadtree.NewBuilder[string](bounds).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, bounds, tree.Bounds())
+		must.Eq(t, map[spatial.Rect]int{bounds: 0}, collect(tree.Leaves()))
+	})
+
+	t.Run("Insert works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := quadtree.NewBuilder[string](bounds).
+			Capacity(2).
+			Build()
+		// ========= [A]ct     =========
+		inside := tree.Insert(spatial.Point{X: 10, Y: 10}, "a")
+		tree.Insert(spatial.Point{X: 60, Y: 10}, "b")
+		tree.Insert(spatial.Point{X: 50, Y: 50}, "c")
+		outside := tree.Insert(spatial.Point{X: 101, Y: 0}, "d")
+		// ========= [A]ssert  =========
+		must.True(t, inside)
+		must.False(t, outside)
+		must.Eq(t, 3, tree.Len())
+		// The center point belongs to the upper quadrant
+		must.Eq(t, map[spatial.Rect]int{
+			spatial.NewRect(0, 0, 50, 50):     1,
+			spatial.NewRect(50, 0, 100, 50):   1,
+			spatial.NewRect(0, 50, 50, 100):   0,
+			spatial.NewRect(50, 50, 100, 100): 1,
+		}, collect(tree.Leaves()))
+	})
+
+	t.Run("Max depth stops splitting", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := quadtree.NewBuilder[int](bounds).
+			Capacity(1).
+			MaxDepth(2).
+			Build()
+		// ========= [A]ct     =========
+		for i := range 10 {
+			tree.Insert(spatial.Point{X: 1, Y: 1}, i)
+		}
+		// ========= [A]ssert  =========
+		leaves := collect(tree.Leaves())
+		must.MapLen(t, 7, leaves)
+		must.Eq(t, 10, leaves[spatial.NewRect(0, 0, 25, 25)])
+	})
+
+	t.Run("Queries work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := quadtree.NewBuilder[string](bounds).
+			From(
+				kv.New(spatial.Point{X: 10, Y: 10}, "a"),
+				kv.New(spatial.Point{X: 20, Y: 20}, "b"),
+				kv.New(spatial.Point{X: 80, Y: 80}, "c"),
+				kv.New(spatial.Point{X: 50, Y: 50}, "d"),
+				kv.New(spatial.Point{X: 90, Y: 10}, "e"),
+			).
+			Capacity(1).
+			Build()
+
+		// SCENARIO: Query
+		t.Run("Query - region", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"a", "b"}, values(tree.Query(spatial.NewRect(0, 0, 30, 30))))
+		})
+		t.Run("Query - inclusive edges", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"b", "d"}, values(tree.Query(spatial.NewRect(20, 20, 50, 50))))
+			must.SliceEmpty(t, values(tree.Query(spatial.NewRect(30, 60, 40, 70))))
+		})
+
+		// SCENARIO: Nearest
+		t.Run("Nearest", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Nearest(spatial.Point{X: 45, Y: 45}, 2)
+			// ========= [A]ssert  =========
+			must.Len(t, 2, actual)
+			must.Eq(t, "d", actual[0].Value())
+			must.Eq(t, "b", actual[1].Value())
+		})
+		t.Run("Nearest - outside bounds", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, "e", tree.Nearest(spatial.Point{X: 200, Y: 0}, 1)[0].Value())
+		})
+	})
+
+	t.Run("Remove works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := quadtree.NewBuilder[string](bounds).
+			Capacity(2).
+			Build()
+		shared := spatial.Point{X: 70, Y: 70}
+		tree.Insert(shared, "x")
+		tree.Insert(shared, "y")
+		tree.Insert(spatial.Point{X: 10, Y: 10}, "z")
+
+		// SCENARIO: RemoveFunc picks the payload
+		t.Run("RemoveFunc", func(t *testing.T) {
+			// ========= [A]ct     =========
+			removed := tree.RemoveFunc(shared, func(name string) bool {
+				return name == "y"
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, "y", removed.Unwrap())
+			must.Eq(t, []string{"x"}, values(tree.Query(spatial.PointRect(shared))))
+		})
+		// SCENARIO: cells merge once they fit in their parent
+		t.Run("Remove - merges cells", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, map[spatial.Rect]int{bounds: 2}, collect(tree.Leaves()))
+		})
+		// SCENARIO: Remove
+		t.Run("Remove", func(t *testing.T) {
+			// ========= [A]ct     =========
+			removed := tree.Remove(shared)
+			missing := tree.Remove(shared)
+			// ========= [A]ssert  =========
+			must.Eq(t, "x", removed.Unwrap())
+			must.True(t, missing.IsNone())
+			must.False(t, tree.Contains(shared))
+			must.True(t, tree.Contains(spatial.Point{X: 10, Y: 10}))
+			must.Eq(t, 1, tree.Len())
+		})
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := quadtree.NewBuilder[int](bounds).
+			From(
+				kv.New(spatial.Point{X: 1, Y: 1}, 1),
+				kv.New(spatial.Point{X: 2, Y: 2}, 2),
+				kv.New(spatial.Point{X: 3, Y: 3}, 3),
+			).
+			Build()
+		odd := func(pair kv.Pair[spatial.Point, int]) bool {
+			return pair.Value()%2 == 1
+		}
+		// ========= [A]ssert  =========
+		must.True(t, tree.Any(odd))
+		must.False(t, tree.Every(odd))
+		must.Eq(t, 2, tree.Count(odd))
+		var total int
+		tree.ForEach(func(pair kv.Pair[spatial.Point, int]) {
+			total += pair.Value()
+		})
+		must.Eq(t, 6, total)
+		tree.Clear()
+		must.True(t, tree.IsEmpty())
+		must.Eq(t, bounds, tree.Bounds())
+	})
+}
+
+func TestQuadTreeRandomOperations(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(4, 4))
+	randomPoint := func() spatial.Point {
+		return spatial.Point{X: float64(rng.IntN(64)), Y: float64(rng.IntN(64))}
+	}
+	tree := quadtree.NewBuilder[int](spatial.NewRect(0, 0, 64, 64)).
+		Capacity(3).
+		Build()
+	expected := map[int]spatial.Point{}
+	// ========= [A]ct     =========
+	for step := range 3_000 {
+		switch rng.IntN(3) {
+		case 0, 1:
+			point := randomPoint()
+			tree.Insert(point, step)
+			expected[step] = point
+		default:
+			for id, point := range expected {
+				removed := tree.RemoveFunc(point, func(value int) bool {
+					return value == id
+				})
+				must.Eq(t, id, removed.Unwrap())
+				delete(expected, id)
+				break
+			}
+		}
+		// ========= [A]ssert  =========
+		region := spatial.NewRect(float64(rng.IntN(64)), float64(rng.IntN(64)), float64(rng.IntN(64)), float64(rng.IntN(64)))
+		var want []int
+		for id, point := range expected {
+			if region.Contains(point) {
+				want = append(want, id)
+			}
+		}
+		slices.Sort(want)
+		must.Eq(t, want, values(tree.Query(region)))
+	}
+	// ========= [A]ssert  =========
+	must.Eq(t, len(expected), tree.Len())
+	var total int
+	for _, count := range tree.Leaves() {
+		total += count
+	}
+	must.Eq(t, len(expected), total)
+}
@@ -0,0 +1,11 @@
+package redblack
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/tree/builder"
+)
+
+// Builder is a [builder.BaseBuilder] for [collection.RedBlackTree] implementations.
+type Builder[K any, V any, Target collection.RedBlackTree[K, V], Self Builder[K, V, Target, Self]] interface {
+	builder.BaseBuilder[K, V, Target, Self]
+}
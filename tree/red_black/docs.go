@@ -0,0 +1,4 @@
+// Package redblack implements [collection.RedBlackTree].
+package redblack
+
+import _ "codeberg.org/yaadata/bina/core/collection"
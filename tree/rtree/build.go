@@ -0,0 +1,40 @@
+package rtree
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+	"codeberg.org/yaadata/bina/internal/rtree"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.RTree].
+func NewBuilder[V any]() Builder[V, collection.RTree[V], *builder[V]] {
+	return &builder[V]{
+		from:       None[[]kv.Pair[spatial.Rect, V]](),
+		maxEntries: None[int](),
+	}
+}
+
+type builder[V any] struct {
+	from       Option[[]kv.Pair[spatial.Rect, V]]
+	maxEntries Option[int]
+}
+
+func (b *builder[V]) From(pairs ...kv.Pair[spatial.Rect, V]) *builder[V] {
+	b.from = Some(pairs)
+	return b
+}
+
+func (b *builder[V]) MaxEntries(maxEntries int) *builder[V] {
+	b.maxEntries = Some(maxEntries)
+	return b
+}
+
+func (b *builder[V]) Build() collection.RTree[V] {
+	maxEntries := b.maxEntries.UnwrapOrElse(func() int {
+		return 9
+	})
+	return rtree.New(maxEntries, b.from.UnwrapOrDefault())
+}
@@ -0,0 +1,18 @@
+package rtree
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+)
+
+// Builder defines the fluent interface for constructing R-trees.
+// Use [NewBuilder] to obtain one.
+type Builder[V any, Target collection.RTree[V], Self Builder[V, Target, Self]] interface {
+	// Build constructs and returns the target R-tree.
+	Build() Target
+	// From bulk loads the tree with the given rectangle-payload pairs.
+	From(pairs ...kv.Pair[spatial.Rect, V]) Self
+	// MaxEntries sets the most entries or children a node may hold. Default is 9.
+	MaxEntries(maxEntries int) Self
+}
@@ -0,0 +1,21 @@
+// Package rtree implements [collection.RTree].
+//
+// Rectangles passed to the builder are bulk loaded with Sort-Tile-Recursive
+// packing, which produces a shallower tree with less overlap than inserting
+// them one at a time:
+//
+//	tree := rtree.NewBuilder[string]().
+//		From(kv.New(spatial.NewRect(0, 0, 10, 10), "park")).
+//		Build()
+//	for rect, name := range tree.Search(spatial.NewRect(5, 5, 6, 6)) {
+//		...
+//	}
+//
+// [collection.RTree.Root] exposes the node bounding boxes, which is useful
+// for drawing the index while debugging.
+package rtree
+
+import (
+	_ "codeberg.org/yaadata/bina/core/collection"
+	_ "codeberg.org/yaadata/bina/core/spatial"
+)
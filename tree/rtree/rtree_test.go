@@ -0,0 +1,287 @@
+package rtree_test
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	. "codeberg.org/yaadata/opt"
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/spatial"
+	"codeberg.org/yaadata/bina/tree/rtree"
+)
+
+func values[K any, V cmp.Ordered](seq iter.Seq2[K, V]) []V {
+	var res []V
+	for _, value := range seq {
+		res = append(res, value)
+	}
+	slices.Sort(res)
+	return res
+}
+
+// assertValid walks every node and checks parent links, bounding boxes,
+// node capacity and fill, and that all leaves sit at the same depth.
+func assertValid[V any](t *testing.T, tree collection.RTree[V], maxEntries int) {
+	t.Helper()
+	leafDepth := -1
+	var count int
+	var walk func(n collection.RTreeNode[V], parent Option[collection.RTreeNode[V]], depth int)
+	walk = func(n collection.RTreeNode[V], parent Option[collection.RTreeNode[V]], depth int) {
+		must.Eq(t, parent.IsSome(), n.Parent().IsSome())
+		if parent.IsSome() {
+			must.Eq(t, parent.Unwrap().Bounds(), n.Parent().Unwrap().Bounds())
+		}
+		var rects []spatial.Rect
+		if n.IsLeaf() {
+			if leafDepth < 0 {
+				leafDepth = depth
+			}
+			must.Eq(t, leafDepth, depth)
+			for _, pair := range n.Values() {
+				rects = append(rects, pair.Key())
+			}
+			count += len(rects)
+		} else {
+			must.SliceEmpty(t, n.Values())
+			for child := range n.Children() {
+				walk(child, Some(n), depth+1)
+				rects = append(rects, child.Bounds())
+			}
+		}
+		must.Positive(t, len(rects))
+		must.LessEq(t, maxEntries, len(rects))
+		if parent.IsSome() {
+			// Every node but the root keeps at least 40% of maxEntries
+			must.GreaterEq(t, max(maxEntries*2/5, 1), len(rects))
+		}
+		bounds := rects[0]
+		for _, rect := range rects[1:] {
+			bounds = bounds.Union(rect)
+		}
+		must.Eq(t, bounds, n.Bounds())
+	}
+	if tree.Root().IsSome() {
+		walk(tree.Root().Unwrap(), None[collection.RTreeNode[V]](), 1)
+		must.Eq(t, tree.Height(), leafDepth)
+	}
+	must.Eq(t, tree.Len(), count)
+}
+
+func square(x, y, size float64) spatial.Rect {
+	return spatial.NewRect(x, y, x+size, y+size)
+}
+
+func TestRTree(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := rtree.NewBuilder[string]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, 0, tree.Height())
+		must.True(t, tree.Root().IsNone())
+		must.SliceEmpty(t, tree.Nearest(spatial.Point{}, 1))
+	})
+
+	t.Run("Can bulk load", func(t *testing.T) {
+		// ========= [A]rrange =========
+		var pairs []kv.Pair[spatial.Rect, int]
+		for i := range 1_000 {
+			pairs = append(pairs, kv.New(square(float64(i%40), float64(i/40), 0.5), i))
+		}
+		// ========= [A]ct     =========
+		tree := rtree.NewBuilder[int]().
+			From(pairs...).
+			MaxEntries(10).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 1_000, tree.Len())
+		must.Eq(t, 3, tree.Height())
+		assertValid(t, tree, 10)
+	})
+
+	t.Run("Bulk loading fills every node", func(t *testing.T) {
+		for _, maxEntries := range []int{3, 4, 5, 10} {
+			for size := 1; size <= 250; size++ {
+				// ========= [A]rrange =========
+				var pairs []kv.Pair[spatial.Rect, int]
+				for i := range size {
+					pairs = append(pairs, kv.New(square(float64(i%7), float64(i/7), 0.5), i))
+				}
+				// ========= [A]ct     =========
+				tree := rtree.NewBuilder[int]().
+					From(pairs...).
+					MaxEntries(maxEntries).
+					Build()
+				// ========= [A]ssert  =========
+				assertValid(t, tree, maxEntries)
+			}
+		}
+	})
+
+	t.Run("Queries work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := rtree.NewBuilder[string]().
+			From(
+				kv.New(spatial.NewRect(0, 0, 10, 10), "park"),
+				kv.New(spatial.NewRect(8, 8, 12, 12), "lake"),
+				kv.New(spatial.NewRect(20, 0, 30, 5), "school"),
+				kv.New(spatial.PointRect(spatial.Point{X: 15, Y: 15}), "statue"),
+			).
+			MaxEntries(3).
+			Build()
+
+		// SCENARIO: Search
+		t.Run("Search - overlap", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"lake", "park"}, values(tree.Search(spatial.NewRect(9, 9, 9.5, 9.5))))
+		})
+		t.Run("Search - touching edge", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"park", "school"}, values(tree.Search(spatial.NewRect(10, 0, 20, 1))))
+		})
+		t.Run("Search - point", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []string{"statue"}, values(tree.Search(spatial.NewRect(14, 14, 16, 16))))
+			must.SliceEmpty(t, values(tree.Search(spatial.NewRect(40, 40, 50, 50))))
+		})
+
+		// SCENARIO: Nearest
+		t.Run("Nearest", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Nearest(spatial.Point{X: 16, Y: 16}, 2)
+			// ========= [A]ssert  =========
+			must.Len(t, 2, actual)
+			must.Eq(t, "statue", actual[0].Value())
+			must.Eq(t, "lake", actual[1].Value())
+		})
+		t.Run("Nearest - inside", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, "school", tree.Nearest(spatial.Point{X: 25, Y: 2}, 1)[0].Value())
+			must.Len(t, 4, tree.Nearest(spatial.Point{}, 10))
+		})
+	})
+
+	t.Run("Delete works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := rtree.NewBuilder[string]().
+			Build()
+		tile := square(0, 0, 1)
+		tree.Insert(tile, "road")
+		tree.Insert(tile, "river")
+		tree.Insert(square(5, 5, 1), "forest")
+
+		// SCENARIO: DeleteFunc picks the payload
+		t.Run("DeleteFunc", func(t *testing.T) {
+			// ========= [A]ct     =========
+			deleted := tree.DeleteFunc(tile, func(name string) bool {
+				return name == "river"
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, "river", deleted.Unwrap())
+			must.Eq(t, []string{"road"}, values(tree.Search(tile)))
+		})
+		// SCENARIO: Delete
+		t.Run("Delete", func(t *testing.T) {
+			// ========= [A]ct     =========
+			deleted := tree.Delete(tile)
+			missing := tree.Delete(tile)
+			// ========= [A]ssert  =========
+			must.Eq(t, "road", deleted.Unwrap())
+			must.True(t, missing.IsNone())
+			must.False(t, tree.Contains(tile))
+			must.True(t, tree.Contains(square(5, 5, 1)))
+			must.Eq(t, 1, tree.Len())
+		})
+		// SCENARIO: last entry
+		t.Run("Delete - last", func(t *testing.T) {
+			// ========= [A]ct     =========
+			tree.Delete(square(5, 5, 1))
+			// ========= [A]ssert  =========
+			must.True(t, tree.IsEmpty())
+			must.True(t, tree.Root().IsNone())
+		})
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := rtree.NewBuilder[int]().
+			From(
+				kv.New(square(0, 0, 1), 1),
+				kv.New(square(0, 0, 2), 2),
+				kv.New(square(0, 0, 3), 3),
+			).
+			Build()
+		large := func(pair kv.Pair[spatial.Rect, int]) bool {
+			return pair.Key().Area() > 1
+		}
+		// ========= [A]ssert  =========
+		must.True(t, tree.Any(large))
+		must.False(t, tree.Every(large))
+		must.Eq(t, 2, tree.Count(large))
+		var total int
+		tree.ForEach(func(pair kv.Pair[spatial.Rect, int]) {
+			total += pair.Value()
+		})
+		must.Eq(t, 6, total)
+		tree.Clear()
+		must.True(t, tree.IsEmpty())
+	})
+}
+
+func TestRTreeRandomOperations(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(6, 2))
+	randomRect := func() spatial.Rect {
+		return square(float64(rng.IntN(100)), float64(rng.IntN(100)), float64(1+rng.IntN(5)))
+	}
+	tree := rtree.NewBuilder[int]().MaxEntries(4).Build()
+	expected := map[int]spatial.Rect{}
+	// ========= [A]ct     =========
+	for step := range 3_000 {
+		switch rng.IntN(3) {
+		case 0, 1:
+			rect := randomRect()
+			tree.Insert(rect, step)
+			expected[step] = rect
+		default:
+			for id, rect := range expected {
+				deleted := tree.DeleteFunc(rect, func(value int) bool {
+					return value == id
+				})
+				must.Eq(t, id, deleted.Unwrap())
+				delete(expected, id)
+				break
+			}
+		}
+		// ========= [A]ssert  =========
+		query := randomRect()
+		var want []int
+		for id, rect := range expected {
+			if rect.Intersects(query) {
+				want = append(want, id)
+			}
+		}
+		slices.Sort(want)
+		must.Eq(t, want, values(tree.Search(query)))
+	}
+	// ========= [A]ssert  =========
+	assertValid(t, tree, 4)
+	point := spatial.Point{X: 50, Y: 50}
+	distances := make([]float64, 0, len(expected))
+	for _, rect := range expected {
+		distances = append(distances, rect.Distance(point))
+	}
+	slices.Sort(distances)
+	var actual []float64
+	for _, pair := range tree.Nearest(point, 10) {
+		actual = append(actual, pair.Key().Distance(point))
+	}
+	must.Eq(t, distances[:10], actual)
+}
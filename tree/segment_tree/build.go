@@ -0,0 +1,44 @@
+package segmenttree
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	segmenttree "codeberg.org/yaadata/bina/internal/segment_tree"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.SegmentTree] that aggregates with monoid.
+func NewBuilder[T any, U any](monoid Monoid[T, U]) Builder[T, U, collection.SegmentTree[T, U], *builder[T, U]] {
+	return &builder[T, U]{
+		monoid: monoid,
+		from:   None[[]T](),
+		size:   None[int](),
+	}
+}
+
+type builder[T any, U any] struct {
+	monoid Monoid[T, U]
+	from   Option[[]T]
+	size   Option[int]
+}
+
+func (b *builder[T, U]) From(items ...T) *builder[T, U] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *builder[T, U]) Size(size int) *builder[T, U] {
+	b.size = Some(size)
+	return b
+}
+
+func (b *builder[T, U]) Build() collection.SegmentTree[T, U] {
+	items := b.from.UnwrapOrElse(func() []T {
+		items := make([]T, max(b.size.UnwrapOrDefault(), 0))
+		for i := range items {
+			items[i] = b.monoid.Identity
+		}
+		return items
+	})
+	return segmenttree.New(items, b.monoid.Combine, b.monoid.Identity, b.monoid.Apply, b.monoid.Compose)
+}
@@ -0,0 +1,17 @@
+package segmenttree
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// Builder defines the fluent interface for constructing segment trees.
+// Use [NewBuilder] to obtain one.
+type Builder[T any, U any, Target collection.SegmentTree[T, U], Self Builder[T, U, Target, Self]] interface {
+	// Build constructs and returns the target segment tree.
+	Build() Target
+	// From initializes the tree with the given items.
+	From(items ...T) Self
+	// Size initializes the tree with size copies of the monoid identity.
+	// Ignored when From is set.
+	Size(size int) Self
+}
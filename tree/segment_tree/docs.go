@@ -0,0 +1,8 @@
+// Package segmenttree implements [collection.SegmentTree].
+//
+// A tree is parameterised by a [Monoid], which says how elements combine and
+// how range updates act on them. [Sum], [Min] and [Max] cover the common
+// cases of range-add updates over numbers.
+package segmenttree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
@@ -0,0 +1,82 @@
+package segmenttree
+
+import (
+	"codeberg.org/yaadata/bina/core/numeric"
+)
+
+// Monoid describes how a [collection.SegmentTree] aggregates elements of type
+// T and how updates of type U act on them.
+type Monoid[T any, U any] struct {
+	// Combine merges two aggregates. It must be associative.
+	Combine func(a, b T) T
+	// Identity is the aggregate of an empty range: Combine(Identity, x) == x.
+	Identity T
+	// Apply returns value, the aggregate of length elements, after update
+	// has been applied to each of those elements. It must distribute over
+	// Combine.
+	Apply func(update U, value T, length int) T
+	// Compose returns the single update equivalent to applying older and
+	// then newer.
+	Compose func(newer, older U) U
+}
+
+// Sum returns a [Monoid] that aggregates range sums and adds its update to
+// every element in range.
+func Sum[T numeric.Number]() Monoid[T, T] {
+	return Monoid[T, T]{
+		Combine: func(a, b T) T {
+			return a + b
+		},
+		Identity: 0,
+		Apply: func(update T, value T, length int) T {
+			return value + update*T(length)
+		},
+		Compose: add[T],
+	}
+}
+
+// Min returns a [Monoid] that aggregates range minimums and adds its update to
+// every element in range. identity must be at least as large as any element,
+// for example math.MaxInt for int. Elements equal to identity, such as those
+// of a tree built with Size, hold no value and are left unchanged by updates.
+func Min[T numeric.Number](identity T) Monoid[T, T] {
+	return Monoid[T, T]{
+		Combine: func(a, b T) T {
+			return min(a, b)
+		},
+		Identity: identity,
+		Apply:    addUnlessIdentity(identity),
+		Compose:  add[T],
+	}
+}
+
+// Max returns a [Monoid] that aggregates range maximums and adds its update to
+// every element in range. identity must be at most as large as any element,
+// for example math.MinInt for int. Elements equal to identity, such as those
+// of a tree built with Size, hold no value and are left unchanged by updates.
+func Max[T numeric.Number](identity T) Monoid[T, T] {
+	return Monoid[T, T]{
+		Combine: func(a, b T) T {
+			return max(a, b)
+		},
+		Identity: identity,
+		Apply:    addUnlessIdentity(identity),
+		Compose:  add[T],
+	}
+}
+
+// addUnlessIdentity returns an Apply that adds the update to the aggregate,
+// unless the aggregate is identity: adding to it would wrap around, or turn
+// an empty range into one holding a value.
+func addUnlessIdentity[T numeric.Number](identity T) func(update T, value T, _ int) T {
+	return func(update T, value T, _ int) T {
+		if value == identity {
+			return value
+		}
+		return value + update
+	}
+}
+
+func add[T numeric.Number](newer, older T) T {
+	return newer + older
+}
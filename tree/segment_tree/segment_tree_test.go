@@ -0,0 +1,264 @@
+package segmenttree_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/where"
+	segmenttree "codeberg.org/yaadata/bina/tree/segment_tree"
+)
+
+func TestSegmentTreeSum(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := segmenttree.NewBuilder(segmenttree.Sum[int]()).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, 0, tree.Query())
+		must.True(t, tree.Get(0).IsNone())
+	})
+
+	t.Run("Can build with size", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := segmenttree.NewBuilder(segmenttree.Sum[int]()).
+			Size(4).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 4, tree.Len())
+		must.Eq(t, []int{0, 0, 0, 0}, slices.Collect(tree.Values()))
+	})
+
+	t.Run("Query works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := segmenttree.NewBuilder(segmenttree.Sum[int]()).
+			From(1, 2, 3, 4, 5, 6, 7, 8).
+			Build()
+
+		// SCENARIO: whole range
+		t.Run("Query - unbounded", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 36, tree.Query())
+		})
+		// SCENARIO: inclusive bounds
+		t.Run("Query - inclusive bounds", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 2+3+4, tree.Query(where.From(1), where.To(3)))
+		})
+		// SCENARIO: exclusive bounds
+		t.Run("Query - exclusive bounds", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 3+4, tree.Query(where.FromExclusive(1), where.ToExclusive(4)))
+		})
+		// SCENARIO: open ended
+		t.Run("Query - open ended", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 7+8, tree.Query(where.From(6)))
+			must.Eq(t, 1+2, tree.Query(where.To(1)))
+		})
+		// SCENARIO: empty and out-of-range bounds
+		t.Run("Query - empty range", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 0, tree.Query(where.From(5), where.ToExclusive(5)))
+			must.Eq(t, 0, tree.Query(where.From(10)))
+			must.Eq(t, 36, tree.Query(where.From(-5), where.To(50)))
+		})
+	})
+
+	t.Run("Set works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := segmenttree.NewBuilder(segmenttree.Sum[int]()).
+			From(1, 2, 3, 4).
+			Build()
+		// ========= [A]ct     =========
+		set := tree.Set(2, 10)
+		missing := tree.Set(4, 10)
+		// ========= [A]ssert  =========
+		must.True(t, set)
+		must.False(t, missing)
+		must.Eq(t, 17, tree.Query())
+		must.Eq(t, 10, tree.Get(2).Unwrap())
+	})
+
+	t.Run("Apply works", func(t *testing.T) {
+		// SCENARIO: range add
+		t.Run("Apply - range", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := segmenttree.NewBuilder(segmenttree.Sum[int]()).
+				From(1, 2, 3, 4, 5).
+				Build()
+			// ========= [A]ct     =========
+			tree.Apply(10, where.From(1), where.To(3))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 12, 13, 14, 5}, slices.Collect(tree.Values()))
+			must.Eq(t, 45, tree.Query())
+			must.Eq(t, 27, tree.Query(where.From(2), where.To(3)))
+		})
+		// SCENARIO: updates compose
+		t.Run("Apply - overlapping", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := segmenttree.NewBuilder(segmenttree.Sum[int]()).
+				Size(6).
+				Build()
+			// ========= [A]ct     =========
+			tree.Apply(1)
+			tree.Apply(2, where.To(3))
+			tree.Apply(3, where.From(2))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{3, 3, 6, 6, 4, 4}, slices.Collect(tree.Values()))
+		})
+		// SCENARIO: Set after Apply
+		t.Run("Apply - then Set", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := segmenttree.NewBuilder(segmenttree.Sum[int]()).
+				Size(4).
+				Build()
+			// ========= [A]ct     =========
+			tree.Apply(5)
+			tree.Set(1, 0)
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{5, 0, 5, 5}, slices.Collect(tree.Values()))
+		})
+	})
+}
+
+func TestSegmentTreeMinMax(t *testing.T) {
+	// SCENARIO: Min
+	t.Run("Min", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := segmenttree.NewBuilder(segmenttree.Min(math.MaxInt)).
+			From(5, 3, 8, 1, 9, 2).
+			Build()
+		// ========= [A]ct     =========
+		tree.Apply(10, where.From(3), where.To(4))
+		// ========= [A]ssert  =========
+		must.Eq(t, 2, tree.Query())
+		must.Eq(t, 3, tree.Query(where.To(3)))
+		must.Eq(t, 11, tree.Query(where.From(3), where.To(4)))
+		must.Eq(t, math.MaxInt, tree.Query(where.From(6)))
+	})
+
+	// SCENARIO: Max
+	t.Run("Max", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := segmenttree.NewBuilder(segmenttree.Max(math.Inf(-1))).
+			From(1.5, 3.5, -2, 0.5).
+			Build()
+		// ========= [A]ct     =========
+		tree.Apply(-10, where.To(1))
+		// ========= [A]ssert  =========
+		must.Eq(t, 0.5, tree.Query())
+		must.Eq(t, -6.5, tree.Query(where.To(1)))
+	})
+}
+
+func TestSegmentTreeMinMaxWithSize(t *testing.T) {
+	// SCENARIO: Min
+	t.Run("Min", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := segmenttree.NewBuilder(segmenttree.Min(math.MaxInt)).
+			Size(4).
+			Build()
+		// ========= [A]ct     =========
+		tree.Apply(1)
+		// ========= [A]ssert  =========
+		must.Eq(t, math.MaxInt, tree.Query())
+		// ========= [A]ct     =========
+		tree.Set(0, 5)
+		tree.Apply(2, where.To(1))
+		// ========= [A]ssert  =========
+		must.Eq(t, 7, tree.Query())
+		must.Eq(t, []int{7, math.MaxInt, math.MaxInt, math.MaxInt}, slices.Collect(tree.Values()))
+	})
+
+	// SCENARIO: Max
+	t.Run("Max", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := segmenttree.NewBuilder(segmenttree.Max(math.MinInt)).
+			Size(4).
+			Build()
+		// ========= [A]ct     =========
+		tree.Apply(-1)
+		// ========= [A]ssert  =========
+		must.Eq(t, math.MinInt, tree.Query())
+		// ========= [A]ct     =========
+		tree.Set(3, -5)
+		tree.Apply(-2, where.From(2))
+		// ========= [A]ssert  =========
+		must.Eq(t, -7, tree.Query())
+		must.Eq(t, math.MinInt, tree.Query(where.To(2)))
+	})
+}
+
+func TestSegmentTreeCustomMonoid(t *testing.T) {
+	// ========= [A]rrange =========
+	// Range sums with range assignment: the newer assignment always wins
+	assign := segmenttree.Monoid[int, int]{
+		Combine: func(a, b int) int {
+			return a + b
+		},
+		Identity: 0,
+		Apply: func(update, _ int, length int) int {
+			return update * length
+		},
+		Compose: func(newer, _ int) int {
+			return newer
+		},
+	}
+	tree := segmenttree.NewBuilder(assign).
+		From(1, 2, 3, 4, 5, 6).
+		Build()
+	// ========= [A]ct     =========
+	tree.Apply(7, where.From(1), where.To(4))
+	tree.Apply(0, where.From(3))
+	// ========= [A]ssert  =========
+	must.Eq(t, []int{1, 7, 7, 0, 0, 0}, slices.Collect(tree.Values()))
+	must.Eq(t, 14, tree.Query(where.From(1), where.To(3)))
+}
+
+func TestSegmentTreeRandomOperations(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(5, 8))
+	expected := make([]int, 97)
+	for i := range expected {
+		expected[i] = rng.IntN(100)
+	}
+	sum := segmenttree.NewBuilder(segmenttree.Sum[int]()).From(expected...).Build()
+	low := segmenttree.NewBuilder(segmenttree.Min(math.MaxInt)).From(expected...).Build()
+	// ========= [A]ct     =========
+	for range 2_000 {
+		from := rng.IntN(len(expected))
+		to := from + rng.IntN(len(expected)-from)
+		switch rng.IntN(3) {
+		case 0:
+			delta := rng.IntN(21) - 10
+			sum.Apply(delta, where.From(from), where.To(to))
+			low.Apply(delta, where.From(from), where.To(to))
+			for i := from; i <= to; i++ {
+				expected[i] += delta
+			}
+		case 1:
+			value := rng.IntN(100)
+			sum.Set(from, value)
+			low.Set(from, value)
+			expected[from] = value
+		default:
+			// ========= [A]ssert  =========
+			var total int
+			lowest := math.MaxInt
+			for _, value := range expected[from : to+1] {
+				total += value
+				lowest = min(lowest, value)
+			}
+			must.Eq(t, total, sum.Query(where.From(from), where.To(to)))
+			must.Eq(t, lowest, low.Query(where.From(from), where.To(to)))
+		}
+	}
+	// ========= [A]ssert  =========
+	must.Eq(t, expected, slices.Collect(sum.Values()))
+	must.Eq(t, expected, slices.Collect(low.Values()))
+}
@@ -0,0 +1,11 @@
+package splay
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/tree/builder"
+)
+
+// Builder is a [builder.BaseBuilder] for [collection.SplayTree] implementations.
+type Builder[K any, V any, Target collection.SplayTree[K, V], Self Builder[K, V, Target, Self]] interface {
+	builder.BaseBuilder[K, V, Target, Self]
+}
@@ -0,0 +1,9 @@
+// Package splay implements [collection.SplayTree].
+//
+// Reads mutate a splay tree: Get moves the key it finds to the root. Trees
+// shared between goroutines therefore need external synchronisation around
+// every Get, not only around writes. Use Peek for lookups that must leave the
+// structure unchanged.
+package splay
+
+import _ "codeberg.org/yaadata/bina/core/collection"
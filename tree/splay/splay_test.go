@@ -0,0 +1,375 @@
+package splay_test
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/where"
+	"codeberg.org/yaadata/bina/tree/splay"
+)
+
+func keys[K any, V any](seq iter.Seq2[K, V]) []K {
+	var res []K
+	for key := range seq {
+		res = append(res, key)
+	}
+	return res
+}
+
+func newTree(items ...int) collection.SplayTree[int, int] {
+	pairs := make([]kv.Pair[int, int], 0, len(items))
+	for _, item := range items {
+		pairs = append(pairs, kv.New(item, item*10))
+	}
+	return splay.NewBuiltinBuilder[int, int]().
+		From(pairs...).
+		Build()
+}
+
+// root returns the key at the root, which a pre-order traversal visits first.
+func root(tree collection.SplayTree[int, int]) int {
+	for key := range tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPreOrder)) {
+		return key
+	}
+	panic("empty tree")
+}
+
+func TestSplayTreeBuiltin(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := splay.NewBuiltinBuilder[int, int]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, 0, tree.Height())
+	})
+
+	t.Run("Can build from pairs", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(5, 3, 8, 1)
+		// ========= [A]ssert  =========
+		must.Eq(t, 4, tree.Len())
+		must.Eq(t, []int{1, 3, 5, 8}, keys(tree.All()))
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(1, 2, 3)
+
+		// SCENARIO: Contains
+		t.Run("Contains - true", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Contains(2)
+			// ========= [A]ssert  =========
+			must.True(t, actual)
+		})
+		t.Run("Contains - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Contains(4)
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: IsEmpty
+		t.Run("IsEmpty - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.IsEmpty()
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Clear()
+			// ========= [A]ssert  =========
+			must.True(t, tree.IsEmpty())
+			must.True(t, tree.Min().IsNone())
+		})
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(1, 2, 3, 4, 5)
+
+		// SCENARIO: Any
+		t.Run("Any", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.Any(func(pair kv.Pair[int, int]) bool {
+				return pair.Value() == 40
+			}))
+			must.False(t, tree.Any(func(pair kv.Pair[int, int]) bool {
+				return pair.Key() > 5
+			}))
+		})
+
+		// SCENARIO: Count
+		t.Run("Count", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Count(func(pair kv.Pair[int, int]) bool {
+				return pair.Key()%2 == 0
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, actual)
+		})
+
+		// SCENARIO: Every
+		t.Run("Every", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.Every(func(pair kv.Pair[int, int]) bool {
+				return pair.Value() == pair.Key()*10
+			}))
+			must.False(t, tree.Every(func(pair kv.Pair[int, int]) bool {
+				return pair.Key() < 5
+			}))
+		})
+
+		// SCENARIO: ForEach
+		t.Run("ForEach", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var visited []int
+			tree.ForEach(func(pair kv.Pair[int, int]) {
+				visited = append(visited, pair.Key())
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5}, visited)
+		})
+	})
+
+	t.Run("Search tree methods work", func(t *testing.T) {
+		// SCENARIO: Put / Get
+		t.Run("Put - update existing key", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Put(2, -1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, tree.Len())
+			must.Eq(t, -1, tree.Get(2).Unwrap())
+		})
+		t.Run("Get - missing key", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			actual := tree.Get(10)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsNone())
+		})
+
+		// SCENARIO: Delete
+		t.Run("Delete", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			deleted := tree.Delete(4)
+			missing := tree.Delete(4)
+			// ========= [A]ssert  =========
+			must.Eq(t, 40, deleted.Unwrap())
+			must.True(t, missing.IsNone())
+			must.Eq(t, []int{1, 2, 3, 5, 6, 7, 8, 9, 10}, keys(tree.All()))
+		})
+
+		// SCENARIO: Height
+		t.Run("Height - sorted input builds a spine", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5)
+			// ========= [A]ct     =========
+			actual := tree.Height()
+			// ========= [A]ssert  =========
+			must.Eq(t, 5, actual)
+		})
+
+		// SCENARIO: Min / Max
+		t.Run("Min and Max", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(7, 3, 9, 1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 1, tree.Min().Unwrap().Key())
+			must.Eq(t, 9, tree.Max().Unwrap().Key())
+		})
+
+		// SCENARIO: Floor / Ceiling
+		t.Run("Floor", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(10, 20, 30, 40, 50, 60)
+			// ========= [A]ssert  =========
+			must.Eq(t, 30, tree.Floor(30).Unwrap().Key())
+			must.Eq(t, 30, tree.Floor(39).Unwrap().Key())
+			must.Eq(t, 60, tree.Floor(100).Unwrap().Key())
+			must.True(t, tree.Floor(5).IsNone())
+		})
+		t.Run("Ceiling", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(10, 20, 30, 40, 50, 60)
+			// ========= [A]ssert  =========
+			must.Eq(t, 30, tree.Ceiling(30).Unwrap().Key())
+			must.Eq(t, 40, tree.Ceiling(31).Unwrap().Key())
+			must.Eq(t, 10, tree.Ceiling(0).Unwrap().Key())
+			must.True(t, tree.Ceiling(61).IsNone())
+		})
+
+		// SCENARIO: Range
+		t.Run("Range - inclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			actual := keys(tree.Range(where.From(3), where.To(7)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{3, 4, 5, 6, 7}, actual)
+		})
+		t.Run("Range - exclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			actual := keys(tree.Range(where.FromExclusive(3), where.ToExclusive(7)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{4, 5, 6}, actual)
+		})
+		t.Run("Range - early break", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			var visited []int
+			for key := range tree.Range(where.From(2)) {
+				if key > 4 {
+					break
+				}
+				visited = append(visited, key)
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 3, 4}, visited)
+		})
+
+		// SCENARIO: All
+		t.Run("All - traversal strategies", func(t *testing.T) {
+			// ========= [A]rrange =========
+			// Every insert splays the new maximum to the root, leaving a left spine
+			tree := newTree(1, 2, 3, 4, 5, 6, 7)
+			// ========= [A]ct     =========
+			inOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyInOrder)))
+			preOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPreOrder)))
+			postOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPostOrder)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5, 6, 7}, inOrder)
+			must.Eq(t, []int{7, 6, 5, 4, 3, 2, 1}, preOrder)
+			must.Eq(t, []int{1, 2, 3, 4, 5, 6, 7}, postOrder)
+		})
+	})
+
+	t.Run("Splaying works", func(t *testing.T) {
+		// SCENARIO: Get moves the key to the root
+		t.Run("Get", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7)
+			// ========= [A]ct     =========
+			actual := tree.Get(1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 10, actual.Unwrap())
+			must.Eq(t, 1, root(tree))
+			must.Eq(t, []int{1, 2, 3, 4, 5, 6, 7}, keys(tree.All()))
+		})
+		t.Run("Get - roughly halves the depth of the access path", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8)
+			// ========= [A]ct     =========
+			tree.Get(1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 5, tree.Height())
+		})
+		t.Run("Get - missing key splays the last visited node", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(10, 20, 30, 40)
+			// ========= [A]ct     =========
+			actual := tree.Get(15)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsNone())
+			must.SliceContains(t, []int{10, 20}, root(tree))
+		})
+
+		// SCENARIO: Peek leaves the tree untouched
+		t.Run("Peek", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7)
+			before := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPreOrder)))
+			// ========= [A]ct     =========
+			found := tree.Peek(1)
+			missing := tree.Peek(8)
+			// ========= [A]ssert  =========
+			must.Eq(t, 10, found.Unwrap())
+			must.True(t, missing.IsNone())
+			must.Eq(t, before, keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPreOrder))))
+		})
+
+		// SCENARIO: Put moves the key to the root
+		t.Run("Put", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7)
+			// ========= [A]ct     =========
+			tree.Put(3, 0)
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, root(tree))
+			must.Eq(t, 0, tree.Peek(3).Unwrap())
+		})
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(3, 5))
+		tree := newTree()
+		expected := map[int]bool{}
+		// ========= [A]ct     =========
+		for range 5_000 {
+			key := rng.IntN(500)
+			switch rng.IntN(3) {
+			case 0:
+				tree.Delete(key)
+				delete(expected, key)
+			case 1:
+				must.Eq(t, expected[key], tree.Get(key).IsSome())
+			default:
+				tree.Put(key, key)
+				expected[key] = true
+			}
+		}
+		// ========= [A]ssert  =========
+		var sorted []int
+		for key := range expected {
+			sorted = append(sorted, key)
+		}
+		slices.Sort(sorted)
+		must.Eq(t, sorted, keys(tree.All()))
+	})
+}
+
+func TestSplayTreeComparator(t *testing.T) {
+	// ========= [A]rrange =========
+	descending := func(a, b string) compare.Order {
+		return compare.Builtin(b, a)
+	}
+	tree := splay.NewComparatorBuilder[string, int](descending).
+		From(kv.New("a", 1), kv.New("c", 3), kv.New("b", 2), kv.New("d", 4)).
+		Build()
+
+	// SCENARIO: keys follow the comparator
+	t.Run("All", func(t *testing.T) {
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"d", "c", "b", "a"}, keys(tree.All()))
+	})
+
+	// SCENARIO: bounds follow the comparator
+	t.Run("Range", func(t *testing.T) {
+		// ========= [A]ct     =========
+		actual := keys(tree.Range(where.From("c"), where.ToExclusive("a")))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"c", "b"}, actual)
+	})
+}
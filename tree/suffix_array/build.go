@@ -0,0 +1,38 @@
+package suffixarray
+
+import (
+	"cmp"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	suffixarray "codeberg.org/yaadata/bina/internal/suffix_array"
+)
+
+// NewBuiltinBuilder returns a [Builder] for creating a [collection.SuffixArray] over ordered symbols.
+func NewBuiltinBuilder[T cmp.Ordered]() Builder[T, collection.SuffixArray[T], *comparatorBuilder[T]] {
+	return NewComparatorBuilder[T](compare.Builtin[T])
+}
+
+// NewComparatorBuilder returns a [Builder] for creating a [collection.SuffixArray] whose symbols are ordered by fn.
+func NewComparatorBuilder[T any](fn func(a, b T) compare.Order) Builder[T, collection.SuffixArray[T], *comparatorBuilder[T]] {
+	return &comparatorBuilder[T]{
+		compare: fn,
+		from:    None[[]T](),
+	}
+}
+
+type comparatorBuilder[T any] struct {
+	compare func(a, b T) compare.Order
+	from    Option[[]T]
+}
+
+func (b *comparatorBuilder[T]) From(text ...T) *comparatorBuilder[T] {
+	b.from = Some(text)
+	return b
+}
+
+func (b *comparatorBuilder[T]) Build() collection.SuffixArray[T] {
+	return suffixarray.New(b.compare, b.from.UnwrapOrDefault())
+}
@@ -0,0 +1,14 @@
+package suffixarray
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// Builder defines the fluent interface for constructing suffix arrays.
+// Use [NewBuiltinBuilder] or [NewComparatorBuilder] to obtain one.
+type Builder[T any, Target collection.SuffixArray[T], Self Builder[T, Target, Self]] interface {
+	// Build constructs and returns the target suffix array in O(n log n).
+	Build() Target
+	// From sets the text to index.
+	From(text ...T) Self
+}
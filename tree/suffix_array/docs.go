@@ -0,0 +1,14 @@
+// Package suffixarray implements [collection.SuffixArray].
+//
+// The array is generic over the symbol type, so the same code indexes bytes,
+// runes or any other ordered tokens:
+//
+//	index := suffixarray.NewBuiltinBuilder[byte]().
+//		From([]byte(corpus)...).
+//		Build()
+//	for offset := range index.Lookup([]byte("timeout")) {
+//		...
+//	}
+package suffixarray
+
+import _ "codeberg.org/yaadata/bina/core/collection"
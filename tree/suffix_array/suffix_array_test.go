@@ -0,0 +1,145 @@
+package suffixarray_test
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/compare"
+	suffixarray "codeberg.org/yaadata/bina/tree/suffix_array"
+)
+
+func TestSuffixArrayBytes(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		index := suffixarray.NewBuiltinBuilder[byte]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, index.Len())
+		must.SliceEmpty(t, index.Suffixes())
+		must.False(t, index.Contains([]byte("a")))
+		must.SliceEmpty(t, index.LongestRepeatedSubstring())
+	})
+
+	t.Run("Builds suffixes and LCP", func(t *testing.T) {
+		// ========= [A]rrange =========
+		index := suffixarray.NewBuiltinBuilder[byte]().
+			From([]byte("banana")...).
+			Build()
+		// ========= [A]ssert  =========
+		// a, ana, anana, banana, na, nana
+		must.Eq(t, []int{5, 3, 1, 0, 4, 2}, index.Suffixes())
+		must.Eq(t, []int{0, 1, 3, 0, 0, 2}, index.LCP())
+	})
+
+	t.Run("Queries work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		index := suffixarray.NewBuiltinBuilder[byte]().
+			From([]byte("abracadabra")...).
+			Build()
+
+		// SCENARIO: Lookup
+		t.Run("Lookup", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{0, 7}, slices.Collect(index.Lookup([]byte("abra"))))
+			must.Eq(t, []int{0, 3, 5, 7, 10}, slices.Collect(index.Lookup([]byte("a"))))
+			must.SliceEmpty(t, slices.Collect(index.Lookup([]byte("abrac!"))))
+		})
+		// SCENARIO: Count
+		t.Run("Count", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, index.Count([]byte("bra")))
+			must.Eq(t, 0, index.Count([]byte("abracadabras")))
+		})
+		// SCENARIO: the empty pattern occurs at every position, the end included
+		t.Run("Empty pattern", func(t *testing.T) {
+			// ========= [A]rrange =========
+			empty := suffixarray.NewBuiltinBuilder[byte]().
+				Build()
+			// ========= [A]ssert  =========
+			must.Eq(t, 12, index.Count(nil))
+			must.Eq(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, slices.Collect(index.Lookup([]byte{})))
+			must.True(t, index.Contains(nil))
+			must.Eq(t, 1, empty.Count(nil))
+			must.Eq(t, []int{0}, slices.Collect(empty.Lookup(nil)))
+			must.True(t, empty.Contains(nil))
+		})
+		// SCENARIO: Contains
+		t.Run("Contains", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, index.Contains([]byte("cad")))
+			must.False(t, index.Contains([]byte("dac")))
+		})
+		// SCENARIO: LongestRepeatedSubstring
+		t.Run("LongestRepeatedSubstring", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, "abra", string(index.LongestRepeatedSubstring()))
+		})
+		// SCENARIO: LongestCommonSubstring
+		t.Run("LongestCommonSubstring", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.Eq(t, "cadab", string(index.LongestCommonSubstring([]byte("xxcadabxx"))))
+			must.SliceEmpty(t, index.LongestCommonSubstring([]byte("xyz")))
+		})
+	})
+}
+
+func TestSuffixArrayRunes(t *testing.T) {
+	// ========= [A]rrange =========
+	index := suffixarray.NewBuiltinBuilder[rune]().
+		From([]rune("日本語の日本")...).
+		Build()
+	// ========= [A]ssert  =========
+	must.Eq(t, []int{0, 4}, slices.Collect(index.Lookup([]rune("日本"))))
+	must.Eq(t, "日本", string(index.LongestRepeatedSubstring()))
+}
+
+func TestSuffixArrayComparator(t *testing.T) {
+	// ========= [A]rrange =========
+	// Case-insensitive tokens
+	index := suffixarray.NewComparatorBuilder(func(a, b string) compare.Order {
+		return compare.Builtin(strings.ToLower(a), strings.ToLower(b))
+	}).
+		From(strings.Fields("GET /a OK get /b ERR Get /a OK")...).
+		Build()
+	// ========= [A]ssert  =========
+	must.Eq(t, 3, index.Count([]string{"get"}))
+	must.Eq(t, []int{0, 6}, slices.Collect(index.Lookup([]string{"get", "/a"})))
+}
+
+func TestSuffixArrayRandomText(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(1, 5))
+	text := make([]byte, 2_000)
+	for i := range text {
+		text[i] = "abc"[rng.IntN(3)]
+	}
+	index := suffixarray.NewBuiltinBuilder[byte]().From(text...).Build()
+	// ========= [A]ssert  =========
+	suffixes := index.Suffixes()
+	lcp := index.LCP()
+	for r := 1; r < len(suffixes); r++ {
+		previous, current := text[suffixes[r-1]:], text[suffixes[r]:]
+		must.Negative(t, bytes.Compare(previous, current))
+		var common int
+		for common < min(len(previous), len(current)) && previous[common] == current[common] {
+			common++
+		}
+		must.Eq(t, common, lcp[r])
+	}
+	for range 200 {
+		start := rng.IntN(len(text))
+		pattern := text[start:min(start+1+rng.IntN(6), len(text))]
+		var want []int
+		for i := range text {
+			if bytes.HasPrefix(text[i:], pattern) {
+				want = append(want, i)
+			}
+		}
+		must.Eq(t, want, slices.Collect(index.Lookup(pattern)))
+	}
+}
@@ -0,0 +1,35 @@
+package suffixtree
+
+import (
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	suffixtree "codeberg.org/yaadata/bina/internal/suffix_tree"
+)
+
+// NewBuilder returns a [Builder] for creating a [collection.SuffixTree] over comparable symbols.
+func NewBuilder[T comparable]() Builder[T, collection.SuffixTree[T], *builder[T]] {
+	return &builder[T]{
+		from: None[[][]T](),
+	}
+}
+
+type builder[T comparable] struct {
+	from Option[[][]T]
+}
+
+func (b *builder[T]) From(strings ...[]T) *builder[T] {
+	b.from = Some(strings)
+	return b
+}
+
+func (b *builder[T]) Build() collection.SuffixTree[T] {
+	resp := suffixtree.New[T]()
+	for _, symbols := range b.from.UnwrapOrDefault() {
+		for _, symbol := range symbols {
+			resp.Append(symbol)
+		}
+		resp.Terminate()
+	}
+	return resp
+}
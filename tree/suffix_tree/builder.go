@@ -0,0 +1,14 @@
+package suffixtree
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+)
+
+// Builder defines the fluent interface for constructing suffix trees.
+// Use [NewBuilder] to obtain one.
+type Builder[T any, Target collection.SuffixTree[T], Self Builder[T, Target, Self]] interface {
+	// Build constructs and returns the target suffix tree.
+	Build() Target
+	// From initializes the tree with the given strings, terminating each one.
+	From(strings ...[]T) Self
+}
@@ -0,0 +1,17 @@
+// Package suffixtree implements [collection.SuffixTree].
+//
+// The tree is built online with Ukkonen's algorithm, so text can be streamed
+// in and queried between appends. Several strings can share one tree by
+// terminating each before appending the next:
+//
+//	tree := suffixtree.NewBuilder[byte]().Build()
+//	for _, b := range []byte("GET /health") {
+//		tree.Append(b)
+//	}
+//	tree.Terminate()
+//	for index, offset := range tree.Occurrences([]byte("health")) {
+//		...
+//	}
+package suffixtree
+
+import _ "codeberg.org/yaadata/bina/core/collection"
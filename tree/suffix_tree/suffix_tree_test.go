@@ -0,0 +1,174 @@
+package suffixtree_test
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	suffixtree "codeberg.org/yaadata/bina/tree/suffix_tree"
+)
+
+type occurrence struct {
+	index  int
+	offset int
+}
+
+func occurrences(seq iter.Seq2[int, int]) []occurrence {
+	var res []occurrence
+	for index, offset := range seq {
+		res = append(res, occurrence{index: index, offset: offset})
+	}
+	return res
+}
+
+// suffixes walks the tree and rebuilds the suffix ending at every leaf.
+func suffixes[T any](t *testing.T, node collection.SuffixTreeNode[T], prefix []T) map[occurrence][]T {
+	t.Helper()
+	res := map[occurrence][]T{}
+	path := append(slices.Clone(prefix), node.Label()...)
+	if node.IsLeaf() {
+		suffix := node.Suffix().Unwrap()
+		res[occurrence{index: suffix.Key(), offset: suffix.Value()}] = path
+	}
+	for child := range node.Children() {
+		must.True(t, child.Parent().Unwrap() == node)
+		for at, suffix := range suffixes(t, child, path) {
+			res[at] = suffix
+		}
+	}
+	return res
+}
+
+func TestSuffixTree(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := suffixtree.NewBuilder[byte]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, 0, tree.Strings())
+		must.True(t, tree.Root().Parent().IsNone())
+		must.False(t, tree.Contains([]byte("a")))
+		must.True(t, tree.Contains(nil))
+	})
+
+	t.Run("Can build from strings", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := suffixtree.NewBuilder[byte]().
+			From([]byte("banana"), []byte("bandana")).
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 13, tree.Len())
+		must.Eq(t, 2, tree.Strings())
+		must.Eq(t, []occurrence{{0, 1}, {0, 3}, {1, 1}, {1, 4}}, occurrences(tree.Occurrences([]byte("an"))))
+		must.Eq(t, []occurrence{{1, 3}}, occurrences(tree.Occurrences([]byte("dan"))))
+		must.SliceEmpty(t, occurrences(tree.Occurrences([]byte("nab"))))
+	})
+
+	t.Run("Queries work while appending", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := suffixtree.NewBuilder[byte]().
+			Build()
+		// ========= [A]ct     =========
+		for _, b := range []byte("abcab") {
+			tree.Append(b)
+		}
+		// ========= [A]ssert  =========
+		// "ab" at offset 3 is still an implicit suffix
+		must.Eq(t, []occurrence{{0, 0}, {0, 3}}, occurrences(tree.Occurrences([]byte("ab"))))
+		must.True(t, tree.Contains([]byte("bca")))
+		must.False(t, tree.Contains([]byte("abcabc")))
+
+		// SCENARIO: later appends extend earlier matches
+		t.Run("Append", func(t *testing.T) {
+			// ========= [A]ct     =========
+			tree.Append('c')
+			// ========= [A]ssert  =========
+			must.True(t, tree.Contains([]byte("abcabc")))
+			must.Eq(t, []occurrence{{0, 0}, {0, 3}}, occurrences(tree.Occurrences([]byte("abc"))))
+		})
+		// SCENARIO: Terminate starts a new string
+		t.Run("Terminate", func(t *testing.T) {
+			// ========= [A]ct     =========
+			index := tree.Terminate()
+			tree.Append('c')
+			tree.Append('a')
+			// ========= [A]ssert  =========
+			must.Eq(t, 0, index)
+			must.Eq(t, 1, tree.Strings())
+			must.Eq(t, []occurrence{{0, 2}, {1, 0}}, occurrences(tree.Occurrences([]byte("ca"))))
+			// Matches never span two strings
+			must.False(t, tree.Contains([]byte("bcc")))
+		})
+	})
+
+	t.Run("Nodes expose every suffix", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := suffixtree.NewBuilder[rune]().
+			From([]rune("mississippi"), []rune("ssi")).
+			Build()
+		// ========= [A]ct     =========
+		actual := suffixes(t, tree.Root(), nil)
+		// ========= [A]ssert  =========
+		must.MapLen(t, 11+3+2, actual)
+		must.Eq(t, []rune("issippi"), actual[occurrence{0, 4}])
+		must.Eq(t, []rune("si"), actual[occurrence{1, 1}])
+		// Suffixes holding only a terminator are empty
+		must.SliceEmpty(t, actual[occurrence{1, 3}])
+	})
+
+	t.Run("Early termination works", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := suffixtree.NewBuilder[byte]().
+			From([]byte("aaaa")).
+			Build()
+		var seen []int
+		// ========= [A]ct     =========
+		for _, offset := range tree.Occurrences([]byte("a")) {
+			seen = append(seen, offset)
+			if len(seen) == 2 {
+				break
+			}
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, []int{0, 1}, seen)
+	})
+}
+
+func TestSuffixTreeRandomText(t *testing.T) {
+	// ========= [A]rrange =========
+	rng := rand.New(rand.NewPCG(3, 9))
+	tree := suffixtree.NewBuilder[byte]().Build()
+	texts := []string{""}
+	// ========= [A]ct     =========
+	for range 600 {
+		if rng.IntN(40) == 0 {
+			tree.Terminate()
+			texts = append(texts, "")
+		} else {
+			b := "ab"[rng.IntN(2)]
+			tree.Append(b)
+			texts[len(texts)-1] += string(b)
+		}
+		// ========= [A]ssert  =========
+		pattern := make([]byte, 1+rng.IntN(4))
+		for i := range pattern {
+			pattern[i] = "ab"[rng.IntN(2)]
+		}
+		var want []occurrence
+		for index, text := range texts {
+			for offset := range len(text) {
+				if strings.HasPrefix(text[offset:], string(pattern)) {
+					want = append(want, occurrence{index: index, offset: offset})
+				}
+			}
+		}
+		must.Eq(t, want, occurrences(tree.Occurrences(pattern)))
+		must.Eq(t, len(want) > 0, tree.Contains(pattern))
+	}
+}
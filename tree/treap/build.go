@@ -0,0 +1,97 @@
+package treap
+
+import (
+	"cmp"
+	"math/rand/v2"
+
+	. "codeberg.org/yaadata/opt"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/internal/treap"
+)
+
+// NewBuiltinBuilder returns a [Builder] for creating a keyed treap with ordered keys.
+func NewBuiltinBuilder[K cmp.Ordered, V any]() Builder[K, V, collection.SearchTree[K, V], *comparatorBuilder[K, V]] {
+	return NewComparatorBuilder[K, V](compare.Builtin[K])
+}
+
+// NewComparatorBuilder returns a [Builder] for creating a keyed treap whose keys are ordered by fn.
+func NewComparatorBuilder[K any, V any](fn func(a, b K) compare.Order) Builder[K, V, collection.SearchTree[K, V], *comparatorBuilder[K, V]] {
+	return &comparatorBuilder[K, V]{
+		compare: fn,
+		seed:    None[uint64](),
+		from:    None[[]kv.Pair[K, V]](),
+	}
+}
+
+type comparatorBuilder[K any, V any] struct {
+	compare func(a, b K) compare.Order
+	seed    Option[uint64]
+	from    Option[[]kv.Pair[K, V]]
+}
+
+func (b *comparatorBuilder[K, V]) From(items ...kv.Pair[K, V]) *comparatorBuilder[K, V] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *comparatorBuilder[K, V]) Seed(seed uint64) *comparatorBuilder[K, V] {
+	b.seed = Some(seed)
+	return b
+}
+
+func (b *comparatorBuilder[K, V]) Build() collection.SearchTree[K, V] {
+	resp := treap.NewKeyed[K, V](b.compare, b.seed.UnwrapOrElse(rand.Uint64))
+	for _, pair := range b.from.UnwrapOrDefault() {
+		resp.Put(pair.Key(), pair.Value())
+	}
+	return resp
+}
+
+// NewImplicitBuiltinBuilder returns an [ImplicitBuilder] for creating a [collection.ImplicitTreap] with comparable elements.
+func NewImplicitBuiltinBuilder[T comparable]() ImplicitBuilder[T, collection.ImplicitTreap[T], *implicitBuilder[T]] {
+	return &implicitBuilder[T]{
+		equal: func(a, b T) bool {
+			return a == b
+		},
+		seed: None[uint64](),
+		from: None[[]T](),
+	}
+}
+
+// NewImplicitComparableInterfaceBuilder returns an [ImplicitBuilder] for creating a [collection.ImplicitTreap] with [compare.Comparable] elements.
+func NewImplicitComparableInterfaceBuilder[T compare.Comparable[T]]() ImplicitBuilder[T, collection.ImplicitTreap[T], *implicitBuilder[T]] {
+	return &implicitBuilder[T]{
+		equal: func(a, b T) bool {
+			return a.Equal(b)
+		},
+		seed: None[uint64](),
+		from: None[[]T](),
+	}
+}
+
+type implicitBuilder[T any] struct {
+	equal func(a, b T) bool
+	seed  Option[uint64]
+	from  Option[[]T]
+}
+
+func (b *implicitBuilder[T]) From(items ...T) *implicitBuilder[T] {
+	b.from = Some(items)
+	return b
+}
+
+func (b *implicitBuilder[T]) Seed(seed uint64) *implicitBuilder[T] {
+	b.seed = Some(seed)
+	return b
+}
+
+func (b *implicitBuilder[T]) Build() collection.ImplicitTreap[T] {
+	resp := treap.NewImplicit[T](b.equal, b.seed.UnwrapOrElse(rand.Uint64))
+	for _, item := range b.from.UnwrapOrDefault() {
+		resp.Insert(resp.Len(), item)
+	}
+	return resp
+}
@@ -0,0 +1,23 @@
+package treap
+
+import (
+	"codeberg.org/yaadata/bina/core/collection"
+	sequence "codeberg.org/yaadata/bina/sequence/builder"
+	"codeberg.org/yaadata/bina/tree/builder"
+)
+
+// Builder is a [builder.BaseBuilder] for keyed treaps.
+type Builder[K any, V any, Target collection.SearchTree[K, V], Self Builder[K, V, Target, Self]] interface {
+	builder.BaseBuilder[K, V, Target, Self]
+	// Seed sets the seed of the priority generator. Default is a random seed.
+	Seed(seed uint64) Self
+}
+
+// ImplicitBuilder is a [sequence.BaseBuilder] for [collection.ImplicitTreap] implementations.
+type ImplicitBuilder[T any, Target collection.ImplicitTreap[T], Self ImplicitBuilder[T, Target, Self]] interface {
+	sequence.BaseBuilder[T, Target, Self]
+	// From initializes the treap with the given items.
+	From(items ...T) Self
+	// Seed sets the seed of the priority generator. Default is a random seed.
+	Seed(seed uint64) Self
+}
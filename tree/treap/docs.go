@@ -0,0 +1,8 @@
+// Package treap implements a keyed treap satisfying [collection.SearchTree]
+// and an implicit treap satisfying [collection.ImplicitTreap].
+//
+// Both draw node priorities from a seedable generator. Builders pick a random
+// seed by default; pass Seed to make tree shapes reproducible in tests.
+package treap
+
+import _ "codeberg.org/yaadata/bina/core/collection"
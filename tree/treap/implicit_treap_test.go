@@ -0,0 +1,382 @@
+package treap_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/where"
+	"codeberg.org/yaadata/bina/tree/treap"
+)
+
+type ComparableInt int
+
+func (c ComparableInt) Equal(other ComparableInt) bool {
+	return c == other
+}
+
+func newSequence(items ...int) collection.ImplicitTreap[int] {
+	return treap.NewImplicitBuiltinBuilder[int]().
+		Seed(42).
+		From(items...).
+		Build()
+}
+
+func TestImplicitTreapBuiltin(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		sequence := treap.NewImplicitBuiltinBuilder[int]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, sequence.Len())
+		must.True(t, sequence.IsEmpty())
+	})
+
+	t.Run("Can build from items", func(t *testing.T) {
+		// ========= [A]rrange =========
+		sequence := newSequence(5, 3, 8, 1)
+		// ========= [A]ssert  =========
+		must.Eq(t, 4, sequence.Len())
+		must.Eq(t, []int{5, 3, 8, 1}, slices.Collect(sequence.Values()))
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		sequence := newSequence(1, 2, 3)
+
+		// SCENARIO: Contains
+		t.Run("Contains - true", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, sequence.Contains(2))
+		})
+		t.Run("Contains - false", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.False(t, sequence.Contains(4))
+		})
+
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 3)
+			// ========= [A]ct     =========
+			sequence.Clear()
+			// ========= [A]ssert  =========
+			must.True(t, sequence.IsEmpty())
+			must.True(t, sequence.Get(0).IsNone())
+		})
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		sequence := newSequence(1, 2, 3, 4, 5)
+
+		// SCENARIO: Any
+		t.Run("Any", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, sequence.Any(func(item int) bool {
+				return item == 4
+			}))
+			must.False(t, sequence.Any(func(item int) bool {
+				return item > 5
+			}))
+		})
+
+		// SCENARIO: Count
+		t.Run("Count", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := sequence.Count(func(item int) bool {
+				return item%2 == 1
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, actual)
+		})
+
+		// SCENARIO: Every
+		t.Run("Every", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, sequence.Every(func(item int) bool {
+				return item > 0
+			}))
+			must.False(t, sequence.Every(func(item int) bool {
+				return item < 5
+			}))
+		})
+
+		// SCENARIO: ForEach
+		t.Run("ForEach", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var visited []int
+			sequence.ForEach(func(item int) {
+				visited = append(visited, item)
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5}, visited)
+		})
+	})
+
+	t.Run("Sequence methods work", func(t *testing.T) {
+		// SCENARIO: All
+		t.Run("All", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(10, 20, 30)
+			// ========= [A]ct     =========
+			var indices, items []int
+			for index, item := range sequence.All() {
+				indices = append(indices, index)
+				items = append(items, item)
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{0, 1, 2}, indices)
+			must.Eq(t, []int{10, 20, 30}, items)
+		})
+
+		// SCENARIO: Find / FindIndex
+		t.Run("Find", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 4, 6, 9)
+			// ========= [A]ct     =========
+			found := sequence.Find(func(item int) bool {
+				return item%2 == 0
+			})
+			index := sequence.FindIndex(func(item int) bool {
+				return item > 5
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 4, found.Unwrap())
+			must.Eq(t, 2, index.Unwrap())
+		})
+
+		// SCENARIO: Get
+		t.Run("Get", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(10, 20, 30)
+			// ========= [A]ssert  =========
+			must.Eq(t, 10, sequence.Get(0).Unwrap())
+			must.Eq(t, 30, sequence.Get(2).Unwrap())
+			must.True(t, sequence.Get(3).IsNone())
+			must.True(t, sequence.Get(-1).IsNone())
+		})
+
+		// SCENARIO: Retain
+		t.Run("Retain", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 3, 4, 5, 6)
+			// ========= [A]ct     =========
+			sequence.Retain(func(item int) bool {
+				return item%2 == 0
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 4, 6}, slices.Collect(sequence.Values()))
+		})
+
+		// SCENARIO: Sort
+		t.Run("Sort", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(5, 3, 8, 1)
+			// ========= [A]ct     =========
+			sequence.Sort(compare.Builtin[int])
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 3, 5, 8}, slices.Collect(sequence.Values()))
+		})
+	})
+
+	t.Run("Dynamic sequence methods work", func(t *testing.T) {
+		// SCENARIO: Insert
+		t.Run("Insert", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 4)
+			// ========= [A]ct     =========
+			front := sequence.Insert(0, 0)
+			middle := sequence.Insert(3, 3)
+			end := sequence.Insert(5, 5)
+			// ========= [A]ssert  =========
+			must.True(t, front)
+			must.True(t, middle)
+			must.True(t, end)
+			must.Eq(t, []int{0, 1, 2, 3, 4, 5}, slices.Collect(sequence.Values()))
+		})
+		t.Run("Cannot Insert", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 4)
+			// ========= [A]ct     =========
+			inserted := sequence.Insert(10, 3)
+			// ========= [A]ssert  =========
+			must.False(t, inserted)
+			must.Eq(t, 3, sequence.Len())
+		})
+
+		// SCENARIO: RemoveAt
+		t.Run("RemoveAt", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 3, 4)
+			// ========= [A]ct     =========
+			removed := sequence.RemoveAt(2)
+			missing := sequence.RemoveAt(3)
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, removed.Unwrap())
+			must.True(t, missing.IsNone())
+			must.Eq(t, []int{1, 2, 4}, slices.Collect(sequence.Values()))
+		})
+	})
+
+	t.Run("Implicit treap methods work", func(t *testing.T) {
+		// SCENARIO: Split
+		t.Run("Split", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 3, 4, 5)
+			// ========= [A]ct     =========
+			rest := sequence.Split(2)
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2}, slices.Collect(sequence.Values()))
+			must.Eq(t, []int{3, 4, 5}, slices.Collect(rest.Values()))
+		})
+		t.Run("Split - index past the end", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 3)
+			// ========= [A]ct     =========
+			rest := sequence.Split(10)
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, sequence.Len())
+			must.True(t, rest.IsEmpty())
+		})
+
+		// SCENARIO: Concat
+		t.Run("Concat", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2)
+			other := newSequence(3, 4, 5)
+			// ========= [A]ct     =========
+			sequence.Concat(other)
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5}, slices.Collect(sequence.Values()))
+			must.True(t, other.IsEmpty())
+		})
+		t.Run("Concat - undoes Split", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 3, 4, 5)
+			rest := sequence.Split(3)
+			// ========= [A]ct     =========
+			sequence.Concat(rest)
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5}, slices.Collect(sequence.Values()))
+		})
+
+		// SCENARIO: Reverse
+		t.Run("Reverse - whole sequence", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(1, 2, 3, 4, 5)
+			// ========= [A]ct     =========
+			sequence.Reverse()
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{5, 4, 3, 2, 1}, slices.Collect(sequence.Values()))
+			must.Eq(t, 5, sequence.Get(0).Unwrap())
+		})
+		t.Run("Reverse - inclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(0, 1, 2, 3, 4, 5, 6)
+			// ========= [A]ct     =========
+			sequence.Reverse(where.From(1), where.To(4))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{0, 4, 3, 2, 1, 5, 6}, slices.Collect(sequence.Values()))
+		})
+		t.Run("Reverse - exclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(0, 1, 2, 3, 4, 5, 6)
+			// ========= [A]ct     =========
+			sequence.Reverse(where.FromExclusive(1), where.ToExclusive(5))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{0, 1, 4, 3, 2, 5, 6}, slices.Collect(sequence.Values()))
+		})
+		t.Run("Reverse - bounds at the int limits", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(0, 1, 2, 3, 4, 5, 6)
+			// ========= [A]ct     =========
+			sequence.Reverse(where.FromExclusive(math.MaxInt))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{0, 1, 2, 3, 4, 5, 6}, slices.Collect(sequence.Values()))
+			// ========= [A]ct     =========
+			sequence.Reverse(where.From(2), where.To(math.MaxInt))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{0, 1, 6, 5, 4, 3, 2}, slices.Collect(sequence.Values()))
+			// ========= [A]ct     =========
+			sequence.Reverse(where.FromExclusive(math.MinInt), where.ToExclusive(math.MaxInt))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 3, 4, 5, 6, 1, 0}, slices.Collect(sequence.Values()))
+			// ========= [A]ct     =========
+			sequence.Reverse(where.To(math.MinInt))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 3, 4, 5, 6, 1, 0}, slices.Collect(sequence.Values()))
+		})
+		t.Run("Reverse - overlapping ranges", func(t *testing.T) {
+			// ========= [A]rrange =========
+			sequence := newSequence(0, 1, 2, 3, 4, 5, 6)
+			// ========= [A]ct     =========
+			sequence.Reverse(where.To(3))
+			sequence.Reverse(where.From(2))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{3, 2, 6, 5, 4, 0, 1}, slices.Collect(sequence.Values()))
+			must.Eq(t, 4, sequence.Get(4).Unwrap())
+		})
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(9, 4))
+		sequence := newSequence()
+		var expected []int
+		// ========= [A]ct     =========
+		for i := range 3_000 {
+			switch rng.IntN(4) {
+			case 0:
+				if len(expected) > 0 {
+					index := rng.IntN(len(expected))
+					must.Eq(t, expected[index], sequence.RemoveAt(index).Unwrap())
+					expected = slices.Delete(expected, index, index+1)
+				}
+			case 1:
+				from := rng.IntN(len(expected) + 1)
+				to := rng.IntN(len(expected) + 1)
+				sequence.Reverse(where.From(from), where.ToExclusive(to))
+				if from < to {
+					slices.Reverse(expected[from:to])
+				}
+			default:
+				index := rng.IntN(len(expected) + 1)
+				sequence.Insert(index, i)
+				expected = slices.Insert(expected, index, i)
+			}
+		}
+		// ========= [A]ssert  =========
+		must.Eq(t, expected, slices.Collect(sequence.Values()))
+		for index, item := range expected {
+			must.Eq(t, item, sequence.Get(index).Unwrap())
+		}
+	})
+}
+
+func TestImplicitTreapComparableInterface(t *testing.T) {
+	// ========= [A]rrange =========
+	sequence := treap.NewImplicitComparableInterfaceBuilder[ComparableInt]().
+		From(1, 2, 3).
+		Build()
+
+	// SCENARIO: Contains uses Equal
+	t.Run("Contains", func(t *testing.T) {
+		// ========= [A]ssert  =========
+		must.True(t, sequence.Contains(2))
+		must.False(t, sequence.Contains(4))
+	})
+
+	// SCENARIO: Reverse
+	t.Run("Reverse", func(t *testing.T) {
+		// ========= [A]ct     =========
+		sequence.Reverse()
+		// ========= [A]ssert  =========
+		must.Eq(t, []ComparableInt{3, 2, 1}, slices.Collect(sequence.Values()))
+	})
+}
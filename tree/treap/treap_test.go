@@ -0,0 +1,339 @@
+package treap_test
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/shoenig/test/must"
+
+	"codeberg.org/yaadata/bina/core/collection"
+	"codeberg.org/yaadata/bina/core/compare"
+	"codeberg.org/yaadata/bina/core/kv"
+	"codeberg.org/yaadata/bina/core/where"
+	"codeberg.org/yaadata/bina/tree/treap"
+)
+
+func keys[K any, V any](seq iter.Seq2[K, V]) []K {
+	var res []K
+	for key := range seq {
+		res = append(res, key)
+	}
+	return res
+}
+
+func newTree(items ...int) collection.SearchTree[int, int] {
+	pairs := make([]kv.Pair[int, int], 0, len(items))
+	for _, item := range items {
+		pairs = append(pairs, kv.New(item, item*10))
+	}
+	return treap.NewBuiltinBuilder[int, int]().
+		Seed(42).
+		From(pairs...).
+		Build()
+}
+
+func TestTreapBuiltin(t *testing.T) {
+	t.Run("Can build", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := treap.NewBuiltinBuilder[int, int]().
+			Build()
+		// ========= [A]ssert  =========
+		must.Eq(t, 0, tree.Len())
+		must.Eq(t, 0, tree.Height())
+	})
+
+	t.Run("Can build from pairs", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(5, 3, 8, 1)
+		// ========= [A]ssert  =========
+		must.Eq(t, 4, tree.Len())
+		must.Eq(t, []int{1, 3, 5, 8}, keys(tree.All()))
+	})
+
+	t.Run("Collection methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(1, 2, 3)
+
+		// SCENARIO: Contains
+		t.Run("Contains - true", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Contains(2)
+			// ========= [A]ssert  =========
+			must.True(t, actual)
+		})
+		t.Run("Contains - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Contains(4)
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: IsEmpty
+		t.Run("IsEmpty - false", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.IsEmpty()
+			// ========= [A]ssert  =========
+			must.False(t, actual)
+		})
+
+		// SCENARIO: Clear
+		t.Run("Clear", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Clear()
+			// ========= [A]ssert  =========
+			must.True(t, tree.IsEmpty())
+			must.True(t, tree.Min().IsNone())
+		})
+	})
+
+	t.Run("Aggregate methods work", func(t *testing.T) {
+		// ========= [A]rrange =========
+		tree := newTree(1, 2, 3, 4, 5)
+
+		// SCENARIO: Any
+		t.Run("Any", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.Any(func(pair kv.Pair[int, int]) bool {
+				return pair.Value() == 40
+			}))
+			must.False(t, tree.Any(func(pair kv.Pair[int, int]) bool {
+				return pair.Key() > 5
+			}))
+		})
+
+		// SCENARIO: Count
+		t.Run("Count", func(t *testing.T) {
+			// ========= [A]ct     =========
+			actual := tree.Count(func(pair kv.Pair[int, int]) bool {
+				return pair.Key()%2 == 0
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, 2, actual)
+		})
+
+		// SCENARIO: Every
+		t.Run("Every", func(t *testing.T) {
+			// ========= [A]ssert  =========
+			must.True(t, tree.Every(func(pair kv.Pair[int, int]) bool {
+				return pair.Value() == pair.Key()*10
+			}))
+			must.False(t, tree.Every(func(pair kv.Pair[int, int]) bool {
+				return pair.Key() < 5
+			}))
+		})
+
+		// SCENARIO: ForEach
+		t.Run("ForEach", func(t *testing.T) {
+			// ========= [A]ct     =========
+			var visited []int
+			tree.ForEach(func(pair kv.Pair[int, int]) {
+				visited = append(visited, pair.Key())
+			})
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5}, visited)
+		})
+	})
+
+	t.Run("Search tree methods work", func(t *testing.T) {
+		// SCENARIO: Put / Get
+		t.Run("Put - update existing key", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			tree.Put(2, -1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 3, tree.Len())
+			must.Eq(t, -1, tree.Get(2).Unwrap())
+		})
+		t.Run("Get - missing key", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3)
+			// ========= [A]ct     =========
+			actual := tree.Get(10)
+			// ========= [A]ssert  =========
+			must.True(t, actual.IsNone())
+		})
+
+		// SCENARIO: Delete
+		t.Run("Delete", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			deleted := tree.Delete(4)
+			missing := tree.Delete(4)
+			// ========= [A]ssert  =========
+			must.Eq(t, 40, deleted.Unwrap())
+			must.True(t, missing.IsNone())
+			must.Eq(t, []int{1, 2, 3, 5, 6, 7, 8, 9, 10}, keys(tree.All()))
+		})
+
+		// SCENARIO: Height stays logarithmic for sorted input
+		t.Run("Height", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := treap.NewBuiltinBuilder[int, int]().Seed(7).Build()
+			// ========= [A]ct     =========
+			for i := range 1023 {
+				tree.Put(i, i)
+			}
+			// ========= [A]ssert  =========
+			must.LessEq(t, 40, tree.Height())
+		})
+
+		// SCENARIO: Min / Max
+		t.Run("Min and Max", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(7, 3, 9, 1)
+			// ========= [A]ssert  =========
+			must.Eq(t, 1, tree.Min().Unwrap().Key())
+			must.Eq(t, 9, tree.Max().Unwrap().Key())
+		})
+
+		// SCENARIO: Floor / Ceiling
+		t.Run("Floor", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(10, 20, 30, 40, 50, 60)
+			// ========= [A]ssert  =========
+			must.Eq(t, 30, tree.Floor(30).Unwrap().Key())
+			must.Eq(t, 30, tree.Floor(39).Unwrap().Key())
+			must.Eq(t, 60, tree.Floor(100).Unwrap().Key())
+			must.True(t, tree.Floor(5).IsNone())
+		})
+		t.Run("Ceiling", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(10, 20, 30, 40, 50, 60)
+			// ========= [A]ssert  =========
+			must.Eq(t, 30, tree.Ceiling(30).Unwrap().Key())
+			must.Eq(t, 40, tree.Ceiling(31).Unwrap().Key())
+			must.Eq(t, 10, tree.Ceiling(0).Unwrap().Key())
+			must.True(t, tree.Ceiling(61).IsNone())
+		})
+
+		// SCENARIO: Range
+		t.Run("Range - inclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			actual := keys(tree.Range(where.From(3), where.To(7)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{3, 4, 5, 6, 7}, actual)
+		})
+		t.Run("Range - exclusive bounds", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			actual := keys(tree.Range(where.FromExclusive(3), where.ToExclusive(7)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{4, 5, 6}, actual)
+		})
+		t.Run("Range - early break", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			// ========= [A]ct     =========
+			var visited []int
+			for key := range tree.Range(where.From(2)) {
+				if key > 4 {
+					break
+				}
+				visited = append(visited, key)
+			}
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{2, 3, 4}, visited)
+		})
+
+		// SCENARIO: All
+		t.Run("All - traversal strategies", func(t *testing.T) {
+			// ========= [A]rrange =========
+			tree := newTree(4, 2, 6, 1, 3, 5, 7)
+			// ========= [A]ct     =========
+			inOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyInOrder)))
+			preOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPreOrder)))
+			postOrder := keys(tree.All(collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPostOrder)))
+			// ========= [A]ssert  =========
+			must.Eq(t, []int{1, 2, 3, 4, 5, 6, 7}, inOrder)
+			must.SliceContainsAll(t, inOrder, preOrder)
+			must.SliceContainsAll(t, inOrder, postOrder)
+			must.Eq(t, preOrder[0], postOrder[len(postOrder)-1])
+		})
+	})
+
+	t.Run("Seed works", func(t *testing.T) {
+		// SCENARIO: equal seeds give equal shapes
+		t.Run("Same seed", func(t *testing.T) {
+			// ========= [A]rrange =========
+			first := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			second := newTree(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			preOrder := collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPreOrder)
+			// ========= [A]ssert  =========
+			must.Eq(t, keys(first.All(preOrder)), keys(second.All(preOrder)))
+			must.Eq(t, first.Height(), second.Height())
+		})
+
+		// SCENARIO: different seeds give different shapes
+		t.Run("Different seed", func(t *testing.T) {
+			// ========= [A]rrange =========
+			build := func(seed uint64) collection.SearchTree[int, int] {
+				tree := treap.NewBuiltinBuilder[int, int]().Seed(seed).Build()
+				for i := range 100 {
+					tree.Put(i, i)
+				}
+				return tree
+			}
+			preOrder := collection.WithSearchTreeStrategy(collection.SearchTreeStrategyPreOrder)
+			// ========= [A]ssert  =========
+			must.NotEq(t, keys(build(1).All(preOrder)), keys(build(2).All(preOrder)))
+		})
+	})
+
+	t.Run("Random operations stay consistent", func(t *testing.T) {
+		// ========= [A]rrange =========
+		rng := rand.New(rand.NewPCG(3, 5))
+		tree := newTree()
+		expected := map[int]bool{}
+		// ========= [A]ct     =========
+		for range 5_000 {
+			key := rng.IntN(500)
+			if rng.IntN(3) == 0 {
+				tree.Delete(key)
+				delete(expected, key)
+			} else {
+				tree.Put(key, key)
+				expected[key] = true
+			}
+		}
+		// ========= [A]ssert  =========
+		var sorted []int
+		for key := range expected {
+			sorted = append(sorted, key)
+		}
+		slices.Sort(sorted)
+		must.Eq(t, sorted, keys(tree.All()))
+	})
+}
+
+func TestTreapComparator(t *testing.T) {
+	// ========= [A]rrange =========
+	descending := func(a, b string) compare.Order {
+		return compare.Builtin(b, a)
+	}
+	tree := treap.NewComparatorBuilder[string, int](descending).
+		From(kv.New("a", 1), kv.New("c", 3), kv.New("b", 2), kv.New("d", 4)).
+		Build()
+
+	// SCENARIO: keys follow the comparator
+	t.Run("All", func(t *testing.T) {
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"d", "c", "b", "a"}, keys(tree.All()))
+	})
+
+	// SCENARIO: bounds follow the comparator
+	t.Run("Range", func(t *testing.T) {
+		// ========= [A]ct     =========
+		actual := keys(tree.Range(where.From("c"), where.ToExclusive("a")))
+		// ========= [A]ssert  =========
+		must.Eq(t, []string{"c", "b"}, actual)
+	})
+}